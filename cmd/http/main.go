@@ -6,12 +6,16 @@ import (
 
 	"github.com/tommjj/go-blog-api/internal/adapter/http"
 	"github.com/tommjj/go-blog-api/internal/adapter/http/handler"
+	"github.com/tommjj/go-blog-api/internal/adapter/mailer"
+	"github.com/tommjj/go-blog-api/internal/adapter/oidc"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/pwned"
 	"github.com/tommjj/go-blog-api/internal/adapter/storage/redis"
 	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite"
 	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite/repository"
 	"github.com/tommjj/go-blog-api/internal/config"
 	"github.com/tommjj/go-blog-api/internal/core/auth"
 	"github.com/tommjj/go-blog-api/internal/core/cache"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
 	"github.com/tommjj/go-blog-api/internal/core/service"
 	"github.com/tommjj/go-blog-api/internal/logger"
 )
@@ -29,6 +33,11 @@ import (
 //	@in							header
 //	@name						Authorization
 //	@description				Type "Bearer" followed by a space and the access token.
+//
+//	@securityDefinitions.apikey	ApiKeyAuth
+//	@in							header
+//	@name						Authorization
+//	@description				Type "ApiKey" followed by a space and the api key, only accepted by endpoints that list it.
 func main() {
 	config, err := config.New()
 	fatalOnError(err)
@@ -52,34 +61,160 @@ func main() {
 	// repository
 	userRepo := repository.NewUserRepository(db)
 	blogRepo := repository.NewBlogRepository(db)
+	blogRevisionRepo := repository.NewBlogRevisionRepository(db)
+	actionTokenRepo := repository.NewActionTokenRepository(db)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(db)
+	passkeyRepo := repository.NewPasskeyRepository(db)
+	userIdentityRepo := repository.NewUserIdentityRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	auditLogRepo := repository.NewAuditLogRepository(db)
+	reactionRepo := repository.NewReactionRepository(db)
+	bookmarkRepo := repository.NewBookmarkRepository(db)
+	followRepo := repository.NewFollowRepository(db)
+	analyticsRepo := repository.NewAnalyticsRepository(db)
 
 	// cache
 	userCache := cache.NewUserCache(redis, time.Hour)
 	blogCache := cache.NewBlogCache(redis, time.Hour, time.Minute*2, time.Minute*2)
+	contentCache := cache.NewContentCache(redis, time.Hour*24)
+	feedCacheDuration, err := time.ParseDuration(config.Feed.CacheDuration)
+	fatalOnError(err)
+	feedCache := cache.NewFeedCache(redis, feedCacheDuration)
+	sitemapCacheDuration, err := time.ParseDuration(config.Sitemap.CacheDuration)
+	fatalOnError(err)
+	sitemapCache := cache.NewSitemapCache(redis, sitemapCacheDuration)
+	reactionCounterDuration, err := time.ParseDuration(config.Reaction.CounterDuration)
+	fatalOnError(err)
+	reactionCache := cache.NewReactionCache(redis, reactionCounterDuration)
+	timelineCache := cache.NewTimelineCache(redis, time.Minute*2)
+	viewWindow, err := time.ParseDuration(config.Analytics.ViewWindow)
+	fatalOnError(err)
+	viewCache := cache.NewViewCache(redis, viewWindow)
+
+	// breached passwords
+	var breachedRepo ports.IBreachedPasswordRepository
+	if config.Password.BreachedFile != "" {
+		breachedRepo, err = pwned.New(config.Password.BreachedFile)
+		fatalOnError(err)
+	}
+
+	// mailer
+	mailer, err := mailer.New(*config.Mail)
+	fatalOnError(err)
+
+	// identity providers
+	oidcProviders, err := oidc.NewProviders(context.Background(), *config.OIDC)
+	fatalOnError(err)
 
 	// service
 	tokenService, err := auth.NewJWTTokenService(*config.Auth)
 	fatalOnError(err)
 
-	authService := service.NewAuthService(tokenService, userRepo)
-	userService := service.NewUserService(userRepo, userCache)
-	blogService := service.NewBlogService(blogRepo, blogCache)
+	passwordPolicy := auth.NewPasswordPolicy(*config.Password, breachedRepo)
+
+	mfaService := service.NewMFAService(*config.Auth, userRepo, userCache, recoveryCodeRepo, redis)
+	apiKeyService := service.NewAPIKeyService(*config.Auth, apiKeyRepo)
+	authService, err := service.NewAuthService(*config.Auth, tokenService, userRepo, userCache, mfaService, apiKeyService, redis)
+	fatalOnError(err)
+	emailVerificationService, err := service.NewEmailVerificationService(*config.Auth, userRepo, userCache, actionTokenRepo, mailer)
+	fatalOnError(err)
+	userService := service.NewUserService(userRepo, userCache, passwordPolicy, emailVerificationService, blogCache, sitemapCache, feedCache)
+	passwordResetService, err := service.NewPasswordResetService(*config.Auth, userRepo, userCache, actionTokenRepo, passwordPolicy, mailer)
+	fatalOnError(err)
+	adminService := service.NewAdminService(tokenService, userRepo, userCache, auditLogRepo, passwordResetService, sitemapCache, feedCache)
+	passkeyService, err := service.NewPasskeyService(*config.WebAuthn, tokenService, userRepo, passkeyRepo, redis)
+	fatalOnError(err)
+	magicLinkService, err := service.NewMagicLinkService(*config.Auth, tokenService, userRepo, userCache, actionTokenRepo, redis, mailer)
+	fatalOnError(err)
+	oidcService, err := service.NewOIDCService(*config.OIDC, oidcProviders, tokenService, userRepo, userIdentityRepo, redis)
+	fatalOnError(err)
+	blogService := service.NewBlogService(blogRepo, blogRevisionRepo, blogCache, sitemapCache, feedCache)
+	contentService := service.NewContentService(contentCache)
+	feedService, err := service.NewFeedService(*config.Feed, *config.Site, blogService, userService, contentService, feedCache)
+	fatalOnError(err)
+	sitemapService, err := service.NewSitemapService(*config.Sitemap, *config.Site, blogService, sitemapCache)
+	fatalOnError(err)
+	reactionService, err := service.NewReactionService(*config.Reaction, reactionRepo, reactionCache, blogService)
+	fatalOnError(err)
+	bookmarkService := service.NewBookmarkService(bookmarkRepo, blogService)
+	followService := service.NewFollowService(followRepo, userService, blogService, timelineCache)
+	analyticsService, err := service.NewAnalyticsService(*config.Analytics, *config.Site, analyticsRepo, viewCache)
+	fatalOnError(err)
+	trashService, err := service.NewTrashService(*config.Trash, userRepo, blogRepo)
+	fatalOnError(err)
+
+	// make the configured users admins
+	err = adminService.PromoteAdmins(context.Background(), config.Auth.AdminUsernames)
+	fatalOnError(err)
+
+	// blogs written before slugs existed get one
+	err = blogService.AssignSlugs(context.Background())
+	fatalOnError(err)
+
+	// permanently delete what stayed in the trash past the retention period
+	go trashService.RunPurgeJob(context.Background())
+
+	// save the reaction counters to the database
+	go reactionService.RunReconcileJob(context.Background())
+
+	// save the buffered views to the daily views
+	go analyticsService.RunFlushJob(context.Background())
 
 	// auth handler
-	authHandler := handler.NewAuthHandler(authService)
+	authHandler := handler.NewAuthHandler(authService, passwordResetService, emailVerificationService)
+
+	// mfa handler
+	mfaHandler := handler.NewMFAHandler(mfaService)
+
+	// magic link handler
+	magicLinkHandler := handler.NewMagicLinkHandler(magicLinkService)
+
+	// oidc handler
+	oidcHandler := handler.NewOIDCHandler(oidcService)
+
+	// api key handler
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+
+	// passkey handler
+	passkeyHandler := handler.NewPasskeyHandler(passkeyService)
+
+	// admin handler
+	adminHandler := handler.NewAdminHandler(adminService)
 
 	// user handler
 	userHandler := handler.NewUserHandler(userService)
 
+	// bookmark handler
+	bookmarkHandler := handler.NewBookmarkHandler(bookmarkService)
+
+	// follow handler
+	followHandler := handler.NewFollowHandler(followService)
+
+	// feed handler
+	feedHandler := handler.NewFeedHandler(feedService)
+
+	// sitemap handler
+	sitemapHandler := handler.NewSitemapHandler(sitemapService)
+
 	// blog handler
-	BlogHandler := handler.NewBlogHandler(blogService)
+	BlogHandler := handler.NewBlogHandler(blogService, contentService, reactionService, analyticsService, config.Http.RequireIfMatch)
 
 	r, err := http.New(config.Http,
 		http.Group("/v1/api",
-			http.RegisterAuthRoute(authHandler),
-			http.RegisterUserRoute(tokenService, userHandler),
-			http.RegisterBlogRoute(tokenService, BlogHandler),
+			http.RegisterAuthRoute(authService, authHandler),
+			http.RegisterMagicLinkRoute(magicLinkHandler),
+			http.RegisterOIDCRoute(oidcHandler),
+			http.RegisterMFARoute(authService, mfaHandler),
+			http.RegisterPasskeyRoute(authService, passkeyHandler),
+			http.RegisterAPIKeyRoute(authService, apiKeyHandler),
+			http.RegisterAdminRoute(authService, adminHandler),
+			http.RegisterUserRoute(authService, userHandler),
+			http.RegisterBookmarkRoute(authService, bookmarkHandler),
+			http.RegisterFollowRoute(authService, followHandler),
+			http.RegisterBlogRoute(authService, BlogHandler, config.Auth.RequireVerifiedEmail),
+			http.RegisterFeedRoute(feedHandler),
 		),
+		http.RegisterSitemapRoute(sitemapHandler),
 	)
 	fatalOnError(err)
 
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/redis"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite/repository"
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/cache"
+	"github.com/tommjj/go-blog-api/internal/core/service"
+	"github.com/tommjj/go-blog-api/internal/logger"
+)
+
+const usage = `Usage: maintenance <command> [flags]
+
+Commands:
+  backfill-metadata [-all]  compute the excerpt, word count and reading time of the blogs written before they existed,
+                            of every blog with -all
+`
+
+// Maintenance commands of the go blog api, run with the configuration of the api
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	command := os.Args[1]
+	if command != "backfill-metadata" {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%v", command, usage)
+		os.Exit(2)
+	}
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	all := flags.Bool("all", false, "compute the metadata of every blog again")
+	_ = flags.Parse(os.Args[2:])
+
+	config, err := config.New()
+	fatalOnError(err)
+
+	// setup logger
+	err = logger.Set(*config.Logger)
+	fatalOnError(err)
+	defer logger.Sync()
+
+	// database
+	db, err := sqlite.New(*config.DB)
+	fatalOnError(err)
+
+	//redis
+	redis, err := redis.New(context.Background(), *config.Redis)
+	fatalOnError(err)
+	defer redis.Close()
+
+	// repository
+	blogRepo := repository.NewBlogRepository(db)
+	blogRevisionRepo := repository.NewBlogRevisionRepository(db)
+
+	// cache
+	blogCache := cache.NewBlogCache(redis, time.Hour, time.Minute*2, time.Minute*2)
+	sitemapCacheDuration, err := time.ParseDuration(config.Sitemap.CacheDuration)
+	fatalOnError(err)
+	sitemapCache := cache.NewSitemapCache(redis, sitemapCacheDuration)
+	feedCacheDuration, err := time.ParseDuration(config.Feed.CacheDuration)
+	fatalOnError(err)
+	feedCache := cache.NewFeedCache(redis, feedCacheDuration)
+
+	// service
+	blogService := service.NewBlogService(blogRepo, blogRevisionRepo, blogCache, sitemapCache, feedCache)
+
+	// blogs written before the excerpt, word count and reading time existed get them
+	count, err := blogService.BackfillMetadata(context.Background(), *all)
+	fatalOnError(err)
+	logger.Infof("backfill-metadata: %v blogs updated", count)
+}
+
+func fatalOnError(err error) {
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+}
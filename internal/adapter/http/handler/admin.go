@@ -0,0 +1,357 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+type AdminHandler struct {
+	svc ports.IAdminService
+}
+
+func NewAdminHandler(adminService ports.IAdminService) *AdminHandler {
+	return &AdminHandler{
+		svc: adminService,
+	}
+}
+
+type listUsersRequest struct {
+	Name          string     `form:"name" binding:"max=24" example:"lap"`
+	CreatedAfter  *time.Time `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00" example:"2024-01-01T00:00:00Z"`
+	CreatedBefore *time.Time `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00" example:"2025-01-01T00:00:00Z"`
+	Role          string     `form:"role" binding:"omitempty,oneof=user admin" example:"user"`
+	Status        string     `form:"status" binding:"omitempty,oneof=active suspended" example:"active"`
+	Skip          int        `form:"skip" binding:"min=0" example:"0"`
+	Limit         int        `form:"limit" binding:"min=1,max=100" example:"20"`
+}
+
+// ListUsers go-blog
+//
+//	@Summary		List users
+//	@Description	List and filter users, newest first. Admin only.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			name			query		string								false	"Username prefix"
+//	@Param			created_after	query		string								false	"Created at or after"	format(date-time)
+//	@Param			created_before	query		string								false	"Created before"		format(date-time)
+//	@Param			role			query		string								false	"Role"					Enums(user, admin)
+//	@Param			status			query		string								false	"Status"				Enums(active, suspended)
+//	@Param			skip			query		int									false	"Skip"					default(0)	minimum(0)
+//	@Param			limit			query		int									false	"Limit"					default(20)	minimum(1)	maximum(100)
+//	@Success		200				{object}	response{data=listUsersResponse}	"Users"
+//	@Failure		400				{object}	errorResponse						"Validation error"
+//	@Failure		401				{object}	errorResponse						"Unauthorized error"
+//	@Failure		403				{object}	errorResponse						"Forbidden error"
+//	@Failure		500				{object}	errorResponse						"Internal server error"
+//	@Router			/admin/users [get]
+//	@Security		BearerAuth
+func (ah *AdminHandler) ListUsers(ctx *gin.Context) {
+	req := listUsersRequest{
+		Limit: 20,
+	}
+	err := ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	users, total, err := ah.svc.ListUsers(ctx, domain.UserFilter{
+		NamePrefix:    req.Name,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+		Role:          domain.UserRole(req.Role),
+		Status:        domain.UserStatus(req.Status),
+	}, req.Skip, req.Limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]adminUserResponse, 0, len(users))
+	for i := range users {
+		res = append(res, newAdminUserResponse(&users[i]))
+	}
+
+	meta := newMeta(total, req.Limit, req.Skip)
+	handleSuccess(ctx, newListUsersResponse(meta, res))
+}
+
+type adminActionRequest struct {
+	Reason string `json:"reason" binding:"required,max=255" example:"support ticket #1234"`
+}
+
+// SuspendUser go-blog
+//
+//	@Summary		Suspend a user
+//	@Description	Suspends a user, its tokens, api keys and logins are rejected until it is reactivated. Admins can not be suspended. Admin only.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string								true	"User id"	format(uuid)
+//	@Param			request	body		adminActionRequest					true	"Reason recorded in the audit log"
+//	@Success		200		{object}	response{data=adminUserResponse}	"User suspended"
+//	@Failure		400		{object}	errorResponse						"Validation error or already suspended"
+//	@Failure		401		{object}	errorResponse						"Unauthorized error"
+//	@Failure		403		{object}	errorResponse						"Forbidden error"
+//	@Failure		404		{object}	errorResponse						"Data not found error"
+//	@Failure		500		{object}	errorResponse						"Internal server error"
+//	@Router			/admin/users/{id}/suspend [post]
+//	@Security		BearerAuth
+func (ah *AdminHandler) SuspendUser(ctx *gin.Context) {
+	ah.setStatus(ctx, ah.svc.SuspendUser)
+}
+
+// ReactivateUser go-blog
+//
+//	@Summary		Reactivate a user
+//	@Description	Reactivates a suspended user. Admin only.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string								true	"User id"	format(uuid)
+//	@Param			request	body		adminActionRequest					true	"Reason recorded in the audit log"
+//	@Success		200		{object}	response{data=adminUserResponse}	"User reactivated"
+//	@Failure		400		{object}	errorResponse						"Validation error or not suspended"
+//	@Failure		401		{object}	errorResponse						"Unauthorized error"
+//	@Failure		403		{object}	errorResponse						"Forbidden error"
+//	@Failure		404		{object}	errorResponse						"Data not found error"
+//	@Failure		500		{object}	errorResponse						"Internal server error"
+//	@Router			/admin/users/{id}/reactivate [post]
+//	@Security		BearerAuth
+func (ah *AdminHandler) ReactivateUser(ctx *gin.Context) {
+	ah.setStatus(ctx, ah.svc.ReactivateUser)
+}
+
+// setStatus bind an admin action on a user and apply it with fn
+func (ah *AdminHandler) setStatus(ctx *gin.Context, fn func(ctx context.Context, adminID, id uuid.UUID, reason string) (*domain.User, error)) {
+	id, req, ok := bindAdminAction(ctx)
+	if !ok {
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	user, err := fn(ctx, token.ID, id, req.Reason)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newAdminUserResponse(user)
+	handleSuccess(ctx, res)
+}
+
+// ForcePasswordReset go-blog
+//
+//	@Summary		Force a password reset
+//	@Description	Revokes the password and every token of a user and emails it a password reset link. Admin only.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string				true	"User id"	format(uuid)
+//	@Param			request	body		adminActionRequest	true	"Reason recorded in the audit log"
+//	@Success		200		{object}	response			"Password reset forced"
+//	@Failure		400		{object}	errorResponse		"Validation error or user without email"
+//	@Failure		401		{object}	errorResponse		"Unauthorized error"
+//	@Failure		403		{object}	errorResponse		"Forbidden error"
+//	@Failure		404		{object}	errorResponse		"Data not found error"
+//	@Failure		500		{object}	errorResponse		"Internal server error"
+//	@Router			/admin/users/{id}/password-reset [post]
+//	@Security		BearerAuth
+func (ah *AdminHandler) ForcePasswordReset(ctx *gin.Context) {
+	id, req, ok := bindAdminAction(ctx)
+	if !ok {
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	err := ah.svc.ForcePasswordReset(ctx, token.ID, id, req.Reason)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+// Impersonate go-blog
+//
+//	@Summary		Impersonate a user
+//	@Description	Returns a short-lived access token to act as a user for support, the impersonation is recorded in the audit log. Admins can not be impersonated. Admin only.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"User id"	format(uuid)
+//	@Param			request	body		adminActionRequest			true	"Reason recorded in the audit log"
+//	@Success		200		{object}	response{data=authResponse}	"Impersonation token"
+//	@Failure		400		{object}	errorResponse				"Validation error"
+//	@Failure		401		{object}	errorResponse				"Unauthorized error"
+//	@Failure		403		{object}	errorResponse				"Forbidden error or suspended user"
+//	@Failure		404		{object}	errorResponse				"Data not found error"
+//	@Failure		500		{object}	errorResponse				"Internal server error"
+//	@Router			/admin/users/{id}/impersonate [post]
+//	@Security		BearerAuth
+func (ah *AdminHandler) Impersonate(ctx *gin.Context) {
+	id, req, ok := bindAdminAction(ctx)
+	if !ok {
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	impersonationToken, err := ah.svc.Impersonate(ctx, token.ID, id, req.Reason)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newAuthResponse(impersonationToken)
+	handleSuccess(ctx, res)
+}
+
+type listAuditLogsRequest struct {
+	TargetID string `form:"target_id" binding:"omitempty,uuid" example:"39833b12-a044-46f5-8abd-47c47345d458"`
+	Skip     int    `form:"skip" binding:"min=0" example:"0"`
+	Limit    int    `form:"limit" binding:"min=1,max=100" example:"20"`
+}
+
+// ListAuditLogs go-blog
+//
+//	@Summary		List audit logs
+//	@Description	List the admin actions on users, newest first. Admin only.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			target_id	query		string									false	"User id the actions were applied to"	format(uuid)
+//	@Param			skip		query		int										false	"Skip"									default(0)	minimum(0)
+//	@Param			limit		query		int										false	"Limit"									default(20)	minimum(1)	maximum(100)
+//	@Success		200			{object}	response{data=listAuditLogsResponse}	"Audit logs"
+//	@Failure		400			{object}	errorResponse							"Validation error"
+//	@Failure		401			{object}	errorResponse							"Unauthorized error"
+//	@Failure		403			{object}	errorResponse							"Forbidden error"
+//	@Failure		500			{object}	errorResponse							"Internal server error"
+//	@Router			/admin/audit-logs [get]
+//	@Security		BearerAuth
+func (ah *AdminHandler) ListAuditLogs(ctx *gin.Context) {
+	req := listAuditLogsRequest{
+		Limit: 20,
+	}
+	err := ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	var targetID *uuid.UUID
+	if req.TargetID != "" {
+		id := uuid.MustParse(req.TargetID)
+		targetID = &id
+	}
+
+	logs, total, err := ah.svc.ListAuditLogs(ctx, targetID, req.Skip, req.Limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]auditLogResponse, 0, len(logs))
+	for i := range logs {
+		res = append(res, newAuditLogResponse(&logs[i]))
+	}
+
+	meta := newMeta(total, req.Limit, req.Skip)
+	handleSuccess(ctx, newListAuditLogsResponse(meta, res))
+}
+
+type listDeletedUsersRequest struct {
+	Skip  int `form:"skip" binding:"min=0" example:"0"`
+	Limit int `form:"limit" binding:"min=1,max=100" example:"20"`
+}
+
+// ListDeletedUsers go-blog
+//
+//	@Summary		List deleted users
+//	@Description	List the users in the trash, last deleted first. Admin only.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			skip	query		int									false	"Skip"	default(0)	minimum(0)
+//	@Param			limit	query		int									false	"Limit"	default(20)	minimum(1)	maximum(100)
+//	@Success		200		{object}	response{data=listUsersResponse}	"Deleted users"
+//	@Failure		400		{object}	errorResponse						"Validation error"
+//	@Failure		401		{object}	errorResponse						"Unauthorized error"
+//	@Failure		403		{object}	errorResponse						"Forbidden error"
+//	@Failure		500		{object}	errorResponse						"Internal server error"
+//	@Router			/admin/trash/users [get]
+//	@Security		BearerAuth
+func (ah *AdminHandler) ListDeletedUsers(ctx *gin.Context) {
+	req := listDeletedUsersRequest{
+		Limit: 20,
+	}
+	err := ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	users, total, err := ah.svc.ListDeletedUsers(ctx, req.Skip, req.Limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]adminUserResponse, 0, len(users))
+	for i := range users {
+		res = append(res, newAdminUserResponse(&users[i]))
+	}
+
+	meta := newMeta(total, req.Limit, req.Skip)
+	handleSuccess(ctx, newListUsersResponse(meta, res))
+}
+
+// RestoreUser go-blog
+//
+//	@Summary		Restore a deleted user
+//	@Description	Takes a user out of the trash with the blogs deleted along with it. Admin only.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string								true	"User id"	format(uuid)
+//	@Param			request	body		adminActionRequest					true	"Reason recorded in the audit log"
+//	@Success		200		{object}	response{data=adminUserResponse}	"User restored"
+//	@Failure		400		{object}	errorResponse						"Validation error"
+//	@Failure		401		{object}	errorResponse						"Unauthorized error"
+//	@Failure		403		{object}	errorResponse						"Forbidden error"
+//	@Failure		404		{object}	errorResponse						"Data not found error"
+//	@Failure		500		{object}	errorResponse						"Internal server error"
+//	@Router			/admin/users/{id}/restore [post]
+//	@Security		BearerAuth
+func (ah *AdminHandler) RestoreUser(ctx *gin.Context) {
+	ah.setStatus(ctx, ah.svc.RestoreUser)
+}
+
+// bindAdminAction bind the user id path param and the body of an admin action,
+// write the validation error and return false if they are invalid
+func bindAdminAction(ctx *gin.Context) (uuid.UUID, *adminActionRequest, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, err)
+		return uuid.Nil, nil, false
+	}
+
+	var req adminActionRequest
+	err = ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return uuid.Nil, nil, false
+	}
+
+	return id, &req, true
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+)
+
+// recordView count the read of a blog, the visitor is known by its ip and user agent
+func (bh *BlogHandler) recordView(ctx *gin.Context, blog *domain.Blog) {
+	bh.views.RecordView(ctx, &domain.BlogView{
+		BlogID:    blog.ID,
+		IP:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+		Referrer:  ctx.Request.Referer(),
+		ViewedAt:  time.Now(),
+	})
+}
+
+type getBlogStatsRequest struct {
+	Days int `form:"days" binding:"min=1,max=365" example:"30"`
+}
+
+// GetBlogStats go-blog
+//
+//	@Summary		get blog stats
+//	@Description	get the views per day of a blog of the current user over the last days, today included, and its top 20 referrers.
+//	@Description	A visitor is counted once per view window, bots are not counted, the views show once they are flushed
+//	@Tags			blogs
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string								true	"Blog id"	format(uuid)
+//	@Param			days	query		int									false	"Days"		default(30)	minimum(1)	maximum(365)
+//	@Success		200		{object}	response{data=blogStatsResponse}	"Blog stats"
+//	@Failure		400		{object}	errorResponse						"Validation error"
+//	@Failure		401		{object}	errorResponse						"Unauthorized error"
+//	@Failure		403		{object}	errorResponse						"Forbidden error"
+//	@Failure		404		{object}	errorResponse						"Data not found error"
+//	@Failure		500		{object}	errorResponse						"Internal server error"
+//	@Router			/blogs/{id}/stats [get]
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+func (bh *BlogHandler) GetBlogStats(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	req := getBlogStatsRequest{
+		Days: 30,
+	}
+	err = ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	if !bh.authorizeAuthor(ctx, id) {
+		return
+	}
+
+	stats, err := bh.views.GetBlogStats(ctx, id, req.Days)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newBlogStatsResponse(stats)
+	handleSuccess(ctx, res)
+}
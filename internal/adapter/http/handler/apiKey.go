@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+type APIKeyHandler struct {
+	svc ports.IAPIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService ports.IAPIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		svc: apiKeyService,
+	}
+}
+
+type createAPIKeyRequest struct {
+	Name          string               `json:"name" binding:"required,max=64" example:"release notes CI"`
+	Scopes        []domain.APIKeyScope `json:"scopes" binding:"required,min=1,dive,oneof=blogs:write" example:"blogs:write"`
+	ExpiresInDays int                  `json:"expires_in_days" binding:"min=0,max=365" example:"90"`
+}
+
+// CreateAPIKey go-blog
+//
+//	@Summary		Create an api key
+//	@Description	Creates a named api key with the given scopes for the current user, send it as "ApiKey <key>" in the Authorization header. The key is only returned here, it expires after expires_in_days or never when omitted.
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		createAPIKeyRequest						true	"Create api key request body"
+//	@Success		200		{object}	response{data=createdAPIKeyResponse}	"Api key created"
+//	@Failure		400		{object}	errorResponse							"Validation error"
+//	@Failure		401		{object}	errorResponse							"Unauthorized error"
+//	@Failure		500		{object}	errorResponse							"Internal server error"
+//	@Router			/auth/api-keys [post]
+//	@Security		BearerAuth
+func (ah *APIKeyHandler) CreateAPIKey(ctx *gin.Context) {
+	var req createAPIKeyRequest
+
+	err := ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	expiresIn := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+
+	key, rawKey, err := ah.svc.CreateKey(ctx, token.ID, req.Name, req.Scopes, expiresIn)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newCreatedAPIKeyResponse(key, rawKey)
+	handleSuccess(ctx, res)
+}
+
+// GetAPIKeys go-blog
+//
+//	@Summary		List api keys
+//	@Description	List the api keys of the current user, the keys themselves are never returned again.
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response{data=[]apiKeyResponse}	"Api keys"
+//	@Failure		401	{object}	errorResponse					"Unauthorized error"
+//	@Failure		500	{object}	errorResponse					"Internal server error"
+//	@Router			/auth/api-keys [get]
+//	@Security		BearerAuth
+func (ah *APIKeyHandler) GetAPIKeys(ctx *gin.Context) {
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	keys, err := ah.svc.GetKeys(ctx, token.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]apiKeyResponse, 0, len(keys))
+	for i := range keys {
+		res = append(res, newAPIKeyResponse(&keys[i]))
+	}
+
+	handleSuccess(ctx, res)
+}
+
+// RevokeAPIKey go-blog
+//
+//	@Summary		Revoke an api key
+//	@Description	Revoke an api key of the current user, requests using it are rejected from now on.
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string			true	"Api key id"	format(uuid)
+//	@Success		200	{object}	response		"Api key revoked"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/auth/api-keys/{id} [delete]
+//	@Security		BearerAuth
+func (ah *APIKeyHandler) RevokeAPIKey(ctx *gin.Context) {
+	paramId := ctx.Param("id")
+
+	id, err := uuid.Parse(paramId)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	err = ah.svc.RevokeKey(ctx, token.ID, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+// RevokeAllAPIKeys go-blog
+//
+//	@Summary		Revoke all api keys
+//	@Description	Revoke every api key of the current user.
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response		"Api keys revoked"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/auth/api-keys [delete]
+//	@Security		BearerAuth
+func (ah *APIKeyHandler) RevokeAllAPIKeys(ctx *gin.Context) {
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	err := ah.svc.RevokeAllKeys(ctx, token.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
@@ -6,24 +6,34 @@ import (
 )
 
 type AuthHandler struct {
-	svc ports.IAuthService
+	svc       ports.IAuthService
+	resetSvc  ports.IPasswordResetService
+	verifySvc ports.IEmailVerificationService
 }
 
-func NewAuthHandler(authService ports.IAuthService) *AuthHandler {
+func NewAuthHandler(
+	authService ports.IAuthService,
+	passwordResetService ports.IPasswordResetService,
+	emailVerificationService ports.IEmailVerificationService,
+) *AuthHandler {
 	return &AuthHandler{
-		svc: authService,
+		svc:       authService,
+		resetSvc:  passwordResetService,
+		verifySvc: emailVerificationService,
 	}
 }
 
 type loginRequest struct {
 	Username string `json:"username" binding:"required,min=3" example:"laplala" minLength:"3"`
-	Password string `json:"password" binding:"required,min=8" example:"12345678" minLength:"8"`
+	Password string `json:"password" binding:"required" example:"Laplala#2024"`
 }
 
 // Login go-blog
 //
 //	@Summary		Login and get an access token
 //	@Description	Logs in a registered user and returns an access token if the credentials are valid.
+//	@Description	If the user enabled two-factor authentication, "mfa_required" is true and the "mfa_token" must be sent with a code to /auth/login/mfa instead.
+//	@Description	With cookie sessions enabled the access token of browser requests (with an Origin header) is set in an HttpOnly "session" cookie instead of the body, unsafe requests using it must send the "csrf_token" cookie in the X-CSRF-Token header.
 //	@Tags			auth
 //	@Accept			json
 //	@Produce		json
@@ -42,12 +52,194 @@ func (auth AuthHandler) Login(ctx *gin.Context) {
 		return
 	}
 
-	token, err := auth.svc.Login(ctx, req.Username, req.Password)
+	result, err := auth.svc.Login(ctx, req.Username, req.Password)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	res := newLoginResponse(result)
+
+	handleLoginSuccess(ctx, res)
+}
+
+type loginMFARequest struct {
+	MFAToken string `json:"mfa_token" binding:"required" example:"eyJJ9.eyJwdXJwb3NlIjoibWZhIn0.fUjDw0"`
+	Code     string `json:"code" binding:"required" example:"123456"`
+}
+
+// LoginMFA go-blog
+//
+//	@Summary		Complete a two-factor login
+//	@Description	Exchanges the challenge token returned by /auth/login and a TOTP or recovery code for an access token.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		loginMFARequest				true	"Two-factor login request body"
+//	@Success		200		{object}	response{data=authResponse}	"Successfully logged in"
+//	@Failure		400		{object}	errorResponse				"Validation error"
+//	@Failure		401		{object}	errorResponse				"Invalid challenge token or code"
+//	@Failure		429		{object}	errorResponse				"Too many wrong codes for the challenge or the user"
+//	@Failure		500		{object}	errorResponse				"Internal server error"
+//	@Router			/auth/login/mfa [post]
+func (auth AuthHandler) LoginMFA(ctx *gin.Context) {
+	var req loginMFARequest
+
+	err := ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token, err := auth.svc.LoginMFA(ctx, req.MFAToken, req.Code)
 	if err != nil {
 		handleError(ctx, err)
 		return
 	}
 	res := newAuthResponse(token)
 
-	handleSuccess(ctx, res)
+	handleLoginSuccess(ctx, res)
+}
+
+// Logout go-blog
+//
+//	@Summary		Logout
+//	@Description	Deletes the session cookie of cookie sessions, access tokens sent in the Authorization header stay valid until they expire.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response		"Successfully logged out"
+//	@Failure		403	{object}	errorResponse	"Invalid CSRF token"
+//	@Router			/auth/logout [post]
+func (auth AuthHandler) Logout(ctx *gin.Context) {
+	clearSessionCookie(ctx)
+
+	handleSuccess(ctx, nil)
+}
+
+type forgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email" example:"laplala@example.com"`
+}
+
+// ForgotPassword go-blog
+//
+//	@Summary		Request a password reset
+//	@Description	Emails a single-use password reset link if an account has the email. The response is the same whether or not the email is registered.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		forgotPasswordRequest	true	"Forgot password request body"
+//	@Success		200		{object}	response				"Reset link sent if the email is registered"
+//	@Failure		400		{object}	errorResponse			"Validation error"
+//	@Failure		500		{object}	errorResponse			"Internal server error"
+//	@Router			/auth/password/forgot [post]
+func (auth AuthHandler) ForgotPassword(ctx *gin.Context) {
+	var req forgotPasswordRequest
+
+	err := ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	err = auth.resetSvc.RequestReset(ctx, req.Email)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+type resetPasswordRequest struct {
+	Token       string `json:"token" binding:"required" example:"bW9ja2VkIHJlc2V0IHRva2Vu"`
+	NewPassword string `json:"new_password" binding:"required" example:"Laplala#2025"`
+}
+
+// ResetPassword go-blog
+//
+//	@Summary		Reset password
+//	@Description	Sets a new password using a password reset token, the token can only be used once and all existing access tokens of the user are revoked.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		resetPasswordRequest	true	"Reset password request body"
+//	@Success		200		{object}	response				"Password reset"
+//	@Failure		400		{object}	errorResponse			"Validation error, invalid token or password policy violation"
+//	@Failure		500		{object}	errorResponse			"Internal server error"
+//	@Router			/auth/password/reset [post]
+func (auth AuthHandler) ResetPassword(ctx *gin.Context) {
+	var req resetPasswordRequest
+
+	err := ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	err = auth.resetSvc.ResetPassword(ctx, req.Token, req.NewPassword)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+type verifyEmailRequest struct {
+	Token string `json:"token" binding:"required" example:"bW9ja2VkIHZlcmlmeSB0b2tlbg"`
+}
+
+// VerifyEmail go-blog
+//
+//	@Summary		Verify email address
+//	@Description	Marks the email address of the user as verified using the token sent by email.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		verifyEmailRequest	true	"Verify email request body"
+//	@Success		200		{object}	response			"Email verified"
+//	@Failure		400		{object}	errorResponse		"Validation error or invalid token"
+//	@Failure		500		{object}	errorResponse		"Internal server error"
+//	@Router			/auth/verify-email [post]
+func (auth AuthHandler) VerifyEmail(ctx *gin.Context) {
+	var req verifyEmailRequest
+
+	err := ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	err = auth.verifySvc.VerifyEmail(ctx, req.Token)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+// ResendVerification go-blog
+//
+//	@Summary		Resend verification email
+//	@Description	Sends a new email verification link to the email address of the current user.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response		"Verification email sent"
+//	@Failure		400	{object}	errorResponse	"No email or email already verified"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/auth/verify-email/resend [post]
+//	@Security		BearerAuth
+func (auth AuthHandler) ResendVerification(ctx *gin.Context) {
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	err := auth.verifySvc.ResendVerification(ctx, token.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
 }
@@ -1,6 +1,10 @@
 package handler
 
 import (
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -10,27 +14,44 @@ import (
 )
 
 type BlogHandler struct {
-	svc ports.IBlogService
+	svc            ports.IBlogService
+	content        ports.IContentService
+	reactions      ports.IReactionService
+	views          ports.IAnalyticsService
+	requireIfMatch bool // updates and deletes without If-Match are rejected
 }
 
-func NewBlogHandler(blogService ports.IBlogService) *BlogHandler {
+func NewBlogHandler(blogService ports.IBlogService, contentService ports.IContentService, reactionService ports.IReactionService, analyticsService ports.IAnalyticsService, requireIfMatch bool) *BlogHandler {
 	return &BlogHandler{
-		svc: blogService,
+		svc:            blogService,
+		content:        contentService,
+		reactions:      reactionService,
+		views:          analyticsService,
+		requireIfMatch: requireIfMatch,
 	}
 }
 
+// getBlogRequest is the query of a single blog, render=html adds the text rendered from markdown
+// to sanitized HTML and its table of contents
+type getBlogRequest struct {
+	Render string `form:"render" binding:"omitempty,oneof=html" example:"html"`
+}
+
 // GetBlog go-blog
 //
 //	@Summary		get blog
-//	@Description	get blog by blog id
+//	@Description	get blog by blog id, the ETag header holds the version of the blog and a hash of the render mode and reaction counts
 //	@Tags			blogs
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		string						true	"blog id"	format(uuid)
-//	@Success		200	{object}	response{data=blogResponse}	"Blog data"
-//	@Failure		400	{object}	errorResponse				"Validation error"
-//	@Failure		404	{object}	errorResponse				"Data not found error"
-//	@Failure		500	{object}	errorResponse				"Internal server error"
+//	@Param			id				path		string						true	"blog id"															format(uuid)
+//	@Param			render			query		string						false	"Add the text rendered to sanitized HTML and its table of contents"	Enums(html)
+//	@Param			If-None-Match	header		string						false	"ETag of the cached blog"
+//	@Success		200				{object}	response{data=blogResponse}	"Blog data"
+//	@Success		304				"Blog not modified"
+//	@Failure		400				{object}	errorResponse	"Validation error"
+//	@Failure		404				{object}	errorResponse	"Data not found error"
+//	@Failure		500				{object}	errorResponse	"Internal server error"
 //	@Router			/blogs/{id} [get]
 func (bh *BlogHandler) GetBlog(ctx *gin.Context) {
 	paramId := ctx.Param("id")
@@ -41,16 +62,137 @@ func (bh *BlogHandler) GetBlog(ctx *gin.Context) {
 		return
 	}
 
+	var req getBlogRequest
+	if err := ctx.BindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
 	blog, err := bh.svc.GetBlogByID(ctx, id)
 	if err != nil {
 		handleError(ctx, err)
 		return
 	}
 
-	res := newBlogResponse(blog)
+	bh.recordView(ctx, blog)
+	bh.writeBlog(ctx, blog, req.Render == "html")
+}
+
+// GetBlogBySlug go-blog
+//
+//	@Summary		get blog by slug
+//	@Description	get blog by slug, an old slug of the blog or a non canonical form of the slug redirects permanently to the current one
+//	@Tags			blogs
+//	@Accept			json
+//	@Produce		json
+//	@Param			slug			path		string						true	"Blog slug"
+//	@Param			render			query		string						false	"Add the text rendered to sanitized HTML and its table of contents"	Enums(html)
+//	@Param			If-None-Match	header		string						false	"ETag of the cached blog"
+//	@Success		200				{object}	response{data=blogResponse}	"Blog data"
+//	@Success		301				"Moved to the current slug of the blog"
+//	@Success		304				"Blog not modified"
+//	@Failure		404				{object}	errorResponse	"Data not found error"
+//	@Failure		500				{object}	errorResponse	"Internal server error"
+//	@Router			/blogs/by-slug/{slug} [get]
+func (bh *BlogHandler) GetBlogBySlug(ctx *gin.Context) {
+	slug := ctx.Param("slug")
+
+	var req getBlogRequest
+	if err := ctx.BindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	blog, err := bh.svc.GetBlogBySlug(ctx, slug)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	if blog.Slug != slug {
+		location := path.Join(path.Dir(ctx.Request.URL.Path), url.PathEscape(blog.Slug))
+		if ctx.Request.URL.RawQuery != "" {
+			location += "?" + ctx.Request.URL.RawQuery
+		}
+		ctx.Redirect(http.StatusMovedPermanently, location)
+		return
+	}
+
+	bh.recordView(ctx, blog)
+	bh.writeBlog(ctx, blog, req.Render == "html")
+}
+
+// GetCodeBlocks go-blog
+//
+//	@Summary		get code blocks of blog
+//	@Description	list the fenced code blocks of a blog with their language and the options of their info string, for copy buttons
+//	@Tags			blogs
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string								true	"blog id"	format(uuid)
+//	@Success		200	{object}	response{data=[]codeBlockResponse}	"Code blocks"
+//	@Failure		400	{object}	errorResponse						"Validation error"
+//	@Failure		404	{object}	errorResponse						"Data not found error"
+//	@Failure		500	{object}	errorResponse						"Internal server error"
+//	@Router			/blogs/{id}/code-blocks [get]
+func (bh *BlogHandler) GetCodeBlocks(ctx *gin.Context) {
+	paramId := ctx.Param("id")
+
+	id, err := uuid.Parse(paramId)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	blog, err := bh.svc.GetBlogByID(ctx, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rendered, err := bh.content.Render(ctx, blog.Text)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newCodeBlocksResponse(rendered.CodeBlocks)
 	handleSuccess(ctx, res)
 }
 
+// getHighlightCSSRequest is the query of the stylesheet of the highlighted code blocks
+type getHighlightCSSRequest struct {
+	Style string `form:"style" binding:"" example:"github"`
+}
+
+// GetHighlightCSS go-blog
+//
+//	@Summary		get code highlighting stylesheet
+//	@Description	get the stylesheet of the classes of the code blocks highlighted in rendered blogs
+//	@Tags			blogs
+//	@Produce		text/css
+//	@Param			style	query		string			false	"Highlighting style, github by default"
+//	@Success		200		{string}	string			"Stylesheet"
+//	@Failure		404		{object}	errorResponse	"Data not found error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/blogs/highlight.css [get]
+func (bh *BlogHandler) GetHighlightCSS(ctx *gin.Context) {
+	var req getHighlightCSSRequest
+	if err := ctx.BindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	css, err := bh.content.HighlightCSS(ctx, req.Style)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.Header("Cache-Control", "public, max-age=86400")
+	ctx.Data(http.StatusOK, "text/css; charset=utf-8", []byte(css))
+}
+
 type getListBlogsRequest struct {
 	Query string `form:"q" binding:"" example:"how to ..."`
 	Skip  int    `form:"skip" binding:"min=0" example:"0"`
@@ -97,6 +239,13 @@ func (bh *BlogHandler) GetListBlogs(ctx *gin.Context) {
 	for _, blog := range blogs {
 		res = append(res, newBlogResponse(&blog))
 	}
+	blogRes := make([]*blogResponse, 0, len(res))
+	for i := range res {
+		blogRes = append(blogRes, &res[i])
+	}
+	if !bh.setReactions(ctx, blogRes...) {
+		return
+	}
 
 	meta := newMeta(len(res), req.Limit, req.Skip)
 
@@ -104,14 +253,17 @@ func (bh *BlogHandler) GetListBlogs(ctx *gin.Context) {
 }
 
 type createBlogRequest struct {
-	Title string `json:"title" binding:"required" example:"adw..."`
-	Text  string `json:"text" binding:"required" example:"adaw ..."`
+	Title   string   `json:"title" binding:"required" example:"adw..."`
+	Text    string   `json:"text" binding:"required" example:"adaw ..."`
+	Slug    string   `json:"slug" binding:"omitempty" example:"how-to"`                // made from the title if empty
+	Summary string   `json:"summary" binding:"max=280" example:"A short guide to ..."` // the first paragraph is the excerpt if empty
+	Tags    []string `json:"tags" binding:"max=5" example:"go,web"`
 }
 
 // CreateBlog go-blog
 //
 //	@Summary		create blog
-//	@Description	create a new blog
+//	@Description	create a new blog, the slug is made from the title unless one is given
 //	@Tags			blogs
 //	@Accept			json
 //	@Produce		json
@@ -119,10 +271,12 @@ type createBlogRequest struct {
 //	@Success		200		{object}	response{data=blogResponse}	"Blog created"
 //	@Failure		400		{object}	errorResponse				"Validation error"
 //	@Failure		401		{object}	errorResponse				"Unauthorized error"
+//	@Failure		403		{object}	errorResponse				"Forbidden error"
 //	@Failure		409		{object}	errorResponse				"Data conflict error"
 //	@Failure		500		{object}	errorResponse				"Internal server error"
 //	@Router			/blogs [post]
 //	@Security		BearerAuth
+//	@Security		ApiKeyAuth
 func (bh *BlogHandler) CreateBlog(ctx *gin.Context) {
 	var req createBlogRequest
 	err := ctx.BindJSON(&req)
@@ -135,7 +289,10 @@ func (bh *BlogHandler) CreateBlog(ctx *gin.Context) {
 
 	blog, err := bh.svc.CreateBlog(ctx, &domain.Blog{
 		Title:    req.Title,
+		Slug:     req.Slug,
 		Text:     req.Text,
+		Summary:  req.Summary,
+		Tags:     req.Tags,
 		AuthorID: token.ID,
 	})
 	if err != nil {
@@ -144,31 +301,42 @@ func (bh *BlogHandler) CreateBlog(ctx *gin.Context) {
 	}
 
 	res := newBlogResponse(blog)
+	if !bh.setReactions(ctx, &res) {
+		return
+	}
+	ctx.Header("ETag", blogETag(blog.Version, "", res.Reactions))
 	handleSuccess(ctx, res)
 }
 
 type putBlogRequest struct {
-	Title string `json:"title" binding:"required" example:"adw..."`
-	Text  string `json:"text" binding:"required" example:"adaw ..."`
+	Title   string   `json:"title" binding:"required" example:"adw..."`
+	Text    string   `json:"text" binding:"required" example:"adaw ..."`
+	Slug    string   `json:"slug" binding:"omitempty" example:"how-to"`                // left unchanged if empty
+	Summary string   `json:"summary" binding:"max=280" example:"A short guide to ..."` // left unchanged if empty
+	Tags    []string `json:"tags" binding:"max=5" example:"go,web"`                    // left unchanged if missing
 }
 
 // CreateBlog go-blog
 //
 //	@Summary		update blog
-//	@Description	update a blog data
+//	@Description	update a blog data, a slug made from the title follows it and the old slug redirects to the blog
 //	@Tags			blogs
 //	@Accept			json
 //	@Produce		json
-//	@Param			id		path		string						true	"Blog id"	format(uuid)
-//	@Param			request	body		putBlogRequest				true	"Update blog request body"
-//	@Success		200		{object}	response{data=blogResponse}	"Blog updated"
-//	@Failure		400		{object}	errorResponse				"Validation error"
-//	@Failure		401		{object}	errorResponse				"Unauthorized error"
-//	@Failure		403		{object}	errorResponse				"Forbidden error"
-//	@Failure		409		{object}	errorResponse				"Data conflict error"
-//	@Failure		500		{object}	errorResponse				"Internal server error"
+//	@Param			id			path		string						true	"Blog id"	format(uuid)
+//	@Param			If-Match	header		string						false	"ETag of the version the update applies to, required if the server is configured so"
+//	@Param			request		body		putBlogRequest				true	"Update blog request body"
+//	@Success		200			{object}	response{data=blogResponse}	"Blog updated"
+//	@Failure		400			{object}	errorResponse				"Validation error"
+//	@Failure		401			{object}	errorResponse				"Unauthorized error"
+//	@Failure		403			{object}	errorResponse				"Forbidden error"
+//	@Failure		409			{object}	errorResponse				"Data conflict error"
+//	@Failure		412			{object}	errorResponse				"Blog modified since the ETag was read"
+//	@Failure		428			{object}	errorResponse				"If-Match header required"
+//	@Failure		500			{object}	errorResponse				"Internal server error"
 //	@Router			/blogs/{id} [put]
 //	@Security		BearerAuth
+//	@Security		ApiKeyAuth
 func (bh *BlogHandler) UpdateBlog(ctx *gin.Context) {
 	paramId := ctx.Param("id")
 
@@ -185,6 +353,11 @@ func (bh *BlogHandler) UpdateBlog(ctx *gin.Context) {
 		return
 	}
 
+	version, ok := bh.bindIfMatch(ctx)
+	if !ok {
+		return
+	}
+
 	token := getAuthPayload(ctx, authorizationPayloadKey)
 
 	err = bh.svc.Authorized(ctx, token.ID, id)
@@ -194,9 +367,13 @@ func (bh *BlogHandler) UpdateBlog(ctx *gin.Context) {
 	}
 
 	blog, err := bh.svc.UpdateBlog(ctx, &domain.Blog{
-		ID:    id,
-		Title: req.Title,
-		Text:  req.Text,
+		ID:      id,
+		Title:   req.Title,
+		Slug:    req.Slug,
+		Text:    req.Text,
+		Summary: req.Summary,
+		Tags:    req.Tags,
+		Version: version,
 	})
 	if err != nil {
 		handleError(ctx, err)
@@ -204,25 +381,135 @@ func (bh *BlogHandler) UpdateBlog(ctx *gin.Context) {
 	}
 
 	res := newBlogResponse(blog)
+	if !bh.setReactions(ctx, &res) {
+		return
+	}
+	ctx.Header("ETag", blogETag(blog.Version, "", res.Reactions))
+	handleSuccess(ctx, res)
+}
+
+// patchBlogRequest is the patched blog document, a blog can not be left without a title,
+// an empty slug is made from the title and an empty summary shows the excerpt again
+type patchBlogRequest struct {
+	Title   string   `json:"title" binding:"required" example:"adw..."`
+	Text    string   `json:"text" example:"adaw ..."`
+	Slug    string   `json:"slug" example:"how-to"`
+	Summary string   `json:"summary" binding:"max=280" example:"A short guide to ..."`
+	Tags    []string `json:"tags" binding:"max=5" example:"go,web"`
+}
+
+// PatchBlog go-blog
+//
+//	@Summary		patch blog
+//	@Description	partially update a blog with a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902) applied to {"title", "text", "slug", "summary", "tags"},
+//	@Description	a removed or null text or summary clears it, a removed or null slug is made from the title again
+//	@Tags			blogs
+//	@Accept			application/merge-patch+json,application/json-patch+json
+//	@Produce		json
+//	@Param			id			path		string						true	"Blog id"	format(uuid)
+//	@Param			If-Match	header		string						false	"ETag of the version the patch applies to, required if the server is configured so"
+//	@Param			request		body		object						true	"Merge patch object or array of JSON Patch operations"
+//	@Success		200			{object}	response{data=blogResponse}	"Blog updated"
+//	@Failure		400			{object}	errorResponse				"Validation error or invalid patch"
+//	@Failure		401			{object}	errorResponse				"Unauthorized error"
+//	@Failure		403			{object}	errorResponse				"Forbidden error"
+//	@Failure		409			{object}	errorResponse				"Patch can not be applied, a test operation failed"
+//	@Failure		412			{object}	errorResponse				"Blog modified since the ETag was read"
+//	@Failure		415			{object}	errorResponse				"Unsupported patch content type"
+//	@Failure		428			{object}	errorResponse				"If-Match header required"
+//	@Failure		500			{object}	errorResponse				"Internal server error"
+//	@Router			/blogs/{id} [patch]
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+func (bh *BlogHandler) PatchBlog(ctx *gin.Context) {
+	paramId := ctx.Param("id")
+
+	id, err := uuid.Parse(paramId)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	version, ok := bh.bindIfMatch(ctx)
+	if !ok {
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	err = bh.svc.Authorized(ctx, token.ID, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	blog, err := bh.svc.GetBlogByID(ctx, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if version != 0 && version != blog.Version {
+		handleError(ctx, domain.ErrPreconditionFailed)
+		return
+	}
+
+	var req patchBlogRequest
+	if !bindPatch(ctx, map[string]interface{}{"title": blog.Title, "text": blog.Text, "slug": blog.Slug, "summary": blog.Summary, "tags": blog.Tags}, &req) {
+		return
+	}
+
+	patch := &domain.BlogPatch{}
+	if req.Title != blog.Title {
+		patch.Title = &req.Title
+	}
+	if req.Text != blog.Text {
+		patch.Text = &req.Text
+	}
+	if req.Slug != blog.Slug {
+		patch.Slug = &req.Slug
+	}
+	if req.Summary != blog.Summary {
+		patch.Summary = &req.Summary
+	}
+	if !slices.Equal(req.Tags, blog.Tags) {
+		patch.Tags = &req.Tags
+	}
+
+	// the patch was computed from this version, it must not apply to a newer one
+	updatedBlog, err := bh.svc.PatchBlog(ctx, id, blog.Version, patch)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newBlogResponse(updatedBlog)
+	if !bh.setReactions(ctx, &res) {
+		return
+	}
+	ctx.Header("ETag", blogETag(updatedBlog.Version, "", res.Reactions))
 	handleSuccess(ctx, res)
 }
 
 // DeleteBlog go-blog
 //
 //	@Summary		delete blog
-//	@Description	delete a blog
+//	@Description	move a blog to the trash, it can be restored until purged
 //	@Tags			blogs
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		string			true	"Blog id"	format(uuid)
-//	@Success		200	{object}	response		"Blog updated"
-//	@Failure		400	{object}	errorResponse	"Validation error"
-//	@Failure		401	{object}	errorResponse	"Unauthorized error"
-//	@Failure		403	{object}	errorResponse	"Forbidden error"
-//	@Failure		409	{object}	errorResponse	"Data conflict error"
-//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Param			id			path		string			true	"Blog id"	format(uuid)
+//	@Param			If-Match	header		string			false	"ETag of the version the delete applies to, required if the server is configured so"
+//	@Success		200			{object}	response		"Blog updated"
+//	@Failure		400			{object}	errorResponse	"Validation error"
+//	@Failure		401			{object}	errorResponse	"Unauthorized error"
+//	@Failure		403			{object}	errorResponse	"Forbidden error"
+//	@Failure		409			{object}	errorResponse	"Data conflict error"
+//	@Failure		412			{object}	errorResponse	"Blog modified since the ETag was read"
+//	@Failure		428			{object}	errorResponse	"If-Match header required"
+//	@Failure		500			{object}	errorResponse	"Internal server error"
 //	@Router			/blogs/{id} [delete]
 //	@Security		BearerAuth
+//	@Security		ApiKeyAuth
 func (bh *BlogHandler) DeleteBlog(ctx *gin.Context) {
 	paramId := ctx.Param("id")
 
@@ -232,6 +519,11 @@ func (bh *BlogHandler) DeleteBlog(ctx *gin.Context) {
 		return
 	}
 
+	version, ok := bh.bindIfMatch(ctx)
+	if !ok {
+		return
+	}
+
 	token := getAuthPayload(ctx, authorizationPayloadKey)
 
 	err = bh.svc.Authorized(ctx, token.ID, id)
@@ -240,7 +532,7 @@ func (bh *BlogHandler) DeleteBlog(ctx *gin.Context) {
 		return
 	}
 
-	err = bh.svc.DeleteBlog(ctx, id)
+	err = bh.svc.DeleteBlog(ctx, id, version)
 	if err != nil {
 		handleError(ctx, err)
 		return
@@ -248,3 +540,137 @@ func (bh *BlogHandler) DeleteBlog(ctx *gin.Context) {
 
 	handleSuccess(ctx, nil)
 }
+
+type getTrashRequest struct {
+	Skip  int `form:"skip" binding:"min=0" example:"0"`
+	Limit int `form:"limit" binding:"min=1,max=100" example:"20"`
+}
+
+// GetTrash go-blog
+//
+//	@Summary		get deleted blogs
+//	@Description	get the blogs of the current user in the trash, last deleted first
+//	@Tags			blogs
+//	@Accept			json
+//	@Produce		json
+//	@Param			skip	query		int									false	"Skip"	default(0)	minimum(0)
+//	@Param			limit	query		int									false	"Limit"	default(20)	minimum(1)	maximum(100)
+//	@Success		200		{object}	response{data=listBlogsResponse}	"Deleted blogs"
+//	@Failure		400		{object}	errorResponse						"Validation error"
+//	@Failure		401		{object}	errorResponse						"Unauthorized error"
+//	@Failure		403		{object}	errorResponse						"Forbidden error"
+//	@Failure		500		{object}	errorResponse						"Internal server error"
+//	@Router			/blogs/trash [get]
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+func (bh *BlogHandler) GetTrash(ctx *gin.Context) {
+	req := getTrashRequest{
+		Limit: 20,
+	}
+	err := ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	blogs, total, err := bh.svc.GetTrash(ctx, token.ID, req.Skip, req.Limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]blogResponse, 0, len(blogs))
+	for i := range blogs {
+		res = append(res, newBlogResponse(&blogs[i]))
+	}
+
+	meta := newMeta(total, req.Limit, req.Skip)
+	handleSuccess(ctx, newListBlogsResponse(meta, res))
+}
+
+// RestoreBlog go-blog
+//
+//	@Summary		restore blog
+//	@Description	take a blog of the current user out of the trash
+//	@Tags			blogs
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string						true	"Blog id"	format(uuid)
+//	@Success		200	{object}	response{data=blogResponse}	"Blog restored"
+//	@Failure		400	{object}	errorResponse				"Validation error"
+//	@Failure		401	{object}	errorResponse				"Unauthorized error"
+//	@Failure		403	{object}	errorResponse				"Forbidden error"
+//	@Failure		404	{object}	errorResponse				"Data not found error"
+//	@Failure		500	{object}	errorResponse				"Internal server error"
+//	@Router			/blogs/{id}/restore [post]
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+func (bh *BlogHandler) RestoreBlog(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	blog, err := bh.svc.RestoreBlog(ctx, token.ID, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newBlogResponse(blog)
+	if !bh.setReactions(ctx, &res) {
+		return
+	}
+	handleSuccess(ctx, res)
+}
+
+// writeBlog write a blog with its ETag, or not modified if the client has this representation,
+// render adds the rendered text to the response
+func (bh *BlogHandler) writeBlog(ctx *gin.Context, blog *domain.Blog, render bool) {
+	variant := ""
+	if render {
+		variant = "html"
+	}
+
+	res := newBlogResponse(blog)
+	if !bh.setReactions(ctx, &res) {
+		return
+	}
+
+	etag := blogETag(blog.Version, variant, res.Reactions)
+	ctx.Header("ETag", etag)
+	if noneMatch(ctx.GetHeader("If-None-Match"), etag) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+	if render {
+		rendered, err := bh.content.Render(ctx, blog.Text)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+		res.TextHTML = rendered.HTML
+		res.TOC = newTOCEntriesResponse(rendered.TOC)
+	}
+	handleSuccess(ctx, res)
+}
+
+// bindIfMatch read the version from the If-Match header, write the error and return false if it is invalid
+func (bh *BlogHandler) bindIfMatch(ctx *gin.Context) (int, bool) {
+	version, err := ifMatchVersion(ctx.GetHeader("If-Match"), bh.requireIfMatch)
+	if err != nil {
+		if err == errMultipleIfMatch {
+			validationError(ctx, err)
+		} else {
+			handleError(ctx, err)
+		}
+		return 0, false
+	}
+
+	return version, true
+}
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type revisionURI struct {
+	ID  string `uri:"id" binding:"required,uuid"`
+	Rev int    `uri:"rev" binding:"required,min=1"`
+}
+
+type getRevisionsRequest struct {
+	Skip  int `form:"skip" binding:"min=0" example:"0"`
+	Limit int `form:"limit" binding:"min=1,max=100" example:"20"`
+}
+
+// GetRevisions go-blog
+//
+//	@Summary		get blog revisions
+//	@Description	get the revisions of a blog of the current user, newest first, without text
+//	@Tags			blogs
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string										true	"Blog id"	format(uuid)
+//	@Param			skip	query		int											false	"Skip"		default(0)	minimum(0)
+//	@Param			limit	query		int											false	"Limit"		default(20)	minimum(1)	maximum(100)
+//	@Success		200		{object}	response{data=listBlogRevisionsResponse}	"Revisions"
+//	@Failure		400		{object}	errorResponse								"Validation error"
+//	@Failure		401		{object}	errorResponse								"Unauthorized error"
+//	@Failure		403		{object}	errorResponse								"Forbidden error"
+//	@Failure		404		{object}	errorResponse								"Data not found error"
+//	@Failure		500		{object}	errorResponse								"Internal server error"
+//	@Router			/blogs/{id}/revisions [get]
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+func (bh *BlogHandler) GetRevisions(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	req := getRevisionsRequest{
+		Limit: 20,
+	}
+	err = ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	if !bh.authorizeAuthor(ctx, id) {
+		return
+	}
+
+	revisions, total, err := bh.svc.GetRevisions(ctx, id, req.Skip, req.Limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]blogRevisionResponse, 0, len(revisions))
+	for i := range revisions {
+		res = append(res, newBlogRevisionResponse(&revisions[i]))
+	}
+
+	meta := newMeta(total, req.Limit, req.Skip)
+	handleSuccess(ctx, newListBlogRevisionsResponse(meta, res))
+}
+
+// GetRevision go-blog
+//
+//	@Summary		get blog revision
+//	@Description	get a revision of a blog of the current user with its text
+//	@Tags			blogs
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string								true	"Blog id"			format(uuid)
+//	@Param			rev	path		int									true	"Revision number"	minimum(1)
+//	@Success		200	{object}	response{data=blogRevisionResponse}	"Revision"
+//	@Failure		400	{object}	errorResponse						"Validation error"
+//	@Failure		401	{object}	errorResponse						"Unauthorized error"
+//	@Failure		403	{object}	errorResponse						"Forbidden error"
+//	@Failure		404	{object}	errorResponse						"Data not found error"
+//	@Failure		500	{object}	errorResponse						"Internal server error"
+//	@Router			/blogs/{id}/revisions/{rev} [get]
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+func (bh *BlogHandler) GetRevision(ctx *gin.Context) {
+	var uri revisionURI
+	err := ctx.BindUri(&uri)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+	id := uuid.MustParse(uri.ID)
+
+	if !bh.authorizeAuthor(ctx, id) {
+		return
+	}
+
+	revision, err := bh.svc.GetRevision(ctx, id, uri.Rev)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newBlogRevisionResponse(revision)
+	handleSuccess(ctx, res)
+}
+
+type diffRevisionsRequest struct {
+	From int `form:"from" binding:"required,min=1" example:"1"`
+	To   int `form:"to" binding:"required,min=1" example:"2"`
+}
+
+// DiffRevisions go-blog
+//
+//	@Summary		diff blog revisions
+//	@Description	get the line-level diff of the text of two revisions of a blog of the current user, the titles are returned as is
+//	@Tags			blogs
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string								true	"Blog id"				format(uuid)
+//	@Param			from	query		int									true	"Old revision number"	minimum(1)
+//	@Param			to		query		int									true	"New revision number"	minimum(1)
+//	@Success		200		{object}	response{data=revisionDiffResponse}	"Diff"
+//	@Failure		400		{object}	errorResponse						"Validation error"
+//	@Failure		401		{object}	errorResponse						"Unauthorized error"
+//	@Failure		403		{object}	errorResponse						"Forbidden error"
+//	@Failure		404		{object}	errorResponse						"Data not found error"
+//	@Failure		500		{object}	errorResponse						"Internal server error"
+//	@Router			/blogs/{id}/revisions/diff [get]
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+func (bh *BlogHandler) DiffRevisions(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	var req diffRevisionsRequest
+	err = ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	if !bh.authorizeAuthor(ctx, id) {
+		return
+	}
+
+	diff, err := bh.svc.DiffRevisions(ctx, id, req.From, req.To)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newRevisionDiffResponse(diff)
+	handleSuccess(ctx, res)
+}
+
+// RestoreRevision go-blog
+//
+//	@Summary		restore blog revision
+//	@Description	set the content of a blog of the current user back to a revision, saved as a new revision
+//	@Tags			blogs
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string						true	"Blog id"			format(uuid)
+//	@Param			rev	path		int							true	"Revision number"	minimum(1)
+//	@Success		200	{object}	response{data=blogResponse}	"Blog updated"
+//	@Failure		400	{object}	errorResponse				"Validation error"
+//	@Failure		401	{object}	errorResponse				"Unauthorized error"
+//	@Failure		403	{object}	errorResponse				"Forbidden error"
+//	@Failure		404	{object}	errorResponse				"Data not found error"
+//	@Failure		500	{object}	errorResponse				"Internal server error"
+//	@Router			/blogs/{id}/revisions/{rev}/restore [post]
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+func (bh *BlogHandler) RestoreRevision(ctx *gin.Context) {
+	var uri revisionURI
+	err := ctx.BindUri(&uri)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+	id := uuid.MustParse(uri.ID)
+
+	if !bh.authorizeAuthor(ctx, id) {
+		return
+	}
+
+	blog, err := bh.svc.RestoreRevision(ctx, id, uri.Rev)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newBlogResponse(blog)
+	if !bh.setReactions(ctx, &res) {
+		return
+	}
+	handleSuccess(ctx, res)
+}
+
+// authorizeAuthor check the current user owns the blog, write the error and return false if not
+func (bh *BlogHandler) authorizeAuthor(ctx *gin.Context, id uuid.UUID) bool {
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	err := bh.svc.Authorized(ctx, token.ID, id)
+	if err != nil {
+		handleError(ctx, err)
+		return false
+	}
+	return true
+}
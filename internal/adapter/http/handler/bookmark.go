@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+type BookmarkHandler struct {
+	svc ports.IBookmarkService
+}
+
+func NewBookmarkHandler(bookmarkService ports.IBookmarkService) *BookmarkHandler {
+	return &BookmarkHandler{
+		svc: bookmarkService,
+	}
+}
+
+// addBookmarkRequest is the optional body of a bookmark
+type addBookmarkRequest struct {
+	Note string `json:"note" binding:"max=500" example:"read the part on caching again"`
+}
+
+// AddBookmark go-blog
+//
+//	@Summary		add bookmark
+//	@Description	save a blog to the bookmarks of the current user, saving it again replaces the note
+//	@Tags			bookmarks
+//	@Accept			json
+//	@Produce		json
+//	@Param			blogId	path		string							true	"Blog id"	format(uuid)
+//	@Param			request	body		addBookmarkRequest				false	"Add bookmark request body"
+//	@Success		200		{object}	response{data=bookmarkResponse}	"Bookmark saved"
+//	@Failure		400		{object}	errorResponse					"Validation error"
+//	@Failure		401		{object}	errorResponse					"Unauthorized error"
+//	@Failure		404		{object}	errorResponse					"Data not found error"
+//	@Failure		500		{object}	errorResponse					"Internal server error"
+//	@Router			/users/me/bookmarks/{blogId} [post]
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+func (bh *BookmarkHandler) AddBookmark(ctx *gin.Context) {
+	blogID, err := uuid.Parse(ctx.Param("blogId"))
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	var req addBookmarkRequest
+	if ctx.Request.ContentLength != 0 {
+		err = ctx.BindJSON(&req)
+		if err != nil {
+			validationError(ctx, err)
+			return
+		}
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	bookmark, err := bh.svc.AddBookmark(ctx, token.ID, blogID, req.Note)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newBookmarkResponse(bookmark)
+	handleSuccess(ctx, res)
+}
+
+// RemoveBookmark go-blog
+//
+//	@Summary		remove bookmark
+//	@Description	remove a blog from the bookmarks of the current user
+//	@Tags			bookmarks
+//	@Accept			json
+//	@Produce		json
+//	@Param			blogId	path		string			true	"Blog id"	format(uuid)
+//	@Success		200		{object}	response		"Bookmark removed"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		401		{object}	errorResponse	"Unauthorized error"
+//	@Failure		404		{object}	errorResponse	"Data not found error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/users/me/bookmarks/{blogId} [delete]
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+func (bh *BookmarkHandler) RemoveBookmark(ctx *gin.Context) {
+	blogID, err := uuid.Parse(ctx.Param("blogId"))
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	err = bh.svc.RemoveBookmark(ctx, token.ID, blogID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+type getBookmarksRequest struct {
+	Skip  int `form:"skip" binding:"min=0" example:"0"`
+	Limit int `form:"limit" binding:"min=1,max=100" example:"20"`
+}
+
+// GetBookmarks go-blog
+//
+//	@Summary		get bookmarks
+//	@Description	get the bookmarks of the current user with the summary of their blogs, last saved first,
+//	@Description	the bookmarks of blogs in the trash are not listed
+//	@Tags			bookmarks
+//	@Accept			json
+//	@Produce		json
+//	@Param			skip	query		int										false	"Skip"	default(0)	minimum(0)
+//	@Param			limit	query		int										false	"Limit"	default(20)	minimum(1)	maximum(100)
+//	@Success		200		{object}	response{data=listBookmarksResponse}	"Bookmarks"
+//	@Failure		400		{object}	errorResponse							"Validation error"
+//	@Failure		401		{object}	errorResponse							"Unauthorized error"
+//	@Failure		500		{object}	errorResponse							"Internal server error"
+//	@Router			/users/me/bookmarks [get]
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+func (bh *BookmarkHandler) GetBookmarks(ctx *gin.Context) {
+	req := getBookmarksRequest{
+		Limit: 20,
+	}
+	err := ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	bookmarks, total, err := bh.svc.GetBookmarks(ctx, token.ID, req.Skip, req.Limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]bookmarkResponse, 0, len(bookmarks))
+	for i := range bookmarks {
+		res = append(res, newBookmarkResponse(&bookmarks[i]))
+	}
+
+	meta := newMeta(total, req.Limit, req.Skip)
+	handleSuccess(ctx, newListBookmarksResponse(meta, res))
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+)
+
+var errMultipleIfMatch = errors.New("if-match header must be a single entity tag or *")
+
+// versionETag return the strong entity tag of a version
+func versionETag(version int) string {
+	return fmt.Sprintf(`"%v"`, version)
+}
+
+// blogETag return the strong entity tag of a representation of a blog version, the version is followed by
+// a hash of the variant and the reaction counts, they change the representation without a new version
+func blogETag(version int, variant string, counts domain.ReactionCounts) string {
+	kinds := make([]string, 0, len(counts))
+	for kind := range counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	hash := sha256.New()
+	fmt.Fprint(hash, variant)
+	for _, kind := range kinds {
+		fmt.Fprintf(hash, ";%v=%v", kind, counts[kind])
+	}
+
+	return fmt.Sprintf(`"%v-%x"`, version, hash.Sum(nil)[:8])
+}
+
+// noneMatch check if the If-None-Match header matches the entity tag, weak tags match too
+func noneMatch(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// notModified check if the client has the entity from its conditional headers, If-None-Match is used
+// over If-Modified-Since when both are sent
+func notModified(header http.Header, etag string, lastModified time.Time) bool {
+	if noneMatchHeader := header.Get("If-None-Match"); noneMatchHeader != "" {
+		return noneMatch(noneMatchHeader, etag)
+	}
+
+	since, err := http.ParseTime(header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// writeDocument write a generated document with its ETag and Last-Modified, or not modified if the client has it
+func writeDocument(ctx *gin.Context, contentType, content, hash string, lastModified time.Time) {
+	etag := `"` + hash + `"`
+	ctx.Header("ETag", etag)
+	ctx.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	if notModified(ctx.Request.Header, etag, lastModified) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	ctx.Data(http.StatusOK, contentType, []byte(content))
+}
+
+// ifMatchVersion return the version the If-Match header applies to, 0 for any version,
+// domain.ErrPreconditionRequired if the header is missing and required
+func ifMatchVersion(header string, required bool) (int, error) {
+	header = strings.TrimSpace(header)
+	switch {
+	case header == "":
+		if required {
+			return 0, domain.ErrPreconditionRequired
+		}
+		return 0, nil
+	case header == "*":
+		return 0, nil
+	case strings.Contains(header, ","):
+		return 0, errMultipleIfMatch
+	}
+
+	// a weak tag or a tag that was never handed out can not match any version,
+	// the variant of a representation does not change the version it applies to
+	tag := strings.TrimSuffix(strings.TrimPrefix(header, `"`), `"`)
+	number, variant, hasVariant := strings.Cut(tag, "-")
+	version, err := strconv.Atoi(number)
+	if err != nil || version < 1 || number != strconv.Itoa(version) || (hasVariant && variant == "") ||
+		header != `"`+tag+`"` {
+		return 0, domain.ErrPreconditionFailed
+	}
+
+	return version, nil
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+// feedContentTypes are the media types of the feed formats
+var feedContentTypes = map[domain.FeedFormat]string{
+	domain.FeedRSS:  "application/rss+xml; charset=utf-8",
+	domain.FeedAtom: "application/atom+xml; charset=utf-8",
+	domain.FeedJSON: "application/feed+json; charset=utf-8",
+}
+
+type FeedHandler struct {
+	svc ports.IFeedService
+}
+
+func NewFeedHandler(feedService ports.IFeedService) *FeedHandler {
+	return &FeedHandler{
+		svc: feedService,
+	}
+}
+
+// GetSiteFeed go-blog
+//
+//	@Summary		get site feed
+//	@Description	get the feed of the latest blogs of the site as RSS 2.0, Atom 1.0 or JSON Feed 1.1,
+//	@Description	the ETag and Last-Modified headers can be sent back to get not modified
+//	@Tags			feeds
+//	@Produce		application/rss+xml,application/atom+xml,application/feed+json
+//	@Param			If-None-Match		header		string	false	"ETag of the cached feed"
+//	@Param			If-Modified-Since	header		string	false	"Last-Modified of the cached feed"
+//	@Success		200					{string}	string	"Feed"
+//	@Success		304					"Feed not modified"
+//	@Failure		500					{object}	errorResponse	"Internal server error"
+//	@Router			/feed.rss [get]
+//	@Router			/feed.atom [get]
+//	@Router			/feed.json [get]
+func (fh *FeedHandler) GetSiteFeed(ctx *gin.Context) {
+	feed, err := fh.svc.GetSiteFeed(ctx, feedFormat(ctx))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	writeFeed(ctx, feed)
+}
+
+// GetAuthorFeed go-blog
+//
+//	@Summary		get author feed
+//	@Description	get the feed of the latest blogs of an author as RSS 2.0, Atom 1.0 or JSON Feed 1.1,
+//	@Description	the ETag and Last-Modified headers can be sent back to get not modified
+//	@Tags			feeds
+//	@Produce		application/rss+xml,application/atom+xml,application/feed+json
+//	@Param			id					path		string	true	"Author id"	format(uuid)
+//	@Param			If-None-Match		header		string	false	"ETag of the cached feed"
+//	@Param			If-Modified-Since	header		string	false	"Last-Modified of the cached feed"
+//	@Success		200					{string}	string	"Feed"
+//	@Success		304					"Feed not modified"
+//	@Failure		400					{object}	errorResponse	"Validation error"
+//	@Failure		404					{object}	errorResponse	"Data not found error"
+//	@Failure		500					{object}	errorResponse	"Internal server error"
+//	@Router			/users/{id}/feed.rss [get]
+//	@Router			/users/{id}/feed.atom [get]
+//	@Router			/users/{id}/feed.json [get]
+func (fh *FeedHandler) GetAuthorFeed(ctx *gin.Context) {
+	paramId := ctx.Param("id")
+
+	id, err := uuid.Parse(paramId)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	feed, err := fh.svc.GetAuthorFeed(ctx, feedFormat(ctx), id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	writeFeed(ctx, feed)
+}
+
+// GetTagFeed go-blog
+//
+//	@Summary		get tag feed
+//	@Description	get the feed of the latest blogs with a tag as RSS 2.0, Atom 1.0 or JSON Feed 1.1,
+//	@Description	the ETag and Last-Modified headers can be sent back to get not modified
+//	@Tags			feeds
+//	@Produce		application/rss+xml,application/atom+xml,application/feed+json
+//	@Param			tag					path		string	true	"Tag"
+//	@Param			If-None-Match		header		string	false	"ETag of the cached feed"
+//	@Param			If-Modified-Since	header		string	false	"Last-Modified of the cached feed"
+//	@Success		200					{string}	string	"Feed"
+//	@Success		304					"Feed not modified"
+//	@Failure		400					{object}	errorResponse	"Validation error"
+//	@Failure		500					{object}	errorResponse	"Internal server error"
+//	@Router			/tags/{tag}/feed.rss [get]
+//	@Router			/tags/{tag}/feed.atom [get]
+//	@Router			/tags/{tag}/feed.json [get]
+func (fh *FeedHandler) GetTagFeed(ctx *gin.Context) {
+	feed, err := fh.svc.GetTagFeed(ctx, feedFormat(ctx), ctx.Param("tag"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	writeFeed(ctx, feed)
+}
+
+// feedFormat return the format of the feed of the route, the extension of its path
+func feedFormat(ctx *gin.Context) domain.FeedFormat {
+	return domain.FeedFormat(strings.TrimPrefix(path.Ext(ctx.FullPath()), "."))
+}
+
+// writeFeed write a feed with its ETag and Last-Modified, or not modified if the client has it
+func writeFeed(ctx *gin.Context, feed *domain.Feed) {
+	writeDocument(ctx, feedContentTypes[feed.Format], feed.Content, feed.ETag, feed.UpdatedAt)
+}
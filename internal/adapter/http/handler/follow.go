@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+// errInvalidCursor is the validation error of a timeline cursor that was not returned by the api
+var errInvalidCursor = errors.New("cursor is invalid")
+
+type FollowHandler struct {
+	svc ports.IFollowService
+}
+
+func NewFollowHandler(followService ports.IFollowService) *FollowHandler {
+	return &FollowHandler{
+		svc: followService,
+	}
+}
+
+// Follow go-blog
+//
+//	@Summary		follow user
+//	@Description	make the current user follow an author, following an author again does nothing
+//	@Tags			follows
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string								true	"User id"	format(uuid)
+//	@Success		200	{object}	response{data=followCountsResponse}	"Follow counts of the author"
+//	@Failure		400	{object}	errorResponse						"Validation error"
+//	@Failure		401	{object}	errorResponse						"Unauthorized error"
+//	@Failure		404	{object}	errorResponse						"Data not found error"
+//	@Failure		500	{object}	errorResponse						"Internal server error"
+//	@Router			/users/{id}/follow [post]
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+func (fh *FollowHandler) Follow(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	counts, err := fh.svc.Follow(ctx, token.ID, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newFollowCountsResponse(counts)
+	handleSuccess(ctx, res)
+}
+
+// Unfollow go-blog
+//
+//	@Summary		unfollow user
+//	@Description	make the current user stop following an author
+//	@Tags			follows
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string								true	"User id"	format(uuid)
+//	@Success		200	{object}	response{data=followCountsResponse}	"Follow counts of the author"
+//	@Failure		400	{object}	errorResponse						"Validation error"
+//	@Failure		401	{object}	errorResponse						"Unauthorized error"
+//	@Failure		404	{object}	errorResponse						"Data not found error"
+//	@Failure		500	{object}	errorResponse						"Internal server error"
+//	@Router			/users/{id}/follow [delete]
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+func (fh *FollowHandler) Unfollow(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	counts, err := fh.svc.Unfollow(ctx, token.ID, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newFollowCountsResponse(counts)
+	handleSuccess(ctx, res)
+}
+
+type getFollowsRequest struct {
+	Skip  int `form:"skip" binding:"min=0" example:"0"`
+	Limit int `form:"limit" binding:"min=1,max=100" example:"20"`
+}
+
+// GetFollowers go-blog
+//
+//	@Summary		get followers
+//	@Description	get the followers of a user, last followed first, the total of the meta is the number of followers
+//	@Tags			follows
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string								true	"User id"	format(uuid)
+//	@Param			skip	query		int									false	"Skip"		default(0)	minimum(0)
+//	@Param			limit	query		int									false	"Limit"		default(20)	minimum(1)	maximum(100)
+//	@Success		200		{object}	response{data=listFollowsResponse}	"Followers"
+//	@Failure		400		{object}	errorResponse						"Validation error"
+//	@Failure		404		{object}	errorResponse						"Data not found error"
+//	@Failure		500		{object}	errorResponse						"Internal server error"
+//	@Router			/users/{id}/followers [get]
+func (fh *FollowHandler) GetFollowers(ctx *gin.Context) {
+	fh.getFollows(ctx, fh.svc.GetFollowers)
+}
+
+// GetFollowing go-blog
+//
+//	@Summary		get following
+//	@Description	get the authors a user follows, last followed first, the total of the meta is the number of authors
+//	@Tags			follows
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string								true	"User id"	format(uuid)
+//	@Param			skip	query		int									false	"Skip"		default(0)	minimum(0)
+//	@Param			limit	query		int									false	"Limit"		default(20)	minimum(1)	maximum(100)
+//	@Success		200		{object}	response{data=listFollowsResponse}	"Followed authors"
+//	@Failure		400		{object}	errorResponse						"Validation error"
+//	@Failure		404		{object}	errorResponse						"Data not found error"
+//	@Failure		500		{object}	errorResponse						"Internal server error"
+//	@Router			/users/{id}/following [get]
+func (fh *FollowHandler) GetFollowing(ctx *gin.Context) {
+	fh.getFollows(ctx, fh.svc.GetFollowing)
+}
+
+// getFollows write a page of the follows of the user of the id param
+func (fh *FollowHandler) getFollows(ctx *gin.Context, get func(ctx context.Context, userID uuid.UUID, skip, limit int) ([]domain.Follow, int, error)) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	req := getFollowsRequest{
+		Limit: 20,
+	}
+	err = ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	follows, total, err := get(ctx, id, req.Skip, req.Limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]followResponse, 0, len(follows))
+	for i := range follows {
+		res = append(res, newFollowResponse(&follows[i]))
+	}
+
+	meta := newMeta(total, req.Limit, req.Skip)
+	handleSuccess(ctx, newListFollowsResponse(meta, res))
+}
+
+type getTimelineRequest struct {
+	Cursor string `form:"cursor" example:"MTcwMDAwMDAwMDAwMDAwMDAwMF8zOTgzM2IxMi1hMDQ0LTQ2ZjUtOGFiZC00N2M0NzM0NWQ0NTg"`
+	Limit  int    `form:"limit" binding:"min=1,max=100" example:"20"`
+}
+
+// GetTimeline go-blog
+//
+//	@Summary		get timeline
+//	@Description	get the latest blogs of the authors the current user follows, newest first, without their text.
+//	@Description	The next page is read with the next_cursor of the page, the last page has none
+//	@Tags			follows
+//	@Accept			json
+//	@Produce		json
+//	@Param			cursor	query		string							false	"Cursor of the page"
+//	@Param			limit	query		int								false	"Limit"	default(20)	minimum(1)	maximum(100)
+//	@Success		200		{object}	response{data=timelineResponse}	"Timeline"
+//	@Failure		400		{object}	errorResponse					"Validation error"
+//	@Failure		401		{object}	errorResponse					"Unauthorized error"
+//	@Failure		500		{object}	errorResponse					"Internal server error"
+//	@Router			/feed [get]
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+func (fh *FollowHandler) GetTimeline(ctx *gin.Context) {
+	req := getTimelineRequest{
+		Limit: 20,
+	}
+	err := ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	var cursor *domain.TimelineCursor
+	if req.Cursor != "" {
+		cursor, err = parseTimelineCursor(req.Cursor)
+		if err != nil {
+			validationError(ctx, err)
+			return
+		}
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	timeline, err := fh.svc.GetTimeline(ctx, token.ID, cursor, req.Limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newTimelineResponse(timeline)
+	handleSuccess(ctx, res)
+}
+
+// formatTimelineCursor write a timeline cursor as an opaque string, the creation time and the id of the last blog
+func formatTimelineCursor(cursor *domain.TimelineCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(cursor.CreatedAt.UnixNano(), 10) + "_" + cursor.ID.String()))
+}
+
+// parseTimelineCursor read a timeline cursor written by formatTimelineCursor
+func parseTimelineCursor(text string) (*domain.TimelineCursor, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(text)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	nanos, id, ok := strings.Cut(string(bytes), "_")
+	if !ok {
+		return nil, errInvalidCursor
+	}
+	createdAt, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	blogID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	return &domain.TimelineCursor{
+		CreatedAt: time.Unix(0, createdAt),
+		ID:        blogID,
+	}, nil
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+type MagicLinkHandler struct {
+	svc ports.IMagicLinkService
+}
+
+func NewMagicLinkHandler(magicLinkService ports.IMagicLinkService) *MagicLinkHandler {
+	return &MagicLinkHandler{
+		svc: magicLinkService,
+	}
+}
+
+type magicLinkRequest struct {
+	Email string `json:"email" binding:"required,email" example:"laplala@example.com"`
+}
+
+// RequestLink go-blog
+//
+//	@Summary		Request a login link
+//	@Description	Emails a single-use, short-lived login link if an account has the email. The response is the same whether or not the email is registered.
+//	@Description	Each address can only request a few links per window.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		magicLinkRequest	true	"Magic link request body"
+//	@Success		200		{object}	response			"Login link sent if the email is registered"
+//	@Failure		400		{object}	errorResponse		"Validation error"
+//	@Failure		429		{object}	errorResponse		"Too many links requested for the address"
+//	@Failure		500		{object}	errorResponse		"Internal server error"
+//	@Router			/auth/magic-link [post]
+func (mh *MagicLinkHandler) RequestLink(ctx *gin.Context) {
+	var req magicLinkRequest
+
+	err := ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	err = mh.svc.RequestLink(ctx, req.Email)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+type magicLinkCallbackRequest struct {
+	Token string `json:"token" binding:"required" example:"bW9ja2VkIGxvZ2luIHRva2Vu"`
+}
+
+// Callback go-blog
+//
+//	@Summary		Login with a login link
+//	@Description	Consumes the token of a login link and returns an access token, or a two-factor challenge like /auth/login.
+//	@Description	It is a POST so mail scanners that open links can not use the token up.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		magicLinkCallbackRequest	true	"Magic link callback request body"
+//	@Success		200		{object}	response{data=authResponse}	"Successfully logged in"
+//	@Failure		400		{object}	errorResponse				"Validation error or invalid, used or expired token"
+//	@Failure		500		{object}	errorResponse				"Internal server error"
+//	@Router			/auth/magic-link/callback [post]
+func (mh *MagicLinkHandler) Callback(ctx *gin.Context) {
+	var req magicLinkCallbackRequest
+
+	err := ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	result, err := mh.svc.Login(ctx, req.Token)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newLoginResponse(result)
+	handleLoginSuccess(ctx, res)
+}
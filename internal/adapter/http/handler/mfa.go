@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+type MFAHandler struct {
+	svc ports.IMFAService
+}
+
+func NewMFAHandler(mfaService ports.IMFAService) *MFAHandler {
+	return &MFAHandler{
+		svc: mfaService,
+	}
+}
+
+// EnrollTOTP go-blog
+//
+//	@Summary		Start TOTP enrolment
+//	@Description	Generates a new TOTP secret and its otpauth URI for the current user. Two-factor authentication is enabled once a first code is confirmed.
+//	@Tags			mfa
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response{data=totpEnrollmentResponse}	"TOTP secret"
+//	@Failure		401	{object}	errorResponse							"Unauthorized error"
+//	@Failure		409	{object}	errorResponse							"Two-factor authentication already enabled"
+//	@Failure		500	{object}	errorResponse							"Internal server error"
+//	@Router			/auth/mfa/totp/enroll [post]
+//	@Security		BearerAuth
+func (mh *MFAHandler) EnrollTOTP(ctx *gin.Context) {
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	enrollment, err := mh.svc.EnrollTOTP(ctx, token.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newTOTPEnrollmentResponse(enrollment)
+	handleSuccess(ctx, res)
+}
+
+type mfaCodeRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric" example:"123456"`
+}
+
+// ConfirmTOTP go-blog
+//
+//	@Summary		Confirm TOTP enrolment
+//	@Description	Enables two-factor authentication with a first code of the enrolled secret and returns the recovery codes, they are only shown once.
+//	@Tags			mfa
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		mfaCodeRequest							true	"TOTP code"
+//	@Success		200		{object}	response{data=recoveryCodesResponse}	"Two-factor authentication enabled"
+//	@Failure		400		{object}	errorResponse							"Validation error or enrolment not started"
+//	@Failure		401		{object}	errorResponse							"Unauthorized error or invalid code"
+//	@Failure		409		{object}	errorResponse							"Two-factor authentication already enabled"
+//	@Failure		500		{object}	errorResponse							"Internal server error"
+//	@Router			/auth/mfa/totp/confirm [post]
+//	@Security		BearerAuth
+func (mh *MFAHandler) ConfirmTOTP(ctx *gin.Context) {
+	var req mfaCodeRequest
+
+	err := ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	codes, err := mh.svc.ConfirmTOTP(ctx, token.ID, req.Code)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newRecoveryCodesResponse(codes)
+	handleSuccess(ctx, res)
+}
+
+type disableTOTPRequest struct {
+	Password string `json:"password" binding:"required" example:"Laplala#2024"`
+}
+
+// DisableTOTP go-blog
+//
+//	@Summary		Disable TOTP
+//	@Description	Disables two-factor authentication and deletes the recovery codes, the password is required.
+//	@Tags			mfa
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		disableTOTPRequest	true	"Disable TOTP request body"
+//	@Success		200		{object}	response			"Two-factor authentication disabled"
+//	@Failure		400		{object}	errorResponse		"Validation error or not enabled"
+//	@Failure		401		{object}	errorResponse		"Unauthorized error"
+//	@Failure		403		{object}	errorResponse		"Incorrect password"
+//	@Failure		500		{object}	errorResponse		"Internal server error"
+//	@Router			/auth/mfa/totp [delete]
+//	@Security		BearerAuth
+func (mh *MFAHandler) DisableTOTP(ctx *gin.Context) {
+	var req disableTOTPRequest
+
+	err := ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	err = mh.svc.DisableTOTP(ctx, token.ID, req.Password)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+// RegenerateRecoveryCodes go-blog
+//
+//	@Summary		Regenerate recovery codes
+//	@Description	Replaces all recovery codes of the current user, a TOTP code is required.
+//	@Tags			mfa
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		mfaCodeRequest							true	"TOTP code"
+//	@Success		200		{object}	response{data=recoveryCodesResponse}	"New recovery codes"
+//	@Failure		400		{object}	errorResponse							"Validation error or not enabled"
+//	@Failure		401		{object}	errorResponse							"Unauthorized error or invalid code"
+//	@Failure		500		{object}	errorResponse							"Internal server error"
+//	@Router			/auth/mfa/recovery-codes [post]
+//	@Security		BearerAuth
+func (mh *MFAHandler) RegenerateRecoveryCodes(ctx *gin.Context) {
+	var req mfaCodeRequest
+
+	err := ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	codes, err := mh.svc.RegenerateRecoveryCodes(ctx, token.ID, req.Code)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newRecoveryCodesResponse(codes)
+	handleSuccess(ctx, res)
+}
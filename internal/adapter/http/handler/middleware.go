@@ -13,14 +13,26 @@ var (
 	authorizationHeaderKey = "authorization"
 	// authorizationType is the accepted authorization type
 	authorizationType = "bearer"
+	// apiKeyAuthorizationType is the authorization type of api keys
+	apiKeyAuthorizationType = "apikey"
 	// authorizationPayloadKey is the key for authorization payload in the context
 	authorizationPayloadKey = "authorization_payload"
 )
 
-func AuthBeerMiddleware(token ports.ITokenService) gin.HandlerFunc {
+// AuthBeerMiddleware authenticate the request with an access token sent in the Authorization
+// header or the session cookie, when scopes are given an api key granted all of them is
+// accepted too with the "ApiKey" authorization type
+func AuthBeerMiddleware(auth ports.IAuthService, scopes ...domain.APIKeyScope) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 
+		// browsers using cookie sessions send the access token in the session cookie
+		if authorizationHeader == "" {
+			if token := getSessionCookie(ctx); token != "" {
+				authorizationHeader = authorizationType + " " + token
+			}
+		}
+
 		isEmpty := len(authorizationHeader) == 0
 
 		if isEmpty {
@@ -37,15 +49,22 @@ func AuthBeerMiddleware(token ports.ITokenService) gin.HandlerFunc {
 			return
 		}
 
+		var (
+			payload *domain.TokenPayload
+			err     error
+		)
+
 		currentAuthorizationType := strings.ToLower(fields[0])
-		if currentAuthorizationType != authorizationType {
-			handleError(ctx, domain.ErrInvalidAuthorizationType)
-			ctx.Abort()
-			return
+		switch {
+		case currentAuthorizationType == authorizationType:
+			accessToken := fields[1]
+			payload, err = auth.VerifyToken(ctx, accessToken)
+		case currentAuthorizationType == apiKeyAuthorizationType && len(scopes) > 0:
+			apiKey := fields[1]
+			payload, err = auth.VerifyAPIKey(ctx, apiKey, scopes...)
+		default:
+			err = domain.ErrInvalidAuthorizationType
 		}
-
-		accessToken := fields[1]
-		payload, err := token.VerifyToken(accessToken)
 		if err != nil {
 			handleError(ctx, err)
 			ctx.Abort()
@@ -56,3 +75,53 @@ func AuthBeerMiddleware(token ports.ITokenService) gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// VerifiedEmailMiddleware reject users without a verified email when required is true,
+// must be used after AuthBeerMiddleware
+func VerifiedEmailMiddleware(required bool) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !required {
+			ctx.Next()
+			return
+		}
+
+		payload := getAuthPayload(ctx, authorizationPayloadKey)
+		if !payload.Verified {
+			handleError(ctx, domain.ErrEmailNotVerified)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// AdminMiddleware reject users that are not admins, tokens of an impersonation and api keys
+// never reach admin routes, must be used after AuthBeerMiddleware
+func AdminMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := getAuthPayload(ctx, authorizationPayloadKey)
+		if payload.Role != domain.RoleAdmin || payload.ImpersonatorID != nil || payload.APIKeyID != nil {
+			handleError(ctx, domain.ErrForbidden)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// NotImpersonatedMiddleware reject tokens of an impersonation, an admin acting as a user
+// must not change or create its credentials or the email they are sent to, must be used after AuthBeerMiddleware
+func NotImpersonatedMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := getAuthPayload(ctx, authorizationPayloadKey)
+		if payload.ImpersonatorID != nil {
+			handleError(ctx, domain.ErrForbidden)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
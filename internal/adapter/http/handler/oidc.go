@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+type OIDCHandler struct {
+	svc ports.IOIDCService
+}
+
+func NewOIDCHandler(oidcService ports.IOIDCService) *OIDCHandler {
+	return &OIDCHandler{
+		svc: oidcService,
+	}
+}
+
+// GetProviders go-blog
+//
+//	@Summary		List identity providers
+//	@Description	List the names of the OpenID Connect providers users can sign in with.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response{data=[]string}	"Provider names"
+//	@Router			/auth/oidc/providers [get]
+func (oh *OIDCHandler) GetProviders(ctx *gin.Context) {
+	handleSuccess(ctx, oh.svc.Providers())
+}
+
+// Authorize go-blog
+//
+//	@Summary		Start an external login
+//	@Description	Returns the URL of the provider to redirect the user to. The provider redirects back to the configured frontend page with "code" and "state".
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			provider	path		string									true	"Provider name"
+//	@Success		200			{object}	response{data=oidcAuthorizeResponse}	"Provider authorization URL"
+//	@Failure		404			{object}	errorResponse							"Provider not found"
+//	@Failure		500			{object}	errorResponse							"Internal server error"
+//	@Router			/auth/oidc/{provider}/authorize [post]
+func (oh *OIDCHandler) Authorize(ctx *gin.Context) {
+	url, err := oh.svc.Authorize(ctx, ctx.Param("provider"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newOIDCAuthorizeResponse(url)
+	handleSuccess(ctx, res)
+}
+
+type oidcCallbackRequest struct {
+	Code  string `json:"code" binding:"required" example:"4/0AX4XfWj"`
+	State string `json:"state" binding:"required" example:"bW9ja2VkIHN0YXRl"`
+}
+
+// Callback go-blog
+//
+//	@Summary		Finish an external login
+//	@Description	Exchanges the code returned by the provider, signs in the linked user, links the account with the same verified email or creates a new user.
+//	@Description	Returns an access token, or a two-factor challenge like /auth/login.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			provider	path		string						true	"Provider name"
+//	@Param			request		body		oidcCallbackRequest			true	"OIDC callback request body"
+//	@Success		200			{object}	response{data=authResponse}	"Successfully logged in"
+//	@Failure		400			{object}	errorResponse				"Validation error or invalid state"
+//	@Failure		401			{object}	errorResponse				"Provider response rejected"
+//	@Failure		404			{object}	errorResponse				"Provider not found"
+//	@Failure		409			{object}	errorResponse				"An account with the unverified email exists"
+//	@Failure		500			{object}	errorResponse				"Internal server error"
+//	@Router			/auth/oidc/{provider}/callback [post]
+func (oh *OIDCHandler) Callback(ctx *gin.Context) {
+	var req oidcCallbackRequest
+
+	err := ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	result, err := oh.svc.Callback(ctx, ctx.Param("provider"), req.State, req.Code)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newLoginResponse(result)
+	handleLoginSuccess(ctx, res)
+}
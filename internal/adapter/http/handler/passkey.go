@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+type PasskeyHandler struct {
+	svc ports.IPasskeyService
+}
+
+func NewPasskeyHandler(passkeyService ports.IPasskeyService) *PasskeyHandler {
+	return &PasskeyHandler{
+		svc: passkeyService,
+	}
+}
+
+// BeginRegistration go-blog
+//
+//	@Summary		Start a passkey registration
+//	@Description	Returns the options to pass to navigator.credentials.create(), the ceremony must be finished before it expires.
+//	@Tags			passkeys
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response{data=object}	"Credential creation options"
+//	@Failure		401	{object}	errorResponse			"Unauthorized error"
+//	@Failure		500	{object}	errorResponse			"Internal server error"
+//	@Router			/auth/passkeys/register/begin [post]
+//	@Security		BearerAuth
+func (ph *PasskeyHandler) BeginRegistration(ctx *gin.Context) {
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	options, err := ph.svc.BeginRegistration(ctx, token.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, options)
+}
+
+type finishRegistrationRequest struct {
+	Name string `form:"name" binding:"max=64" example:"MacBook"`
+}
+
+// FinishRegistration go-blog
+//
+//	@Summary		Finish a passkey registration
+//	@Description	Verifies the credential returned by navigator.credentials.create() and stores the passkey.
+//	@Tags			passkeys
+//	@Accept			json
+//	@Produce		json
+//	@Param			name	query		string							false	"Passkey name"
+//	@Param			request	body		object							true	"PublicKeyCredential returned by the browser"
+//	@Success		200		{object}	response{data=passkeyResponse}	"Passkey registered"
+//	@Failure		400		{object}	errorResponse					"Validation error, invalid or expired response"
+//	@Failure		401		{object}	errorResponse					"Unauthorized error"
+//	@Failure		409		{object}	errorResponse					"Passkey already registered"
+//	@Failure		500		{object}	errorResponse					"Internal server error"
+//	@Router			/auth/passkeys/register/finish [post]
+//	@Security		BearerAuth
+func (ph *PasskeyHandler) FinishRegistration(ctx *gin.Context) {
+	var req finishRegistrationRequest
+
+	err := ctx.BindQuery(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	body, err := ctx.GetRawData()
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	passkey, err := ph.svc.FinishRegistration(ctx, token.ID, req.Name, body)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newPasskeyResponse(passkey)
+	handleSuccess(ctx, res)
+}
+
+// GetPasskeys go-blog
+//
+//	@Summary		List passkeys
+//	@Description	List the passkeys of the current user.
+//	@Tags			passkeys
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response{data=[]passkeyResponse}	"Passkeys"
+//	@Failure		401	{object}	errorResponse						"Unauthorized error"
+//	@Failure		500	{object}	errorResponse						"Internal server error"
+//	@Router			/auth/passkeys [get]
+//	@Security		BearerAuth
+func (ph *PasskeyHandler) GetPasskeys(ctx *gin.Context) {
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	passkeys, err := ph.svc.GetPasskeys(ctx, token.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := make([]passkeyResponse, 0, len(passkeys))
+	for i := range passkeys {
+		res = append(res, newPasskeyResponse(&passkeys[i]))
+	}
+
+	handleSuccess(ctx, res)
+}
+
+// DeletePasskey go-blog
+//
+//	@Summary		Delete a passkey
+//	@Description	Delete a passkey of the current user, it can not be used to sign in anymore.
+//	@Tags			passkeys
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string			true	"Passkey id"	format(uuid)
+//	@Success		200	{object}	response		"Passkey deleted"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/auth/passkeys/{id} [delete]
+//	@Security		BearerAuth
+func (ph *PasskeyHandler) DeletePasskey(ctx *gin.Context) {
+	paramId := ctx.Param("id")
+
+	id, err := uuid.Parse(paramId)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	err = ph.svc.DeletePasskey(ctx, token.ID, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+// BeginLogin go-blog
+//
+//	@Summary		Start a passkey login
+//	@Description	Returns the options to pass to navigator.credentials.get(), any passkey registered for this site can answer.
+//	@Tags			passkeys
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response{data=object}	"Credential request options"
+//	@Failure		500	{object}	errorResponse			"Internal server error"
+//	@Router			/auth/passkeys/login/begin [post]
+func (ph *PasskeyHandler) BeginLogin(ctx *gin.Context) {
+	options, err := ph.svc.BeginLogin(ctx)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, options)
+}
+
+// FinishLogin go-blog
+//
+//	@Summary		Finish a passkey login
+//	@Description	Verifies the assertion returned by navigator.credentials.get() and returns an access token.
+//	@Tags			passkeys
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		object						true	"PublicKeyCredential returned by the browser"
+//	@Success		200		{object}	response{data=authResponse}	"Successfully logged in"
+//	@Failure		400		{object}	errorResponse				"Validation error"
+//	@Failure		401		{object}	errorResponse				"Passkey authentication failed"
+//	@Failure		500		{object}	errorResponse				"Internal server error"
+//	@Router			/auth/passkeys/login/finish [post]
+func (ph *PasskeyHandler) FinishLogin(ctx *gin.Context) {
+	body, err := ctx.GetRawData()
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token, err := ph.svc.FinishLogin(ctx, body)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newAuthResponse(token)
+	handleLoginSuccess(ctx, res)
+}
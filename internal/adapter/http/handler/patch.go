@@ -0,0 +1,424 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const (
+	// mergePatchContentType is the content type of a JSON Merge Patch (RFC 7396)
+	mergePatchContentType = "application/merge-patch+json"
+	// jsonPatchContentType is the content type of a JSON Patch (RFC 6902)
+	jsonPatchContentType = "application/json-patch+json"
+)
+
+var (
+	errUnsupportedPatch = fmt.Errorf("content type must be %v or %v", mergePatchContentType, jsonPatchContentType)
+	// errInvalidPatch is wrapped by the errors of malformed patch documents
+	errInvalidPatch = errors.New("invalid patch")
+	// errPatchConflict is wrapped by the errors of patches that do not apply to the document
+	errPatchConflict = errors.New("patch can not be applied")
+)
+
+// jsonPatchOperation is an operation of a JSON Patch
+type jsonPatchOperation struct {
+	Op    string           `json:"op"`
+	Path  *string          `json:"path"`
+	From  *string          `json:"from"`
+	Value *json.RawMessage `json:"value"`
+}
+
+// bindPatch apply the patch in the request body to the document and bind the result into obj,
+// the patch format is selected by the content type. Fields missing from the result are left zero,
+// unknown fields are rejected and obj is validated with its binding tags.
+// The error is written and false returned if the patch is invalid.
+func bindPatch(ctx *gin.Context, doc map[string]interface{}, obj any) bool {
+	mediaType, _, _ := mime.ParseMediaType(ctx.GetHeader("Content-Type"))
+
+	var patched interface{}
+	var err error
+	switch mediaType {
+	case mergePatchContentType:
+		var patch interface{}
+		if err = decodePatch(ctx, &patch); err == nil {
+			patched = applyMergePatch(doc, patch)
+		}
+	case jsonPatchContentType:
+		var ops []jsonPatchOperation
+		if err = decodePatch(ctx, &ops); err == nil {
+			patched, err = applyJSONPatch(doc, ops)
+		}
+	default:
+		ctx.JSON(http.StatusUnsupportedMediaType, newErrorResponse([]string{errUnsupportedPatch.Error()}))
+		return false
+	}
+	if err != nil {
+		if errors.Is(err, errPatchConflict) {
+			ctx.JSON(http.StatusConflict, newErrorResponse([]string{err.Error()}))
+		} else {
+			validationError(ctx, err)
+		}
+		return false
+	}
+
+	// the patched document goes through the same checks as a request body
+	data, err := json.Marshal(patched)
+	if err != nil {
+		validationError(ctx, err)
+		return false
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(obj); err != nil {
+		validationError(ctx, err)
+		return false
+	}
+	if err := binding.Validator.ValidateStruct(obj); err != nil {
+		validationError(ctx, err)
+		return false
+	}
+
+	return true
+}
+
+// decodePatch decode the request body into the patch
+func decodePatch(ctx *gin.Context, patch any) error {
+	decoder := json.NewDecoder(ctx.Request.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(patch); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidPatch, err)
+	}
+	return nil
+}
+
+// applyMergePatch return the target with the merge patch applied, as defined by RFC 7396
+func applyMergePatch(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = map[string]interface{}{}
+	}
+	result := make(map[string]interface{}, len(targetObj))
+	for key, value := range targetObj {
+		result[key] = value
+	}
+
+	for key, value := range patchObj {
+		if value == nil {
+			delete(result, key)
+			continue
+		}
+		result[key] = applyMergePatch(result[key], value)
+	}
+	return result
+}
+
+// applyJSONPatch return the document with the operations applied in order, as defined by RFC 6902,
+// the document is not modified
+func applyJSONPatch(doc interface{}, ops []jsonPatchOperation) (interface{}, error) {
+	doc = deepCopy(doc)
+
+	var err error
+	for i, op := range ops {
+		if op.Path == nil {
+			return nil, fmt.Errorf("%w: operation %v has no path", errInvalidPatch, i)
+		}
+		path := *op.Path
+
+		var value interface{}
+		switch op.Op {
+		case "add", "replace", "test":
+			if op.Value == nil {
+				return nil, fmt.Errorf("%w: operation %v has no value", errInvalidPatch, i)
+			}
+			decoder := json.NewDecoder(bytes.NewReader(*op.Value))
+			decoder.UseNumber()
+			if err := decoder.Decode(&value); err != nil {
+				return nil, fmt.Errorf("%w: operation %v: %v", errInvalidPatch, i, err)
+			}
+		case "move", "copy":
+			if op.From == nil {
+				return nil, fmt.Errorf("%w: operation %v has no from", errInvalidPatch, i)
+			}
+		case "remove":
+		default:
+			return nil, fmt.Errorf("%w: operation %v has an unknown op %q", errInvalidPatch, i, op.Op)
+		}
+
+		switch op.Op {
+		case "add":
+			doc, err = pointerAdd(doc, path, value)
+		case "remove":
+			doc, _, err = pointerRemove(doc, path)
+		case "replace":
+			doc, _, err = pointerRemove(doc, path)
+			if err == nil {
+				doc, err = pointerAdd(doc, path, value)
+			}
+		case "move":
+			if strings.HasPrefix(path, *op.From+"/") {
+				return nil, fmt.Errorf("%w: operation %v moves a value into itself", errInvalidPatch, i)
+			}
+			var moved interface{}
+			doc, moved, err = pointerRemove(doc, *op.From)
+			if err == nil {
+				doc, err = pointerAdd(doc, path, moved)
+			}
+		case "copy":
+			var copied interface{}
+			copied, err = pointerGet(doc, *op.From)
+			if err == nil {
+				doc, err = pointerAdd(doc, path, deepCopy(copied))
+			}
+		case "test":
+			var current interface{}
+			current, err = pointerGet(doc, path)
+			if err == nil && !jsonEqual(current, value) {
+				err = fmt.Errorf("%w: test of %q failed", errPatchConflict, path)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("operation %v: %w", i, err)
+		}
+	}
+
+	return doc, nil
+}
+
+// parsePointer split a JSON Pointer (RFC 6901) in unescaped reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: path %q must start with /", errInvalidPatch, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// arrayIndex parse the index of a token in an array of the length, "-" is the end of the array
+// and only allowed if end is true
+func arrayIndex(token string, length int, end bool) (int, error) {
+	if token == "-" && end {
+		return length, nil
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("%w: %q is not an array index", errPatchConflict, token)
+	}
+	max := length - 1
+	if end {
+		max = length
+	}
+	if index > max {
+		return 0, fmt.Errorf("%w: array index %v is out of range", errPatchConflict, index)
+	}
+	return index, nil
+}
+
+// pointerGet return the value the pointer references in the document
+func pointerGet(doc interface{}, pointer string) (interface{}, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	current := doc
+	for _, token := range tokens {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: path %q does not exist", errPatchConflict, pointer)
+			}
+			current = value
+		case []interface{}:
+			index, err := arrayIndex(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("%w: path %q does not exist", errPatchConflict, pointer)
+		}
+	}
+	return current, nil
+}
+
+// pointerAdd return the document with the value added at the pointer,
+// an existing member of an object is replaced and a value is inserted into an array
+func pointerAdd(doc interface{}, pointer string, value interface{}) (interface{}, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	parent, err := pointerGet(doc, pointer[:strings.LastIndex(pointer, "/")])
+	if err != nil {
+		return nil, err
+	}
+	last := tokens[len(tokens)-1]
+
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[last] = value
+		return doc, nil
+	case []interface{}:
+		index, err := arrayIndex(last, len(node), true)
+		if err != nil {
+			return nil, err
+		}
+		node = append(node, nil)
+		copy(node[index+1:], node[index:])
+		node[index] = value
+		return replaceParent(doc, pointer, node)
+	default:
+		return nil, fmt.Errorf("%w: path %q does not exist", errPatchConflict, pointer)
+	}
+}
+
+// pointerRemove return the document with the value at the pointer removed, and the removed value
+func pointerRemove(doc interface{}, pointer string) (interface{}, interface{}, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, doc, nil
+	}
+
+	parent, err := pointerGet(doc, pointer[:strings.LastIndex(pointer, "/")])
+	if err != nil {
+		return nil, nil, err
+	}
+	last := tokens[len(tokens)-1]
+
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		value, ok := node[last]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: path %q does not exist", errPatchConflict, pointer)
+		}
+		delete(node, last)
+		return doc, value, nil
+	case []interface{}:
+		index, err := arrayIndex(last, len(node), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		value := node[index]
+		node = append(node[:index:index], node[index+1:]...)
+		doc, err = replaceParent(doc, pointer, node)
+		return doc, value, err
+	default:
+		return nil, nil, fmt.Errorf("%w: path %q does not exist", errPatchConflict, pointer)
+	}
+}
+
+// replaceParent return the document with the array holding the value at the pointer replaced,
+// arrays change length so they are set again in their own parent
+func replaceParent(doc interface{}, pointer string, array []interface{}) (interface{}, error) {
+	parentPointer := pointer[:strings.LastIndex(pointer, "/")]
+	if parentPointer == "" {
+		return array, nil
+	}
+
+	grandParent, err := pointerGet(doc, parentPointer[:strings.LastIndex(parentPointer, "/")])
+	if err != nil {
+		return nil, err
+	}
+	tokens, _ := parsePointer(parentPointer)
+	last := tokens[len(tokens)-1]
+
+	switch node := grandParent.(type) {
+	case map[string]interface{}:
+		node[last] = array
+	case []interface{}:
+		index, err := arrayIndex(last, len(node), false)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = array
+	}
+	return doc, nil
+}
+
+// deepCopy copy the objects and arrays of a decoded JSON value
+func deepCopy(value interface{}) interface{} {
+	switch node := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(node))
+		for key, value := range node {
+			result[key] = deepCopy(value)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(node))
+		for i, value := range node {
+			result[i] = deepCopy(value)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// jsonEqual compare two decoded JSON values, numbers are compared by value
+func jsonEqual(a, b interface{}) bool {
+	if number, ok := a.(json.Number); ok {
+		a, _ = number.Float64()
+	}
+	if number, ok := b.(json.Number); ok {
+		b, _ = number.Float64()
+	}
+
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for key, value := range a {
+			other, ok := b[key]
+			if !ok || !jsonEqual(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !jsonEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
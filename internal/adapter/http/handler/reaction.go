@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type reactionURI struct {
+	ID   string `uri:"id" binding:"required,uuid"`
+	Kind string `uri:"kind" binding:"required"`
+}
+
+// AddReaction go-blog
+//
+//	@Summary		add reaction
+//	@Description	add a reaction of the current user to a blog, adding it again does nothing
+//	@Tags			blogs
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string								true	"Blog id"	format(uuid)
+//	@Param			kind	path		string								true	"Reaction kind, one of the configured kinds"
+//	@Success		200		{object}	response{data=reactionsResponse}	"Reaction counts of the blog"
+//	@Failure		400		{object}	errorResponse						"Validation error or unknown reaction kind"
+//	@Failure		401		{object}	errorResponse						"Unauthorized error"
+//	@Failure		403		{object}	errorResponse						"Forbidden error"
+//	@Failure		404		{object}	errorResponse						"Data not found error"
+//	@Failure		500		{object}	errorResponse						"Internal server error"
+//	@Router			/blogs/{id}/reactions/{kind} [put]
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+func (bh *BlogHandler) AddReaction(ctx *gin.Context) {
+	var uri reactionURI
+	err := ctx.BindUri(&uri)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+	id := uuid.MustParse(uri.ID)
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	counts, err := bh.reactions.AddReaction(ctx, id, token.ID, uri.Kind)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newReactionsResponse(id, counts)
+	handleSuccess(ctx, res)
+}
+
+// RemoveReaction go-blog
+//
+//	@Summary		remove reaction
+//	@Description	remove a reaction of the current user to a blog, removing it again does nothing
+//	@Tags			blogs
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string								true	"Blog id"	format(uuid)
+//	@Param			kind	path		string								true	"Reaction kind, one of the configured kinds"
+//	@Success		200		{object}	response{data=reactionsResponse}	"Reaction counts of the blog"
+//	@Failure		400		{object}	errorResponse						"Validation error or unknown reaction kind"
+//	@Failure		401		{object}	errorResponse						"Unauthorized error"
+//	@Failure		403		{object}	errorResponse						"Forbidden error"
+//	@Failure		404		{object}	errorResponse						"Data not found error"
+//	@Failure		500		{object}	errorResponse						"Internal server error"
+//	@Router			/blogs/{id}/reactions/{kind} [delete]
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+func (bh *BlogHandler) RemoveReaction(ctx *gin.Context) {
+	var uri reactionURI
+	err := ctx.BindUri(&uri)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+	id := uuid.MustParse(uri.ID)
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+
+	counts, err := bh.reactions.RemoveReaction(ctx, id, token.ID, uri.Kind)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newReactionsResponse(id, counts)
+	handleSuccess(ctx, res)
+}
+
+// setReactions add the reaction counts of the blogs to their responses, write the error and return false
+// if they can not be read
+func (bh *BlogHandler) setReactions(ctx *gin.Context, res ...*blogResponse) bool {
+	ids := make([]uuid.UUID, 0, len(res))
+	for _, blog := range res {
+		ids = append(ids, blog.ID)
+	}
+
+	counts, err := bh.reactions.GetCounts(ctx, ids...)
+	if err != nil {
+		handleError(ctx, err)
+		return false
+	}
+
+	for _, blog := range res {
+		blog.Reactions = counts[blog.ID]
+	}
+	return true
+}
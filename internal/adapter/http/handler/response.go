@@ -44,7 +44,9 @@ func newMeta(total, limit, skip int) meta {
 
 // authResponse type to auth response for auth handler
 type authResponse struct {
-	Token string `json:"token" example:"eyJJ9.eyJpEzNDR9.fUjDw0"`
+	Token       string `json:"token,omitempty" example:"eyJJ9.eyJpEzNDR9.fUjDw0"`
+	MFARequired bool   `json:"mfa_required,omitempty" example:"false"`
+	MFAToken    string `json:"mfa_token,omitempty" example:"eyJJ9.eyJwdXJwb3NlIjoibWZhIn0.fUjDw0"`
 }
 
 // newAuthResponse create a auth response for login handler
@@ -54,6 +56,53 @@ func newAuthResponse(token string) authResponse {
 	}
 }
 
+// newLoginResponse create a auth response from a login result
+func newLoginResponse(result *domain.LoginResult) authResponse {
+	return authResponse{
+		Token:       result.Token,
+		MFARequired: result.MFARequired,
+		MFAToken:    result.MFAToken,
+	}
+}
+
+// oidcAuthorizeResponse type to external login response for oidc handler
+type oidcAuthorizeResponse struct {
+	URL string `json:"url" example:"https://accounts.google.com/o/oauth2/v2/auth?client_id=..."`
+}
+
+// newOIDCAuthorizeResponse create an external login response
+func newOIDCAuthorizeResponse(url string) oidcAuthorizeResponse {
+	return oidcAuthorizeResponse{
+		URL: url,
+	}
+}
+
+// totpEnrollmentResponse type to TOTP enrolment response for mfa handler
+type totpEnrollmentResponse struct {
+	Secret string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	URI    string `json:"uri" example:"otpauth://totp/Go%20Blog:laplala?secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP&issuer=Go+Blog"`
+}
+
+// newTOTPEnrollmentResponse create a TOTP enrolment response
+func newTOTPEnrollmentResponse(enrollment *domain.TOTPEnrollment) totpEnrollmentResponse {
+	return totpEnrollmentResponse{
+		Secret: enrollment.Secret,
+		URI:    enrollment.URI,
+	}
+}
+
+// recoveryCodesResponse type to recovery codes response for mfa handler
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes" example:"0a1b2-c3d4e"`
+}
+
+// newRecoveryCodesResponse create a recovery codes response
+func newRecoveryCodesResponse(codes []string) recoveryCodesResponse {
+	return recoveryCodesResponse{
+		RecoveryCodes: codes,
+	}
+}
+
 // userResponse type to user response for user handler
 type userResponse struct {
 	ID        uuid.UUID `json:"id" example:"39833b12-a044-46f5-8abd-47c47345d458"`
@@ -72,26 +121,302 @@ func newUserResponse(user *domain.User) userResponse {
 	}
 }
 
+// adminUserResponse type to user response for admin handler
+type adminUserResponse struct {
+	ID            uuid.UUID         `json:"id" example:"39833b12-a044-46f5-8abd-47c47345d458"`
+	Username      string            `json:"username" example:"laplala"`
+	Email         string            `json:"email,omitempty" example:"laplala@example.com"`
+	EmailVerified bool              `json:"email_verified" example:"true"`
+	TOTPEnabled   bool              `json:"totp_enabled" example:"false"`
+	Role          domain.UserRole   `json:"role" example:"user"`
+	Status        domain.UserStatus `json:"status" example:"active"`
+	UpdatedAt     time.Time         `json:"updated_at" example:"1970-01-01T00:00:00Z"`
+	CreatedAt     time.Time         `json:"created_at" example:"1970-01-01T00:00:00Z"`
+	DeletedAt     *time.Time        `json:"deleted_at,omitempty" example:"1970-01-01T00:00:00Z"`
+}
+
+// newAdminUserResponse create user response for admin handler
+func newAdminUserResponse(user *domain.User) adminUserResponse {
+	return adminUserResponse{
+		ID:            user.ID,
+		Username:      user.Name,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		TOTPEnabled:   user.TOTPEnabled,
+		Role:          user.Role,
+		Status:        user.Status,
+		UpdatedAt:     user.UpdatedAt,
+		CreatedAt:     user.CreatedAt,
+		DeletedAt:     user.DeletedAt,
+	}
+}
+
+// listUsersResponse type to users response for admin handler
+type listUsersResponse struct {
+	Meta  meta                `json:"meta"`
+	Users []adminUserResponse `json:"users"`
+}
+
+// newListUsersResponse create users response for admin handler
+func newListUsersResponse(meta meta, users []adminUserResponse) listUsersResponse {
+	return listUsersResponse{
+		Meta:  meta,
+		Users: users,
+	}
+}
+
+// auditLogResponse type to audit log response for admin handler
+type auditLogResponse struct {
+	ID        uuid.UUID          `json:"id" example:"39833b12-a044-46f5-8abd-47c47345d458"`
+	ActorID   *uuid.UUID         `json:"actor_id,omitempty" example:"39833b12-a044-46f5-8abd-47c47345d458"`
+	Action    domain.AuditAction `json:"action" example:"user.suspend"`
+	TargetID  uuid.UUID          `json:"target_id" example:"39833b12-a044-46f5-8abd-47c47345d458"`
+	Reason    string             `json:"reason,omitempty" example:"spam"`
+	CreatedAt time.Time          `json:"created_at" example:"1970-01-01T00:00:00Z"`
+}
+
+// newAuditLogResponse create audit log response for admin handler
+func newAuditLogResponse(log *domain.AuditLog) auditLogResponse {
+	return auditLogResponse{
+		ID:        log.ID,
+		ActorID:   log.ActorID,
+		Action:    log.Action,
+		TargetID:  log.TargetID,
+		Reason:    log.Reason,
+		CreatedAt: log.CreatedAt,
+	}
+}
+
+// listAuditLogsResponse type to audit logs response for admin handler
+type listAuditLogsResponse struct {
+	Meta meta               `json:"meta"`
+	Logs []auditLogResponse `json:"logs"`
+}
+
+// newListAuditLogsResponse create audit logs response for admin handler
+func newListAuditLogsResponse(meta meta, logs []auditLogResponse) listAuditLogsResponse {
+	return listAuditLogsResponse{
+		Meta: meta,
+		Logs: logs,
+	}
+}
+
+// passkeyResponse type to passkey response for passkey handler
+type passkeyResponse struct {
+	ID         uuid.UUID  `json:"id" example:"39833b12-a044-46f5-8abd-47c47345d458"`
+	Name       string     `json:"name" example:"MacBook"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" example:"1970-01-01T00:00:00Z"`
+	CreatedAt  time.Time  `json:"created_at" example:"1970-01-01T00:00:00Z"`
+}
+
+// newPasskeyResponse create passkey response for passkey handler
+func newPasskeyResponse(passkey *domain.Passkey) passkeyResponse {
+	return passkeyResponse{
+		ID:         passkey.ID,
+		Name:       passkey.Name,
+		LastUsedAt: passkey.LastUsedAt,
+		CreatedAt:  passkey.CreatedAt,
+	}
+}
+
+// apiKeyResponse type to api key response for api key handler
+type apiKeyResponse struct {
+	ID         uuid.UUID            `json:"id" example:"39833b12-a044-46f5-8abd-47c47345d458"`
+	Name       string               `json:"name" example:"release notes CI"`
+	Prefix     string               `json:"prefix" example:"gbk_3q2-7wEv"`
+	Scopes     []domain.APIKeyScope `json:"scopes" example:"blogs:write"`
+	ExpiresAt  *time.Time           `json:"expires_at,omitempty" example:"1970-01-01T00:00:00Z"`
+	LastUsedAt *time.Time           `json:"last_used_at,omitempty" example:"1970-01-01T00:00:00Z"`
+	CreatedAt  time.Time            `json:"created_at" example:"1970-01-01T00:00:00Z"`
+}
+
+// newAPIKeyResponse create api key response for api key handler
+func newAPIKeyResponse(key *domain.APIKey) apiKeyResponse {
+	return apiKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+// createdAPIKeyResponse type to a new api key response, the only response with the key
+type createdAPIKeyResponse struct {
+	apiKeyResponse
+	Key string `json:"key" example:"gbk_3q2-7wEvXj0aQ2Jc7m9hYyPZbS1uKx4nT8dLfRoG6vE"`
+}
+
+// newCreatedAPIKeyResponse create a new api key response for api key handler
+func newCreatedAPIKeyResponse(key *domain.APIKey, rawKey string) createdAPIKeyResponse {
+	return createdAPIKeyResponse{
+		apiKeyResponse: newAPIKeyResponse(key),
+		Key:            rawKey,
+	}
+}
+
 // blogResponse type to blog response for blog handler
 type blogResponse struct {
-	ID        uuid.UUID `json:"id" example:"39833b12-a044-46f5-8abd-47c47345d458"`
-	Title     string    `json:"title" example:"how to ..."`
-	Text      string    `json:"text,omitempty" example:"to do ..."`
-	AuthorID  uuid.UUID `json:"author_id"`
-	UpdatedAt time.Time `json:"updated_at" example:"1970-01-01T00:00:00Z"`
-	CreatedAt time.Time `json:"created_at" example:"1970-01-01T00:00:00Z"`
+	ID          uuid.UUID          `json:"id" example:"39833b12-a044-46f5-8abd-47c47345d458"`
+	Title       string             `json:"title" example:"how to ..."`
+	Slug        string             `json:"slug" example:"how-to"`
+	Tags        []string           `json:"tags,omitempty" example:"go,web"`
+	Text        string             `json:"text,omitempty" example:"to do ..."`
+	TextHTML    string             `json:"text_html,omitempty" example:"<p>to do ...</p>"`
+	TOC         []tocEntryResponse `json:"toc,omitempty"`
+	Summary     string             `json:"summary,omitempty" example:"A short guide to ..."`
+	Excerpt     string             `json:"excerpt" example:"to do ..."` // the summary or the first paragraph of the text
+	WordCount   int                `json:"word_count" example:"1200"`
+	ReadingTime int                `json:"reading_time" example:"6"` // in minutes
+	Reactions   map[string]int     `json:"reactions,omitempty"`      // count of each kind of reaction
+	AuthorID    uuid.UUID          `json:"author_id"`
+	Version     int                `json:"version" example:"3"`
+	UpdatedAt   time.Time          `json:"updated_at" example:"1970-01-01T00:00:00Z"`
+	CreatedAt   time.Time          `json:"created_at" example:"1970-01-01T00:00:00Z"`
+	DeletedAt   *time.Time         `json:"deleted_at,omitempty" example:"1970-01-01T00:00:00Z"`
 }
 
 // newBlogResponse create blog response for blog handler
 func newBlogResponse(blog *domain.Blog) blogResponse {
+	excerpt := blog.Summary
+	if excerpt == "" {
+		excerpt = blog.Excerpt
+	}
+
 	return blogResponse{
-		ID:        blog.ID,
-		Title:     blog.Title,
-		Text:      blog.Text,
-		AuthorID:  blog.AuthorID,
-		UpdatedAt: blog.UpdatedAt,
-		CreatedAt: blog.CreatedAt,
+		ID:          blog.ID,
+		Title:       blog.Title,
+		Slug:        blog.Slug,
+		Tags:        blog.Tags,
+		Text:        blog.Text,
+		Summary:     blog.Summary,
+		Excerpt:     excerpt,
+		WordCount:   blog.WordCount,
+		ReadingTime: blog.ReadingTime,
+		AuthorID:    blog.AuthorID,
+		Version:     blog.Version,
+		UpdatedAt:   blog.UpdatedAt,
+		CreatedAt:   blog.CreatedAt,
+		DeletedAt:   blog.DeletedAt,
+	}
+}
+
+// reactionsResponse type to the reaction counts of a blog
+type reactionsResponse struct {
+	BlogID    uuid.UUID      `json:"blog_id" example:"39833b12-a044-46f5-8abd-47c47345d458"`
+	Reactions map[string]int `json:"reactions"`
+}
+
+// newReactionsResponse create the reaction counts response of a blog
+func newReactionsResponse(blogID uuid.UUID, counts domain.ReactionCounts) reactionsResponse {
+	return reactionsResponse{
+		BlogID:    blogID,
+		Reactions: counts,
+	}
+}
+
+// dailyViewsResponse type to the views of a blog on a day
+type dailyViewsResponse struct {
+	Day   string `json:"day" example:"1970-01-01"`
+	Views int    `json:"views" example:"42"`
+}
+
+// referrerViewsResponse type to the views of a blog from a referrer host
+type referrerViewsResponse struct {
+	Referrer string `json:"referrer" example:"news.ycombinator.com"` // direct without referrer, internal from the site
+	Views    int    `json:"views" example:"42"`
+}
+
+// blogStatsResponse type to the views of a blog for blog handler
+type blogStatsResponse struct {
+	BlogID     uuid.UUID               `json:"blog_id" example:"39833b12-a044-46f5-8abd-47c47345d458"`
+	From       string                  `json:"from" example:"1970-01-01"`
+	To         string                  `json:"to" example:"1970-01-30"`
+	TotalViews int                     `json:"total_views" example:"1260"`
+	Days       []dailyViewsResponse    `json:"days"`
+	Referrers  []referrerViewsResponse `json:"referrers"`
+}
+
+// newBlogStatsResponse create blog stats response for blog handler
+func newBlogStatsResponse(stats *domain.BlogStats) blogStatsResponse {
+	days := make([]dailyViewsResponse, 0, len(stats.Days))
+	for _, day := range stats.Days {
+		days = append(days, dailyViewsResponse{
+			Day:   day.Day,
+			Views: day.Views,
+		})
+	}
+
+	referrers := make([]referrerViewsResponse, 0, len(stats.Referrers))
+	for _, referrer := range stats.Referrers {
+		referrers = append(referrers, referrerViewsResponse{
+			Referrer: referrer.Referrer,
+			Views:    referrer.Views,
+		})
 	}
+
+	return blogStatsResponse{
+		BlogID:     stats.BlogID,
+		From:       stats.From,
+		To:         stats.To,
+		TotalViews: stats.TotalViews,
+		Days:       days,
+		Referrers:  referrers,
+	}
+}
+
+// tocEntryResponse type of a heading in the table of contents of a rendered blog
+type tocEntryResponse struct {
+	ID       string             `json:"id" example:"getting-started"`
+	Title    string             `json:"title" example:"Getting started"`
+	Level    int                `json:"level" example:"2"`
+	Children []tocEntryResponse `json:"children,omitempty"`
+}
+
+// newTOCEntriesResponse create the table of contents response of a rendered blog
+func newTOCEntriesResponse(entries []domain.TOCEntry) []tocEntryResponse {
+	res := make([]tocEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		res = append(res, tocEntryResponse{
+			ID:       entry.ID,
+			Title:    entry.Title,
+			Level:    entry.Level,
+			Children: newTOCEntriesResponse(entry.Children),
+		})
+	}
+	return res
+}
+
+// codeBlockResponse type of a fenced code block of a blog, for copy buttons
+type codeBlockResponse struct {
+	Index           int    `json:"index" example:"0"`
+	Language        string `json:"language" example:"go"`
+	Title           string `json:"title,omitempty" example:"main.go"`
+	Code            string `json:"code" example:"fmt.Println(\"hello\")\n"`
+	Lines           int    `json:"lines" example:"1"`
+	LineNumbers     bool   `json:"line_numbers" example:"true"`
+	LineNumberStart int    `json:"line_number_start" example:"1"`
+	HighlightLines  []int  `json:"highlight_lines,omitempty" example:"1"`
+}
+
+// newCodeBlocksResponse create the code blocks response of a rendered blog
+func newCodeBlocksResponse(blocks []domain.CodeBlock) []codeBlockResponse {
+	res := make([]codeBlockResponse, 0, len(blocks))
+	for _, block := range blocks {
+		res = append(res, codeBlockResponse{
+			Index:           block.Index,
+			Language:        block.Language,
+			Title:           block.Title,
+			Code:            block.Code,
+			Lines:           block.Lines,
+			LineNumbers:     block.LineNumbers,
+			LineNumberStart: block.LineNumberStart,
+			HighlightLines:  block.HighlightLines,
+		})
+	}
+	return res
 }
 
 // listBlogsResponse type to blogs response for blog handler
@@ -108,6 +433,177 @@ func newListBlogsResponse(meta meta, blogs []blogResponse) listBlogsResponse {
 	}
 }
 
+// bookmarkResponse type to bookmark response for bookmark handler
+type bookmarkResponse struct {
+	BlogID    uuid.UUID    `json:"blog_id" example:"39833b12-a044-46f5-8abd-47c47345d458"`
+	Note      string       `json:"note" example:"read the part on caching again"`
+	CreatedAt time.Time    `json:"created_at" example:"1970-01-01T00:00:00Z"`
+	Blog      blogResponse `json:"blog"` // summary of the blog, without its text
+}
+
+// newBookmarkResponse create bookmark response for bookmark handler
+func newBookmarkResponse(bookmark *domain.Bookmark) bookmarkResponse {
+	blog := newBlogResponse(bookmark.Blog)
+	blog.Text = ""
+
+	return bookmarkResponse{
+		BlogID:    bookmark.BlogID,
+		Note:      bookmark.Note,
+		CreatedAt: bookmark.CreatedAt,
+		Blog:      blog,
+	}
+}
+
+// listBookmarksResponse type to paginated bookmarks response for bookmark handler
+type listBookmarksResponse struct {
+	Meta      meta               `json:"meta"`
+	Bookmarks []bookmarkResponse `json:"bookmarks"`
+}
+
+// newListBookmarksResponse create bookmarks response for bookmark handler
+func newListBookmarksResponse(meta meta, bookmarks []bookmarkResponse) listBookmarksResponse {
+	return listBookmarksResponse{
+		Meta:      meta,
+		Bookmarks: bookmarks,
+	}
+}
+
+// followCountsResponse type to the follow counts of a user for follow handler
+type followCountsResponse struct {
+	UserID    uuid.UUID `json:"user_id" example:"39833b12-a044-46f5-8abd-47c47345d458"`
+	Followers int       `json:"followers" example:"42"`
+	Following int       `json:"following" example:"7"`
+}
+
+// newFollowCountsResponse create follow counts response for follow handler
+func newFollowCountsResponse(counts *domain.FollowCounts) followCountsResponse {
+	return followCountsResponse{
+		UserID:    counts.UserID,
+		Followers: counts.Followers,
+		Following: counts.Following,
+	}
+}
+
+// followResponse type to a follower or followed author for follow handler
+type followResponse struct {
+	User       userResponse `json:"user"`
+	FollowedAt time.Time    `json:"followed_at" example:"1970-01-01T00:00:00Z"`
+}
+
+// newFollowResponse create follow response for follow handler
+func newFollowResponse(follow *domain.Follow) followResponse {
+	return followResponse{
+		User:       newUserResponse(follow.User),
+		FollowedAt: follow.CreatedAt,
+	}
+}
+
+// listFollowsResponse type to paginated follows response for follow handler
+type listFollowsResponse struct {
+	Meta  meta             `json:"meta"`
+	Users []followResponse `json:"users"`
+}
+
+// newListFollowsResponse create follows response for follow handler
+func newListFollowsResponse(meta meta, follows []followResponse) listFollowsResponse {
+	return listFollowsResponse{
+		Meta:  meta,
+		Users: follows,
+	}
+}
+
+// timelineResponse type to a timeline page for follow handler
+type timelineResponse struct {
+	Blogs      []blogResponse `json:"blogs"`
+	NextCursor string         `json:"next_cursor,omitempty" example:"MTcwMDAwMDAwMDAwMDAwMDAwMF8zOTgzM2IxMi1hMDQ0LTQ2ZjUtOGFiZC00N2M0NzM0NWQ0NTg"` // none on the last page
+}
+
+// newTimelineResponse create timeline response for follow handler
+func newTimelineResponse(timeline *domain.Timeline) timelineResponse {
+	blogs := make([]blogResponse, 0, len(timeline.Blogs))
+	for i := range timeline.Blogs {
+		blogs = append(blogs, newBlogResponse(&timeline.Blogs[i]))
+	}
+
+	res := timelineResponse{
+		Blogs: blogs,
+	}
+	if timeline.Next != nil {
+		res.NextCursor = formatTimelineCursor(timeline.Next)
+	}
+	return res
+}
+
+// blogRevisionResponse type to blog revision response for blog handler
+type blogRevisionResponse struct {
+	Number      int       `json:"number" example:"3"`
+	AuthorID    uuid.UUID `json:"author_id" example:"39833b12-a044-46f5-8abd-47c47345d458"`
+	Title       string    `json:"title" example:"how to ..."`
+	Text        string    `json:"text,omitempty" example:"to do ..."`
+	ContentHash string    `json:"content_hash" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	CreatedAt   time.Time `json:"created_at" example:"1970-01-01T00:00:00Z"`
+}
+
+// newBlogRevisionResponse create blog revision response for blog handler
+func newBlogRevisionResponse(revision *domain.BlogRevision) blogRevisionResponse {
+	return blogRevisionResponse{
+		Number:      revision.Number,
+		AuthorID:    revision.AuthorID,
+		Title:       revision.Title,
+		Text:        revision.Text,
+		ContentHash: revision.ContentHash,
+		CreatedAt:   revision.CreatedAt,
+	}
+}
+
+// listBlogRevisionsResponse type to blog revisions response for blog handler
+type listBlogRevisionsResponse struct {
+	Meta      meta                   `json:"meta"`
+	Revisions []blogRevisionResponse `json:"revisions"`
+}
+
+// newListBlogRevisionsResponse create blog revisions response for blog handler
+func newListBlogRevisionsResponse(meta meta, revisions []blogRevisionResponse) listBlogRevisionsResponse {
+	return listBlogRevisionsResponse{
+		Meta:      meta,
+		Revisions: revisions,
+	}
+}
+
+// diffLineResponse type to diff line response for blog handler
+type diffLineResponse struct {
+	Op   domain.DiffOp `json:"op" example:"insert" enums:"equal,insert,delete"`
+	Text string        `json:"text" example:"to do ..."`
+}
+
+// revisionDiffResponse type to revision diff response for blog handler
+type revisionDiffResponse struct {
+	From      int                `json:"from" example:"1"`
+	To        int                `json:"to" example:"3"`
+	FromTitle string             `json:"from_title" example:"how to ..."`
+	ToTitle   string             `json:"to_title" example:"how to ..."`
+	Lines     []diffLineResponse `json:"lines"`
+}
+
+// newRevisionDiffResponse create revision diff response for blog handler
+func newRevisionDiffResponse(diff *domain.RevisionDiff) revisionDiffResponse {
+	lines := make([]diffLineResponse, 0, len(diff.Lines))
+	for _, line := range diff.Lines {
+		lines = append(lines, diffLineResponse{
+			Op:   line.Op,
+			Text: line.Text,
+		})
+	}
+
+	return revisionDiffResponse{
+		From:      diff.From,
+		To:        diff.To,
+		FromTitle: diff.FromTitle,
+		ToTitle:   diff.ToTitle,
+		Lines:     lines,
+	}
+}
+
 // errorStatusMap is a map of defined error messages and their corresponding http status codes
 var errorStatusMap = map[error]int{
 	domain.ErrInternal:                   http.StatusInternalServerError,
@@ -120,8 +616,36 @@ var errorStatusMap = map[error]int{
 	domain.ErrInvalidAuthorizationType:   http.StatusUnauthorized,
 	domain.ErrInvalidToken:               http.StatusUnauthorized,
 	domain.ErrExpiredToken:               http.StatusUnauthorized,
+	domain.ErrRevokedToken:               http.StatusUnauthorized,
 	domain.ErrForbidden:                  http.StatusForbidden,
 	domain.ErrNoUpdatedData:              http.StatusBadRequest,
+	domain.ErrWeakPassword:               http.StatusBadRequest,
+	domain.ErrIncorrectPassword:          http.StatusForbidden,
+	domain.ErrInvalidActionToken:         http.StatusBadRequest,
+	domain.ErrEmailNotVerified:           http.StatusForbidden,
+	domain.ErrEmailAlreadyVerified:       http.StatusBadRequest,
+	domain.ErrNoEmail:                    http.StatusBadRequest,
+	domain.ErrInvalidMFACode:             http.StatusUnauthorized,
+	domain.ErrMFAAlreadyEnabled:          http.StatusConflict,
+	domain.ErrMFANotEnabled:              http.StatusBadRequest,
+	domain.ErrMFANotEnrolled:             http.StatusBadRequest,
+	domain.ErrInvalidPasskey:             http.StatusBadRequest,
+	domain.ErrPasskeyLoginFailed:         http.StatusUnauthorized,
+	domain.ErrTooManyRequests:            http.StatusTooManyRequests,
+	domain.ErrInvalidOIDCState:           http.StatusBadRequest,
+	domain.ErrOIDCLoginFailed:            http.StatusUnauthorized,
+	domain.ErrIdentityLinkConflict:       http.StatusConflict,
+	domain.ErrInvalidAPIKey:              http.StatusUnauthorized,
+	domain.ErrInsufficientScope:          http.StatusForbidden,
+	domain.ErrInvalidCSRFToken:           http.StatusForbidden,
+	domain.ErrAccountSuspended:           http.StatusForbidden,
+	domain.ErrAdminTarget:                http.StatusForbidden,
+	domain.ErrPreconditionFailed:         http.StatusPreconditionFailed,
+	domain.ErrPreconditionRequired:       http.StatusPreconditionRequired,
+	domain.ErrInvalidSlug:                http.StatusBadRequest,
+	domain.ErrInvalidTag:                 http.StatusBadRequest,
+	domain.ErrUnknownReaction:            http.StatusBadRequest,
+	domain.ErrSelfFollow:                 http.StatusBadRequest,
 }
 
 // handleSuccess write success response with status code 200 mess Success and data
@@ -132,6 +656,19 @@ func handleSuccess(ctx *gin.Context, data any) {
 
 // handleError write error response
 func handleError(ctx *gin.Context, err error) {
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		res := newErrorResponse(praseError(validationErr.Err))
+		res.Errors = newFieldErrorsResponse(validationErr.Fields)
+
+		statusCode, ok := errorStatusMap[validationErr.Err]
+		if !ok {
+			statusCode = http.StatusBadRequest
+		}
+		ctx.JSON(statusCode, res)
+		return
+	}
+
 	statusCode, ok := errorStatusMap[err]
 	if !ok {
 		statusCode = http.StatusInternalServerError
@@ -144,8 +681,9 @@ func handleError(ctx *gin.Context, err error) {
 
 // errorResponse type of error response
 type errorResponse struct {
-	Success  bool     `json:"success" example:"false"`
-	Messages []string `json:"messages" example:"data not found"`
+	Success  bool                 `json:"success" example:"false"`
+	Messages []string             `json:"messages" example:"data not found"`
+	Errors   []fieldErrorResponse `json:"errors,omitempty"`
 }
 
 // newErrorResponse create an new error response
@@ -156,6 +694,26 @@ func newErrorResponse(errMegs []string) errorResponse {
 	}
 }
 
+// fieldErrorResponse type of a structured validation error of a field
+type fieldErrorResponse struct {
+	Field   string `json:"field" example:"password"`
+	Rule    string `json:"rule" example:"min_length"`
+	Message string `json:"message" example:"password must be at least 8 characters long"`
+}
+
+// newFieldErrorsResponse create field errors response from domain field errors
+func newFieldErrorsResponse(fields []domain.FieldError) []fieldErrorResponse {
+	res := make([]fieldErrorResponse, 0, len(fields))
+	for _, field := range fields {
+		res = append(res, fieldErrorResponse{
+			Field:   field.Field,
+			Rule:    field.Rule,
+			Message: field.Message,
+		})
+	}
+	return res
+}
+
 // validationError handle validation error, write err response
 func validationError(ctx *gin.Context, err error) {
 	errMegs := praseError(err)
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/util"
+)
+
+var (
+	// sessionCookieName is the name of the HttpOnly cookie holding the access token
+	sessionCookieName = "session"
+	// csrfCookieName is the name of the cookie holding the CSRF token, readable by scripts
+	csrfCookieName = "csrf_token"
+	// csrfHeaderKey is the header the CSRF token must be copied to
+	csrfHeaderKey = "X-CSRF-Token"
+	// sessionOptionsKey is the key for cookie session options in the context
+	sessionOptionsKey = "session_options"
+)
+
+// csrfTokenSize is the number of random bytes of a CSRF token
+const csrfTokenSize = 32
+
+// sessionOptions are the cookie attributes of the session and CSRF cookies
+type sessionOptions struct {
+	domain         string
+	secure         bool
+	sameSite       http.SameSite
+	allowedOrigins map[string]bool
+}
+
+// CookieSessionMiddleware enable cookie sessions: logins set an HttpOnly session cookie that
+// AuthBeerMiddleware accepts when no Authorization header is sent. State-changing requests
+// carrying the session cookie must come from an allowed origin and send the CSRF cookie
+// back in the X-CSRF-Token header.
+func CookieSessionMiddleware(domainName string, secure bool, sameSite http.SameSite, allowedOrigins []string) gin.HandlerFunc {
+	opts := &sessionOptions{
+		domain:         domainName,
+		secure:         secure,
+		sameSite:       sameSite,
+		allowedOrigins: make(map[string]bool, len(allowedOrigins)),
+	}
+	for _, origin := range allowedOrigins {
+		opts.allowedOrigins[origin] = true
+	}
+
+	return func(ctx *gin.Context) {
+		ctx.Set(sessionOptionsKey, opts)
+
+		csrfCookie, _ := ctx.Cookie(csrfCookieName)
+		if csrfCookie == "" {
+			if err := setCSRFCookie(ctx, opts); err != nil {
+				handleError(ctx, domain.ErrInternal)
+				ctx.Abort()
+				return
+			}
+		}
+
+		if isSafeMethod(ctx.Request.Method) || !usesSessionCookie(ctx) {
+			ctx.Next()
+			return
+		}
+
+		origin := ctx.GetHeader("Origin")
+		if origin != "" && !opts.allowedOrigins[origin] {
+			handleError(ctx, domain.ErrInvalidCSRFToken)
+			ctx.Abort()
+			return
+		}
+
+		csrfHeader := ctx.GetHeader(csrfHeaderKey)
+		if csrfCookie == "" || subtle.ConstantTimeCompare([]byte(csrfHeader), []byte(csrfCookie)) != 1 {
+			handleError(ctx, domain.ErrInvalidCSRFToken)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// handleLoginSuccess write a login response, with cookie sessions enabled the access token of
+// browser requests is set in the session cookie instead of the response body, clients that do
+// not send an Origin header are not browsers and keep receiving it in the body
+func handleLoginSuccess(ctx *gin.Context, res authResponse) {
+	opts := getSessionOptions(ctx)
+	if opts == nil || res.Token == "" || ctx.GetHeader("Origin") == "" {
+		handleSuccess(ctx, res)
+		return
+	}
+
+	// a new session gets a new CSRF token
+	if err := setCSRFCookie(ctx, opts); err != nil {
+		handleError(ctx, domain.ErrInternal)
+		return
+	}
+
+	setCookie(ctx, opts, sessionCookieName, res.Token, 0, true)
+	res.Token = ""
+
+	handleSuccess(ctx, res)
+}
+
+// clearSessionCookie delete the session cookie if cookie sessions are enabled
+func clearSessionCookie(ctx *gin.Context) {
+	opts := getSessionOptions(ctx)
+	if opts == nil {
+		return
+	}
+
+	setCookie(ctx, opts, sessionCookieName, "", -1, true)
+}
+
+// getSessionCookie get the access token of the session cookie if cookie sessions are enabled
+func getSessionCookie(ctx *gin.Context) string {
+	if getSessionOptions(ctx) == nil {
+		return ""
+	}
+
+	token, _ := ctx.Cookie(sessionCookieName)
+	return token
+}
+
+// getSessionOptions get the cookie session options, nil if cookie sessions are disabled
+func getSessionOptions(ctx *gin.Context) *sessionOptions {
+	opts, ok := ctx.Get(sessionOptionsKey)
+	if !ok {
+		return nil
+	}
+	return opts.(*sessionOptions)
+}
+
+// usesSessionCookie check if the request would be authenticated by the session cookie,
+// requests sending an Authorization header can not be forged by another site
+func usesSessionCookie(ctx *gin.Context) bool {
+	return ctx.GetHeader(authorizationHeaderKey) == "" && getSessionCookie(ctx) != ""
+}
+
+func setCSRFCookie(ctx *gin.Context, opts *sessionOptions) error {
+	token, err := util.GenerateToken(csrfTokenSize)
+	if err != nil {
+		return err
+	}
+
+	setCookie(ctx, opts, csrfCookieName, token, 0, false)
+	return nil
+}
+
+func setCookie(ctx *gin.Context, opts *sessionOptions, name, value string, maxAge int, httpOnly bool) {
+	ctx.SetSameSite(opts.sameSite)
+	ctx.SetCookie(name, value, maxAge, "/", opts.domain, opts.secure, httpOnly)
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
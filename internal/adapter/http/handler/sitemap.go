@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+const sitemapContentType = "application/xml; charset=utf-8"
+
+// SitemapHandler serve the sitemaps and robots.txt at the root of the server, out of the api documentation
+type SitemapHandler struct {
+	svc ports.ISitemapService
+}
+
+func NewSitemapHandler(sitemapService ports.ISitemapService) *SitemapHandler {
+	return &SitemapHandler{
+		svc: sitemapService,
+	}
+}
+
+// GetSitemap serve /sitemap.xml, the sitemap of the site or the index of its sitemaps
+func (sh *SitemapHandler) GetSitemap(ctx *gin.Context) {
+	sh.writeSitemap(ctx, 0)
+}
+
+// GetIndexedSitemap serve /sitemaps/{page}.xml, a sitemap of the index
+func (sh *SitemapHandler) GetIndexedSitemap(ctx *gin.Context) {
+	name, ok := strings.CutSuffix(ctx.Param("page"), ".xml")
+	page, err := strconv.Atoi(name)
+	if !ok || err != nil || page < 1 {
+		handleError(ctx, domain.ErrDataNotFound)
+		return
+	}
+
+	sh.writeSitemap(ctx, page)
+}
+
+// GetRobots serve /robots.txt
+func (sh *SitemapHandler) GetRobots(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(sh.svc.GetRobots(ctx)))
+}
+
+func (sh *SitemapHandler) writeSitemap(ctx *gin.Context, page int) {
+	sitemap, err := sh.svc.GetSitemap(ctx, page)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	writeDocument(ctx, sitemapContentType, sitemap.Content, sitemap.ETag, sitemap.UpdatedAt)
+}
@@ -19,7 +19,8 @@ func NewUserHandler(userService ports.IUserService) *UserHandler {
 
 type createUserRequest struct {
 	Username string `json:"username" binding:"required,min=3" example:"laplala" minLength:"3"`
-	Password string `json:"password" binding:"required,min=8" example:"12345678" minLength:"8"`
+	Email    string `json:"email" binding:"omitempty,email,max=254" example:"laplala@example.com"`
+	Password string `json:"password" binding:"required" example:"Laplala#2024"`
 }
 
 // CreateUser go-blog
@@ -31,7 +32,7 @@ type createUserRequest struct {
 //	@Produce		json
 //	@Param			request	body		createUserRequest			true	"Create User request body"
 //	@Success		200		{object}	response{data=userResponse}	"User created"
-//	@Failure		400		{object}	errorResponse				"Validation error"
+//	@Failure		400		{object}	errorResponse				"Validation error or password policy violation"
 //	@Failure		409		{object}	errorResponse				"Data conflict error"
 //	@Failure		500		{object}	errorResponse				"Internal server error"
 //	@Router			/users [post]
@@ -43,7 +44,11 @@ func (uh *UserHandler) CreateUser(ctx *gin.Context) {
 		return
 	}
 
-	createdUser, err := uh.svc.CreateUser(ctx, req.Username, req.Password)
+	createdUser, err := uh.svc.CreateUser(ctx, &domain.User{
+		Name:     req.Username,
+		Email:    req.Email,
+		Password: req.Password,
+	})
 	if err != nil {
 		handleError(ctx, err)
 		return
@@ -86,8 +91,8 @@ func (uh *UserHandler) GetUser(ctx *gin.Context) {
 }
 
 type updateUserRequest struct {
-	Username string `json:"username" binding:"required,min=3" example:"laplala" minLength:"3"`
-	Password string `json:"password" binding:"required,min=8" example:"12345678" minLength:"8"`
+	Username string `json:"username" binding:"omitempty,min=3" example:"laplala" minLength:"3"`
+	Email    string `json:"email" binding:"omitempty,email,max=254" example:"laplala@example.com"`
 }
 
 // UpdateUser go-blog
@@ -131,9 +136,9 @@ func (uh *UserHandler) UpdateUser(ctx *gin.Context) {
 	}
 
 	updateData := &domain.User{
-		ID:       token.ID,
-		Name:     req.Username,
-		Password: req.Password,
+		ID:    token.ID,
+		Name:  req.Username,
+		Email: req.Email,
 	}
 
 	updatedUser, err := uh.svc.UpdateUser(ctx, updateData)
@@ -146,10 +151,133 @@ func (uh *UserHandler) UpdateUser(ctx *gin.Context) {
 	handleSuccess(ctx, res)
 }
 
+// patchUserRequest is the patched user document, the email can be removed
+type patchUserRequest struct {
+	Username string `json:"username" binding:"required,min=3" example:"laplala" minLength:"3"`
+	Email    string `json:"email" binding:"omitempty,email,max=254" example:"laplala@example.com"`
+}
+
+// PatchUser go-blog
+//
+//	@Summary		patch user
+//	@Description	partially update user data with a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902) applied to {"username", "email"},
+//	@Description	a removed or null email removes the email of the user, a changed email has to be verified again
+//	@Tags			users
+//	@Accept			application/merge-patch+json,application/json-patch+json
+//	@Produce		json
+//	@Param			id		path		string						true	"User id" format(uuid)
+//	@Param			request	body		object						true	"Merge patch object or array of JSON Patch operations"
+//	@Success		200		{object}	response{data=userResponse}	"User updated"
+//	@Failure		400		{object}	errorResponse				"Validation error or invalid patch"
+//	@Failure		401		{object}	errorResponse				"Unauthorized error"
+//	@Failure		403		{object}	errorResponse				"Forbidden error"
+//	@Failure		409		{object}	errorResponse				"Data conflict error or a test operation failed"
+//	@Failure		415		{object}	errorResponse				"Unsupported patch content type"
+//	@Failure		500		{object}	errorResponse				"Internal server error"
+//	@Router			/users/{id} [patch]
+//	@Security		BearerAuth
+func (uh *UserHandler) PatchUser(ctx *gin.Context) {
+	paramId := ctx.Param("id")
+
+	id, err := uuid.Parse(paramId)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+	if token.ID != id {
+		handleError(ctx, domain.ErrForbidden)
+		return
+	}
+
+	user, err := uh.svc.GetUserByID(ctx, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	var req patchUserRequest
+	if !bindPatch(ctx, map[string]interface{}{"username": user.Name, "email": user.Email}, &req) {
+		return
+	}
+
+	patch := &domain.UserPatch{}
+	if req.Username != user.Name {
+		patch.Name = &req.Username
+	}
+	if req.Email != user.Email {
+		patch.Email = &req.Email
+	}
+
+	updatedUser, err := uh.svc.PatchUser(ctx, id, patch)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	res := newUserResponse(updatedUser)
+	handleSuccess(ctx, res)
+}
+
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required" example:"Laplala#2024"`
+	NewPassword     string `json:"new_password" binding:"required" example:"Laplala#2025"`
+}
+
+// ChangePassword go-blog
+//
+//	@Summary		change password
+//	@Description	change user password, the current password is required and all existing tokens of the user are revoked
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"User id"	format(uuid)
+//	@Param			request	body		changePasswordRequest	true	"Change password request body"
+//	@Success		200		{object}	response				"Password changed"
+//	@Failure		400		{object}	errorResponse			"Validation error or password policy violation"
+//	@Failure		401		{object}	errorResponse			"Unauthorized error"
+//	@Failure		403		{object}	errorResponse			"Forbidden error or incorrect current password"
+//	@Failure		404		{object}	errorResponse			"Data not found error"
+//	@Failure		500		{object}	errorResponse			"Internal server error"
+//	@Router			/users/{id}/password [put]
+//	@Security		BearerAuth
+func (uh *UserHandler) ChangePassword(ctx *gin.Context) {
+	var req changePasswordRequest
+
+	paramId := ctx.Param("id")
+
+	id, err := uuid.Parse(paramId)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	err = ctx.BindJSON(&req)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	token := getAuthPayload(ctx, authorizationPayloadKey)
+	if token.ID != id {
+		handleError(ctx, domain.ErrForbidden)
+		return
+	}
+
+	err = uh.svc.ChangePassword(ctx, id, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
 // DeleteUser go-blog
 //
 //	@Summary		delete user
-//	@Description	delete user by user id
+//	@Description	delete user by user id, the user and its blogs are moved to the trash and can be restored by an admin until purged
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
@@ -3,6 +3,7 @@ package http
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/tommjj/go-blog-api/internal/adapter/http/handler"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
 	"github.com/tommjj/go-blog-api/internal/core/ports"
 )
 
@@ -17,44 +18,219 @@ func Group(path string, registerRouterFuncs ...RegisterRouterFunc) RegisterRoute
 }
 
 // RegisterAuthRoute is a option function to return register auth router function
-func RegisterAuthRoute(authHandler *handler.AuthHandler) RegisterRouterFunc {
+func RegisterAuthRoute(authService ports.IAuthService, authHandler *handler.AuthHandler) RegisterRouterFunc {
 	return func(e gin.IRouter) {
 		r := e.Group("/auth")
 		{
 			r.POST("/login", authHandler.Login)
+			r.POST("/login/mfa", authHandler.LoginMFA)
+			r.POST("/logout", authHandler.Logout)
+			r.POST("/password/forgot", authHandler.ForgotPassword)
+			r.POST("/password/reset", authHandler.ResetPassword)
+			r.POST("/verify-email", authHandler.VerifyEmail)
+
+			auth := r.Use(handler.AuthBeerMiddleware(authService))
+			{
+				auth.POST("/verify-email/resend", authHandler.ResendVerification)
+			}
+		}
+	}
+}
+
+// RegisterMagicLinkRoute is a option function to return register passwordless login router function
+func RegisterMagicLinkRoute(magicLinkHandler *handler.MagicLinkHandler) RegisterRouterFunc {
+	return func(e gin.IRouter) {
+		r := e.Group("/auth/magic-link")
+		{
+			r.POST("", magicLinkHandler.RequestLink)
+			r.POST("/callback", magicLinkHandler.Callback)
+		}
+	}
+}
+
+// RegisterOIDCRoute is a option function to return register external login router function
+func RegisterOIDCRoute(oidcHandler *handler.OIDCHandler) RegisterRouterFunc {
+	return func(e gin.IRouter) {
+		r := e.Group("/auth/oidc")
+		{
+			r.GET("/providers", oidcHandler.GetProviders)
+			r.POST("/:provider/authorize", oidcHandler.Authorize)
+			r.POST("/:provider/callback", oidcHandler.Callback)
+		}
+	}
+}
+
+// RegisterMFARoute is a option function to return register two-factor authentication router function
+func RegisterMFARoute(authService ports.IAuthService, mfaHandler *handler.MFAHandler) RegisterRouterFunc {
+	return func(e gin.IRouter) {
+		r := e.Group("/auth/mfa")
+		{
+			auth := r.Use(handler.AuthBeerMiddleware(authService), handler.NotImpersonatedMiddleware())
+			{
+				auth.POST("/totp/enroll", mfaHandler.EnrollTOTP)
+				auth.POST("/totp/confirm", mfaHandler.ConfirmTOTP)
+				auth.DELETE("/totp", mfaHandler.DisableTOTP)
+				auth.POST("/recovery-codes", mfaHandler.RegenerateRecoveryCodes)
+			}
+		}
+	}
+}
+
+// RegisterPasskeyRoute is a option function to return register passkey router function
+func RegisterPasskeyRoute(authService ports.IAuthService, passkeyHandler *handler.PasskeyHandler) RegisterRouterFunc {
+	return func(e gin.IRouter) {
+		r := e.Group("/auth/passkeys")
+		{
+			r.POST("/login/begin", passkeyHandler.BeginLogin)
+			r.POST("/login/finish", passkeyHandler.FinishLogin)
+
+			auth := r.Use(handler.AuthBeerMiddleware(authService), handler.NotImpersonatedMiddleware())
+			{
+				auth.GET("", passkeyHandler.GetPasskeys)
+				auth.POST("/register/begin", passkeyHandler.BeginRegistration)
+				auth.POST("/register/finish", passkeyHandler.FinishRegistration)
+				auth.DELETE("/:id", passkeyHandler.DeletePasskey)
+			}
+		}
+	}
+}
+
+// RegisterAPIKeyRoute is a option function to return register api key router function
+func RegisterAPIKeyRoute(authService ports.IAuthService, apiKeyHandler *handler.APIKeyHandler) RegisterRouterFunc {
+	return func(e gin.IRouter) {
+		r := e.Group("/auth/api-keys")
+		{
+			auth := r.Use(handler.AuthBeerMiddleware(authService), handler.NotImpersonatedMiddleware())
+			{
+				auth.GET("", apiKeyHandler.GetAPIKeys)
+				auth.POST("", apiKeyHandler.CreateAPIKey)
+				auth.DELETE("", apiKeyHandler.RevokeAllAPIKeys)
+				auth.DELETE("/:id", apiKeyHandler.RevokeAPIKey)
+			}
 		}
 	}
 }
 
+// RegisterAdminRoute is a option function to return register admin router function
+func RegisterAdminRoute(authService ports.IAuthService, adminHandler *handler.AdminHandler) RegisterRouterFunc {
+	return func(e gin.IRouter) {
+		r := e.Group("/admin")
+		{
+			admin := r.Use(handler.AuthBeerMiddleware(authService), handler.AdminMiddleware())
+			{
+				admin.GET("/users", adminHandler.ListUsers)
+				admin.POST("/users/:id/suspend", adminHandler.SuspendUser)
+				admin.POST("/users/:id/reactivate", adminHandler.ReactivateUser)
+				admin.POST("/users/:id/password-reset", adminHandler.ForcePasswordReset)
+				admin.POST("/users/:id/impersonate", adminHandler.Impersonate)
+				admin.POST("/users/:id/restore", adminHandler.RestoreUser)
+				admin.GET("/trash/users", adminHandler.ListDeletedUsers)
+				admin.GET("/audit-logs", adminHandler.ListAuditLogs)
+			}
+		}
+	}
+}
+
+// RegisterFeedRoute is a option function to return register feed router function,
+// the site, author and tag feeds are served as RSS, Atom and JSON Feed
+func RegisterFeedRoute(feedHandler *handler.FeedHandler) RegisterRouterFunc {
+	return func(e gin.IRouter) {
+		for _, format := range []string{"rss", "atom", "json"} {
+			e.GET("/feed."+format, feedHandler.GetSiteFeed)
+			e.GET("/users/:id/feed."+format, feedHandler.GetAuthorFeed)
+			e.GET("/tags/:tag/feed."+format, feedHandler.GetTagFeed)
+		}
+	}
+}
+
+// RegisterSitemapRoute is a option function to return register sitemap router function,
+// it is registered at the root of the server where crawlers look for robots.txt
+func RegisterSitemapRoute(sitemapHandler *handler.SitemapHandler) RegisterRouterFunc {
+	return func(e gin.IRouter) {
+		e.GET("/robots.txt", sitemapHandler.GetRobots)
+		e.GET("/sitemap.xml", sitemapHandler.GetSitemap)
+		e.GET("/sitemaps/:page", sitemapHandler.GetIndexedSitemap)
+	}
+}
+
 // RegisterUserRoute is a option function to return register user router function
-func RegisterUserRoute(token ports.ITokenService, authHandler *handler.UserHandler) RegisterRouterFunc {
+func RegisterUserRoute(authService ports.IAuthService, authHandler *handler.UserHandler) RegisterRouterFunc {
 	return func(e gin.IRouter) {
 		r := e.Group("/users")
 		{
 			r.GET("/:id", authHandler.GetUser)
 			r.POST("/", authHandler.CreateUser)
 
-			auth := r.Use(handler.AuthBeerMiddleware(token))
+			auth := r.Use(handler.AuthBeerMiddleware(authService))
+			{
+				auth.PUT("/:id", handler.NotImpersonatedMiddleware(), authHandler.UpdateUser)
+				auth.PATCH("/:id", handler.NotImpersonatedMiddleware(), authHandler.PatchUser)
+				auth.PUT("/:id/password", handler.NotImpersonatedMiddleware(), authHandler.ChangePassword)
+				auth.DELETE("/:id", handler.NotImpersonatedMiddleware(), authHandler.DeleteUser)
+			}
+		}
+	}
+}
+
+// RegisterFollowRoute is a option function to return register follow router function,
+// the timeline of the current user is served at /feed
+func RegisterFollowRoute(authService ports.IAuthService, followHandler *handler.FollowHandler) RegisterRouterFunc {
+	return func(e gin.IRouter) {
+		e.GET("/feed", handler.AuthBeerMiddleware(authService), followHandler.GetTimeline)
+
+		r := e.Group("/users")
+		{
+			r.GET("/:id/followers", followHandler.GetFollowers)
+			r.GET("/:id/following", followHandler.GetFollowing)
+
+			auth := r.Use(handler.AuthBeerMiddleware(authService))
 			{
-				auth.PUT("/:id", authHandler.UpdateUser)
-				auth.DELETE("/:id", authHandler.DeleteUser)
+				auth.POST("/:id/follow", followHandler.Follow)
+				auth.DELETE("/:id/follow", followHandler.Unfollow)
 			}
 		}
 	}
 }
 
-// RegisterBlogRoute is a option function to return register blog router function
-func RegisterBlogRoute(token ports.ITokenService, blogHandler *handler.BlogHandler) RegisterRouterFunc {
+// RegisterBookmarkRoute is a option function to return register bookmark router function,
+// the bookmarks are the ones of the current user
+func RegisterBookmarkRoute(authService ports.IAuthService, bookmarkHandler *handler.BookmarkHandler) RegisterRouterFunc {
+	return func(e gin.IRouter) {
+		r := e.Group("/users/me/bookmarks").Use(handler.AuthBeerMiddleware(authService))
+		{
+			r.GET("", bookmarkHandler.GetBookmarks)
+			r.POST("/:blogId", bookmarkHandler.AddBookmark)
+			r.DELETE("/:blogId", bookmarkHandler.RemoveBookmark)
+		}
+	}
+}
+
+// RegisterBlogRoute is a option function to return register blog router function,
+// requireVerifiedEmail restrict blog creation to users with a verified email
+func RegisterBlogRoute(authService ports.IAuthService, blogHandler *handler.BlogHandler, requireVerifiedEmail bool) RegisterRouterFunc {
 	return func(e gin.IRouter) {
 		r := e.Group("/blogs")
 		{
 			r.GET("/", blogHandler.GetListBlogs)
 			r.GET("/:id", blogHandler.GetBlog)
-			auth := r.Use(handler.AuthBeerMiddleware(token))
+			r.GET("/by-slug/:slug", blogHandler.GetBlogBySlug)
+			r.GET("/highlight.css", blogHandler.GetHighlightCSS)
+			r.GET("/:id/code-blocks", blogHandler.GetCodeBlocks)
+			auth := r.Use(handler.AuthBeerMiddleware(authService, domain.BlogsWriteScope))
 			{
-				auth.POST("/", blogHandler.CreateBlog)
+				auth.POST("/", handler.VerifiedEmailMiddleware(requireVerifiedEmail), blogHandler.CreateBlog)
 				auth.PUT("/:id", blogHandler.UpdateBlog)
+				auth.PATCH("/:id", blogHandler.PatchBlog)
 				auth.DELETE("/:id", blogHandler.DeleteBlog)
+				auth.GET("/trash", blogHandler.GetTrash)
+				auth.POST("/:id/restore", blogHandler.RestoreBlog)
+				auth.GET("/:id/revisions", blogHandler.GetRevisions)
+				auth.GET("/:id/stats", blogHandler.GetBlogStats)
+				auth.GET("/:id/revisions/diff", blogHandler.DiffRevisions)
+				auth.GET("/:id/revisions/:rev", blogHandler.GetRevision)
+				auth.POST("/:id/revisions/:rev/restore", blogHandler.RestoreRevision)
+				auth.PUT("/:id/reactions/:kind", handler.VerifiedEmailMiddleware(requireVerifiedEmail), blogHandler.AddReaction)
+				auth.DELETE("/:id/reactions/:kind", blogHandler.RemoveReaction)
 			}
 		}
 	}
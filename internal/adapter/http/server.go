@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"time"
 
+	"net/http"
+
 	"github.com/gin-contrib/cors"
 	ginzap "github.com/gin-contrib/zap"
 	"github.com/gin-gonic/gin"
+	"github.com/tommjj/go-blog-api/internal/adapter/http/handler"
 	"github.com/tommjj/go-blog-api/internal/config"
 	"github.com/tommjj/go-blog-api/internal/logger"
 
@@ -37,6 +40,12 @@ func New(conf *config.Http, options ...RegisterRouterFunc) (*Router, error) {
 
 	r := gin.New()
 
+	// the client IP is only read from the forwarded headers of the trusted proxies,
+	// any client could set them to change the IP the views are counted by
+	if err := r.SetTrustedProxies(conf.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("http trusted proxies are not valid: %w", err)
+	}
+
 	// set logger middleware
 	logger, err := logger.New(conf.Logger)
 	if err != nil {
@@ -49,8 +58,20 @@ func New(conf *config.Http, options ...RegisterRouterFunc) (*Router, error) {
 	// set CORS
 	ginConfig := cors.DefaultConfig()
 	ginConfig.AllowOrigins = conf.AllowedOrigins
+	ginConfig.AddAllowHeaders("If-Match", "If-None-Match")
+	ginConfig.AddExposeHeaders("ETag")
+	if conf.SessionCookie {
+		// browsers only send cookies to another origin when credentials are allowed
+		ginConfig.AllowCredentials = true
+		ginConfig.AddAllowHeaders("X-CSRF-Token")
+	}
 	r.Use(cors.New(ginConfig))
 
+	// set cookie sessions
+	if conf.SessionCookie {
+		r.Use(handler.CookieSessionMiddleware(conf.CookieDomain, conf.CookieSecure, parseSameSite(conf.CookieSameSite), conf.AllowedOrigins))
+	}
+
 	// set router
 	r.GET("/ping", ping)
 
@@ -69,6 +90,18 @@ func New(conf *config.Http, options ...RegisterRouterFunc) (*Router, error) {
 	}, nil
 }
 
+// parseSameSite convert a SameSite config value to its cookie attribute
+func parseSameSite(sameSite string) http.SameSite {
+	switch sameSite {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
 func (r *Router) Serve() {
 	logger.Info(fmt.Sprintf("start server at http://%v:%v", r.Url, r.Port))
 
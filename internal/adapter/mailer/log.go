@@ -0,0 +1,63 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"os"
+	"sync"
+
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/logger"
+	"go.uber.org/zap"
+)
+
+// LogMailer is a development mailer, it appends the full messages to a file
+// or writes them to the app logger if no file is set
+type LogMailer struct {
+	mu       sync.Mutex
+	from     *mail.Address
+	fileName string
+}
+
+func NewLogMailer(conf config.Mail) (ports.IMailer, error) {
+	from, err := mail.ParseAddress(conf.From)
+	if err != nil {
+		return nil, fmt.Errorf("MAIL_FROM is not a valid address: %v", err)
+	}
+
+	return &LogMailer{
+		from:     from,
+		fileName: conf.LogFile,
+	}, nil
+}
+
+func (lm *LogMailer) Send(ctx context.Context, msg *domain.Mail) error {
+	if lm.fileName == "" {
+		logger.Info("mail sent",
+			zap.Strings("to", msg.To),
+			zap.String("subject", msg.Subject),
+			zap.String("text", msg.Text),
+		)
+		return nil
+	}
+
+	body, err := buildMessage(lm.from, msg)
+	if err != nil {
+		return err
+	}
+
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	f, err := os.OpenFile(lm.fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(body, "\r\n\r\n"...))
+	return err
+}
@@ -0,0 +1,20 @@
+package mailer
+
+import (
+	"fmt"
+
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+// New create the mailer of the configured driver
+func New(conf config.Mail) (ports.IMailer, error) {
+	switch conf.Driver {
+	case "smtp":
+		return NewSMTPMailer(conf)
+	case "log", "":
+		return NewLogMailer(conf)
+	default:
+		return nil, fmt.Errorf("MAIL_DRIVER %q is not supported", conf.Driver)
+	}
+}
@@ -0,0 +1,77 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+)
+
+// buildMessage build a multipart/alternative RFC 5322 message with a text and a html part
+func buildMessage(from *mail.Address, msg *domain.Mail) ([]byte, error) {
+	var buf bytes.Buffer
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	_, domainPart, _ := strings.Cut(from.Address, "@")
+
+	w := multipart.NewWriter(&buf)
+
+	headers := []string{
+		"From: " + from.String(),
+		"To: " + strings.Join(msg.To, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		fmt.Sprintf("Message-ID: <%v@%v>", hex.EncodeToString(id), domainPart),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + w.Boundary(),
+	}
+	buf.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+
+	parts := []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	}
+
+	for _, part := range parts {
+		if part.body == "" {
+			continue
+		}
+
+		pw, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		qp := quotedprintable.NewWriter(pw)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,99 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+// SMTPMailer send emails through an SMTP server, STARTTLS is used when the server supports it
+type SMTPMailer struct {
+	addr     string
+	host     string
+	from     *mail.Address
+	username string
+	password string
+}
+
+func NewSMTPMailer(conf config.Mail) (ports.IMailer, error) {
+	from, err := mail.ParseAddress(conf.From)
+	if err != nil {
+		return nil, fmt.Errorf("MAIL_FROM is not a valid address: %v", err)
+	}
+
+	return &SMTPMailer{
+		addr:     net.JoinHostPort(conf.SMTPHost, strconv.Itoa(conf.SMTPPort)),
+		host:     conf.SMTPHost,
+		from:     from,
+		username: conf.SMTPUsername,
+		password: conf.SMTPPassword,
+	}, nil
+}
+
+func (sm *SMTPMailer) Send(ctx context.Context, msg *domain.Mail) error {
+	body, err := buildMessage(sm.from, msg)
+	if err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", sm.addr)
+	if err != nil {
+		return err
+	}
+
+	// the smtp client has no context support, bound the whole exchange by the context deadline
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, sm.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: sm.host}); err != nil {
+			return err
+		}
+	}
+
+	if sm.username != "" {
+		err := client.Auth(smtp.PlainAuth("", sm.username, sm.password, sm.host))
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(sm.from.Address); err != nil {
+		return err
+	}
+	for _, to := range msg.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
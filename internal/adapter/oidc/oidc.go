@@ -0,0 +1,104 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"golang.org/x/oauth2"
+)
+
+// implement ports.IOIDCProvider for any OpenID Connect provider
+type Provider struct {
+	name     string
+	oauth2   oauth2.Config
+	verifier *gooidc.IDTokenVerifier
+}
+
+// New discover the provider endpoints and keys from its issuer,
+// ctx is kept to refresh the keys and must live as long as the provider
+func New(ctx context.Context, conf config.OIDCProvider) (ports.IOIDCProvider, error) {
+	provider, err := gooidc.NewProvider(ctx, conf.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("oidc provider %v: %w", conf.Name, err)
+	}
+
+	return &Provider{
+		name: conf.Name,
+		oauth2: oauth2.Config{
+			ClientID:     conf.ClientID,
+			ClientSecret: conf.ClientSecret,
+			RedirectURL:  conf.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       conf.Scopes,
+		},
+		verifier: provider.Verifier(&gooidc.Config{ClientID: conf.ClientID}),
+	}, nil
+}
+
+// NewProviders create the providers of the config
+func NewProviders(ctx context.Context, conf config.OIDC) ([]ports.IOIDCProvider, error) {
+	providers := make([]ports.IOIDCProvider, 0, len(conf.Providers))
+
+	for _, providerConf := range conf.Providers {
+		provider, err := New(ctx, providerConf)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+	}
+
+	return providers, nil
+}
+
+func (p *Provider) Name() string {
+	return p.name
+}
+
+func (p *Provider) AuthCodeURL(state, nonce, verifier string) string {
+	return p.oauth2.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier))
+}
+
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (*domain.ExternalIdentity, error) {
+	token, err := p.oauth2.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("token response has no id_token")
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if idToken.Nonce != nonce {
+		return nil, errors.New("id_token nonce does not match")
+	}
+
+	var claims struct {
+		Email             string `json:"email"`
+		EmailVerified     bool   `json:"email_verified"`
+		PreferredUsername string `json:"preferred_username"`
+		Name              string `json:"name"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+
+	return &domain.ExternalIdentity{
+		Provider:          p.name,
+		Subject:           idToken.Subject,
+		Email:             claims.Email,
+		EmailVerified:     claims.EmailVerified,
+		PreferredUsername: claims.PreferredUsername,
+		Name:              claims.Name,
+	}, nil
+}
@@ -0,0 +1,171 @@
+package pwned
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+const (
+	prefixLength = 5
+	hashLength   = 40 // SHA-1 hex length
+)
+
+var ErrInvalidPrefix = errors.New("hash prefix must be 5 hex characters")
+
+// FileRepository is an offline breached password repository, it reads either
+// a single "HASH:COUNT" file loaded into memory at start or a directory of range
+// files named by their prefix (optionally with a .txt extension) holding
+// "SUFFIX:COUNT" lines, read on demand
+type FileRepository struct {
+	dir      string                    // range files directory, empty if loaded from a single file
+	prefixes map[string]map[string]int // prefix -> suffix -> count
+}
+
+func New(path string) (ports.IBreachedPasswordRepository, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return &FileRepository{
+			dir: path,
+		}, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	prefixes, err := loadHashes(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileRepository{
+		prefixes: prefixes,
+	}, nil
+}
+
+func (fr *FileRepository) GetSuffixesByPrefix(ctx context.Context, prefix string) (map[string]int, error) {
+	prefix = strings.ToUpper(prefix)
+	if !isHex(prefix) || len(prefix) != prefixLength {
+		return nil, ErrInvalidPrefix
+	}
+
+	if fr.dir == "" {
+		suffixes, ok := fr.prefixes[prefix]
+		if !ok {
+			return map[string]int{}, nil
+		}
+		return suffixes, nil
+	}
+
+	for _, name := range []string{prefix, prefix + ".txt"} {
+		f, err := os.Open(filepath.Join(fr.dir, name))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		defer f.Close()
+
+		return loadSuffixes(f)
+	}
+
+	return map[string]int{}, nil
+}
+
+// loadHashes read "HASH:COUNT" lines grouped by hash prefix
+func loadHashes(r io.Reader) (map[string]map[string]int, error) {
+	prefixes := map[string]map[string]int{}
+
+	err := scanLines(r, func(hash string, count int) error {
+		if len(hash) != hashLength {
+			return fmt.Errorf("invalid SHA-1 hash %q", hash)
+		}
+
+		prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+		if prefixes[prefix] == nil {
+			prefixes[prefix] = map[string]int{}
+		}
+		prefixes[prefix][suffix] = count
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return prefixes, nil
+}
+
+// loadSuffixes read "SUFFIX:COUNT" lines of a range file
+func loadSuffixes(r io.Reader) (map[string]int, error) {
+	suffixes := map[string]int{}
+
+	err := scanLines(r, func(suffix string, count int) error {
+		if len(suffix) != hashLength-prefixLength {
+			return fmt.Errorf("invalid SHA-1 hash suffix %q", suffix)
+		}
+
+		suffixes[suffix] = count
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return suffixes, nil
+}
+
+func scanLines(r io.Reader, fn func(hash string, count int) error) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		hash, countStr, found := strings.Cut(line, ":")
+		count := 1
+		if found {
+			var err error
+			count, err = strconv.Atoi(strings.TrimSpace(countStr))
+			if err != nil {
+				return fmt.Errorf("invalid breach count in line %q: %v", line, err)
+			}
+		}
+
+		hash = strings.ToUpper(strings.TrimSpace(hash))
+		if !isHex(hash) {
+			return fmt.Errorf("invalid hash in line %q", line)
+		}
+
+		if err := fn(hash, count); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+func isHex(s string) bool {
+	for _, r := range s {
+		if !(r >= '0' && r <= '9' || r >= 'A' && r <= 'F') {
+			return false
+		}
+	}
+	return true
+}
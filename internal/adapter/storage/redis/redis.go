@@ -36,8 +36,8 @@ func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Dura
 	return r.client.Set(ctx, key, value, ttl).Err()
 }
 
-func (r *Redis) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) error {
-	return r.client.SetNX(ctx, key, value, ttl).Err()
+func (r *Redis) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, ttl).Result()
 }
 
 func (r *Redis) Get(ctx context.Context, key string) ([]byte, error) {
@@ -52,6 +52,54 @@ func (r *Redis) Get(ctx context.Context, key string) ([]byte, error) {
 	return []byte(val), nil
 }
 
+func (r *Redis) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	// the window starts with the first hit, the key never exists without a ttl
+	var count *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SetNX(ctx, key, 0, ttl)
+		count = pipe.Incr(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count.Val(), nil
+}
+
+func (r *Redis) IncrBy(ctx context.Context, key string, value int64, ttl time.Duration) (int64, error) {
+	// a counter that expired since it was read is created again with a ttl
+	var count *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SetNX(ctx, key, 0, ttl)
+		count = pipe.IncrBy(ctx, key, value)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count.Val(), nil
+}
+
+func (r *Redis) HIncrBy(ctx context.Context, key, field string, value int64) (int64, error) {
+	return r.client.HIncrBy(ctx, key, field, value).Result()
+}
+
+func (r *Redis) HGetDelAll(ctx context.Context, key string) (map[string]string, error) {
+	var fields *redis.MapStringStringCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		fields = pipe.HGetAll(ctx, key)
+		pipe.Del(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fields.Val(), nil
+}
+
 func (r *Redis) Delete(ctx context.Context, key string) error {
 	return r.client.Del(ctx, key).Err()
 }
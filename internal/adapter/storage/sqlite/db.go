@@ -59,7 +59,7 @@ func New(conf config.DB) (*DB, error) {
 		return nil, err
 	}
 
-	err = db.AutoMigrate(&schema.User{}, &schema.Blog{})
+	err = db.AutoMigrate(&schema.User{}, &schema.Blog{}, &schema.BlogRevision{}, &schema.BlogSlug{}, &schema.BlogTag{}, &schema.BlogReaction{}, &schema.BlogReactionCount{}, &schema.BlogViewDaily{}, &schema.BlogReferrerDaily{}, &schema.Bookmark{}, &schema.Follow{}, &schema.ActionToken{}, &schema.RecoveryCode{}, &schema.Passkey{}, &schema.UserIdentity{}, &schema.APIKey{}, &schema.AuditLog{})
 	if err != nil {
 		return nil, err
 	}
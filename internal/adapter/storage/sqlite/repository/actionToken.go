@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite/schema"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+// implement ports.IActionTokenRepository
+type ActionTokenRepository struct {
+	db *sqlite.DB
+}
+
+func NewActionTokenRepository(db *sqlite.DB) ports.IActionTokenRepository {
+	return &ActionTokenRepository{
+		db: db,
+	}
+}
+
+func (ar *ActionTokenRepository) CreateToken(ctx context.Context, token *domain.ActionToken) (*domain.ActionToken, error) {
+	createdToken := &schema.ActionToken{
+		UserID:    token.UserID,
+		Purpose:   string(token.Purpose),
+		TokenHash: token.TokenHash,
+		ExpiresAt: token.ExpiresAt,
+	}
+
+	if err := ar.db.WithContext(ctx).Create(createdToken).Error; err != nil {
+		return nil, err
+	}
+
+	return &domain.ActionToken{
+		ID:        createdToken.ID,
+		UserID:    createdToken.UserID,
+		Purpose:   domain.TokenPurpose(createdToken.Purpose),
+		TokenHash: createdToken.TokenHash,
+		ExpiresAt: createdToken.ExpiresAt,
+		UsedAt:    createdToken.UsedAt,
+		CreatedAt: createdToken.CreatedAt,
+	}, nil
+}
+
+func (ar *ActionTokenRepository) GetTokenByHash(ctx context.Context, purpose domain.TokenPurpose, hash string) (*domain.ActionToken, error) {
+	token := &schema.ActionToken{}
+
+	err := ar.db.WithContext(ctx).Where("purpose = ? AND token_hash = ?", string(purpose), hash).First(token).Error
+	if err != nil {
+		return nil, domain.ErrDataNotFound
+	}
+
+	return &domain.ActionToken{
+		ID:        token.ID,
+		UserID:    token.UserID,
+		Purpose:   domain.TokenPurpose(token.Purpose),
+		TokenHash: token.TokenHash,
+		ExpiresAt: token.ExpiresAt,
+		UsedAt:    token.UsedAt,
+		CreatedAt: token.CreatedAt,
+	}, nil
+}
+
+func (ar *ActionTokenRepository) ConsumeToken(ctx context.Context, id uuid.UUID) error {
+	// the used_at condition makes concurrent consumes of the same token fail
+	upd := ar.db.WithContext(ctx).Model(&schema.ActionToken{}).
+		Where("id = ? AND used_at IS NULL", id).Update("used_at", time.Now())
+
+	if err := upd.Error; err != nil {
+		return err
+	}
+	if upd.RowsAffected == 0 {
+		return domain.ErrInvalidActionToken
+	}
+
+	return nil
+}
+
+func (ar *ActionTokenRepository) DeleteTokensByUserID(ctx context.Context, userID uuid.UUID, purpose domain.TokenPurpose) error {
+	return ar.db.WithContext(ctx).
+		Where("user_id = ? AND purpose = ?", userID, string(purpose)).Delete(&schema.ActionToken{}).Error
+}
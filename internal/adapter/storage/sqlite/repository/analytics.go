@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite/schema"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// implement ports.IAnalyticsRepository
+type AnalyticsRepository struct {
+	db *sqlite.DB
+}
+
+func NewAnalyticsRepository(db *sqlite.DB) ports.IAnalyticsRepository {
+	return &AnalyticsRepository{
+		db: db,
+	}
+}
+
+// addViews add the views of the rows to the views of the rows already saved
+var addViews = clause.OnConflict{
+	DoUpdates: clause.Assignments(map[string]interface{}{"views": gorm.Expr("views + excluded.views")}),
+}
+
+func (ar *AnalyticsRepository) SaveViews(ctx context.Context, counts []domain.ViewCount, batchSize int) error {
+	type dayKey struct {
+		blogID uuid.UUID
+		day    string
+	}
+
+	// the views of the referrers of a day are rolled up to the day
+	days := map[dayKey]*schema.BlogViewDaily{}
+	dailies := []*schema.BlogViewDaily{}
+	referrers := make([]schema.BlogReferrerDaily, 0, len(counts))
+	for _, count := range counts {
+		key := dayKey{count.BlogID, count.Day}
+		daily, ok := days[key]
+		if !ok {
+			daily = &schema.BlogViewDaily{BlogID: count.BlogID, Day: count.Day}
+			days[key] = daily
+			dailies = append(dailies, daily)
+		}
+		daily.Views += count.Views
+
+		referrers = append(referrers, schema.BlogReferrerDaily{
+			BlogID:   count.BlogID,
+			Day:      count.Day,
+			Referrer: count.Referrer,
+			Views:    count.Views,
+		})
+	}
+	if len(dailies) == 0 {
+		return nil
+	}
+
+	tx := ar.db.WithContext(ctx).Begin()
+
+	if err := tx.Clauses(addViews).CreateInBatches(dailies, batchSize).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Clauses(addViews).CreateInBatches(referrers, batchSize).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (ar *AnalyticsRepository) GetDailyViews(ctx context.Context, blogID uuid.UUID, from, to string) ([]domain.DailyViews, error) {
+	rows := []schema.BlogViewDaily{}
+	err := ar.db.WithContext(ctx).Where("blog_id = ? AND day BETWEEN ? AND ?", blogID, from, to).
+		Order("day").Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]domain.DailyViews, 0, len(rows))
+	for _, row := range rows {
+		days = append(days, domain.DailyViews{
+			Day:   row.Day,
+			Views: row.Views,
+		})
+	}
+	return days, nil
+}
+
+func (ar *AnalyticsRepository) GetReferrerViews(ctx context.Context, blogID uuid.UUID, from, to string, limit int) ([]domain.ReferrerViews, error) {
+	referrers := []domain.ReferrerViews{}
+	err := ar.db.WithContext(ctx).Model(&schema.BlogReferrerDaily{}).
+		Select("referrer, SUM(views) AS views").
+		Where("blog_id = ? AND day BETWEEN ? AND ?", blogID, from, to).
+		Group("referrer").Order("views DESC").Order("referrer").Limit(limit).
+		Scan(&referrers).Error
+	if err != nil {
+		return nil, err
+	}
+	return referrers, nil
+}
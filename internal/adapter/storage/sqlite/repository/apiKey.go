@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite/schema"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+// implement ports.IAPIKeyRepository
+type APIKeyRepository struct {
+	db *sqlite.DB
+}
+
+func NewAPIKeyRepository(db *sqlite.DB) ports.IAPIKeyRepository {
+	return &APIKeyRepository{
+		db: db,
+	}
+}
+
+func (ar *APIKeyRepository) CreateAPIKey(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	createdKey := &schema.APIKey{
+		UserID:    key.UserID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		KeyHash:   key.KeyHash,
+		Scopes:    strings.Join(scopes, ","),
+		ExpiresAt: key.ExpiresAt,
+	}
+
+	if err := ar.db.WithContext(ctx).Create(createdKey).Error; err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return toDomainAPIKey(createdKey), nil
+}
+
+func (ar *APIKeyRepository) GetAPIKeysByUserID(ctx context.Context, userID uuid.UUID) ([]domain.APIKey, error) {
+	keys := []schema.APIKey{}
+
+	err := ar.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at").Find(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.APIKey, 0, len(keys))
+	for i := range keys {
+		result = append(result, *toDomainAPIKey(&keys[i]))
+	}
+
+	return result, nil
+}
+
+func (ar *APIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	key := &schema.APIKey{}
+
+	err := ar.db.WithContext(ctx).Where("key_hash = ?", hash).First(key).Error
+	if err != nil {
+		return nil, domain.ErrDataNotFound
+	}
+
+	return toDomainAPIKey(key), nil
+}
+
+func (ar *APIKeyRepository) UpdateLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	return ar.db.WithContext(ctx).Model(&schema.APIKey{}).Where("id = ?", id).Update("last_used_at", usedAt).Error
+}
+
+func (ar *APIKeyRepository) DeleteAPIKey(ctx context.Context, userID, id uuid.UUID) error {
+	d := ar.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&schema.APIKey{})
+
+	if err := d.Error; err != nil {
+		return err
+	}
+	if d.RowsAffected == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (ar *APIKeyRepository) DeleteAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error {
+	return ar.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&schema.APIKey{}).Error
+}
+
+func toDomainAPIKey(key *schema.APIKey) *domain.APIKey {
+	var scopes []domain.APIKeyScope
+	if key.Scopes != "" {
+		for _, scope := range strings.Split(key.Scopes, ",") {
+			scopes = append(scopes, domain.APIKeyScope(scope))
+		}
+	}
+
+	return &domain.APIKey{
+		ID:         key.ID,
+		UserID:     key.UserID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		KeyHash:    key.KeyHash,
+		Scopes:     scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
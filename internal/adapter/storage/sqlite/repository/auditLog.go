@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite/schema"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+// implement ports.IAuditLogRepository
+type AuditLogRepository struct {
+	db *sqlite.DB
+}
+
+func NewAuditLogRepository(db *sqlite.DB) ports.IAuditLogRepository {
+	return &AuditLogRepository{
+		db: db,
+	}
+}
+
+func (ar *AuditLogRepository) CreateAuditLog(ctx context.Context, log *domain.AuditLog) (*domain.AuditLog, error) {
+	createdLog := &schema.AuditLog{
+		ActorID:  log.ActorID,
+		Action:   string(log.Action),
+		TargetID: log.TargetID,
+		Reason:   log.Reason,
+	}
+
+	if err := ar.db.WithContext(ctx).Create(createdLog).Error; err != nil {
+		return nil, err
+	}
+
+	return toDomainAuditLog(createdLog), nil
+}
+
+func (ar *AuditLogRepository) ListAuditLogs(ctx context.Context, targetID *uuid.UUID, skip, limit int) ([]domain.AuditLog, int, error) {
+	query := ar.db.WithContext(ctx).Model(&schema.AuditLog{})
+	if targetID != nil {
+		query = query.Where("target_id = ?", *targetID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	logs := []schema.AuditLog{}
+	err := query.Order("created_at DESC").Offset(skip).Limit(limit).Find(&logs).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]domain.AuditLog, 0, len(logs))
+	for i := range logs {
+		result = append(result, *toDomainAuditLog(&logs[i]))
+	}
+
+	return result, int(total), nil
+}
+
+func toDomainAuditLog(log *schema.AuditLog) *domain.AuditLog {
+	return &domain.AuditLog{
+		ID:        log.ID,
+		ActorID:   log.ActorID,
+		Action:    domain.AuditAction(log.Action),
+		TargetID:  log.TargetID,
+		Reason:    log.Reason,
+		CreatedAt: log.CreatedAt,
+	}
+}
@@ -3,12 +3,16 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite"
 	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite/schema"
 	"github.com/tommjj/go-blog-api/internal/core/domain"
 	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/core/util"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
@@ -29,21 +33,43 @@ func (br *BlogRepository) GetBlogByID(ctx context.Context, id uuid.UUID) (*domai
 		return nil, domain.ErrDataNotFound
 	}
 
+	tags, err := blogTags(br.db.WithContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+
 	return &domain.Blog{
-		ID:        blog.ID,
-		Title:     blog.Title,
-		Text:      blog.Text,
-		AuthorID:  blog.AuthorID,
-		CreatedAt: blog.CreatedAt,
-		UpdatedAt: blog.UpdatedAt,
+		ID:          blog.ID,
+		Title:       blog.Title,
+		Slug:        stringValue(blog.Slug),
+		Tags:        tags,
+		Summary:     blog.Summary,
+		Excerpt:     stringValue(blog.Excerpt),
+		WordCount:   blog.WordCount,
+		ReadingTime: blog.ReadingTime,
+		Text:        blog.Text,
+		AuthorID:    blog.AuthorID,
+		Version:     blog.Version,
+		CreatedAt:   blog.CreatedAt,
+		UpdatedAt:   blog.UpdatedAt,
 	}, nil
 }
 
+func (br *BlogRepository) GetBlogBySlug(ctx context.Context, slug string) (*domain.Blog, error) {
+	blogSlug := &schema.BlogSlug{}
+
+	if err := br.db.WithContext(ctx).Where("slug = ?", slug).First(blogSlug).Error; err != nil {
+		return nil, domain.ErrDataNotFound
+	}
+
+	return br.GetBlogByID(ctx, blogSlug.BlogID)
+}
+
 func (br *BlogRepository) GetBlogsByAuthorID(ctx context.Context, id uuid.UUID, skip, limit int) ([]domain.Blog, error) {
 	blogs := []schema.Blog{}
 
 	if err := br.db.WithContext(ctx).Select(
-		"id", "title", "author_id", "created_at", "updated_at",
+		"id", "title", "slug", "summary", "excerpt", "word_count", "reading_time", "author_id", "version", "created_at", "updated_at",
 	).Where("author_id = ?", id).Limit(limit).Offset((skip - 1) * limit).Find(&blogs).Error; err != nil {
 		return nil, err
 	}
@@ -55,12 +81,18 @@ func (br *BlogRepository) GetBlogsByAuthorID(ctx context.Context, id uuid.UUID,
 	domainBlogs := []domain.Blog{}
 	for _, blog := range blogs {
 		domainBlogs = append(domainBlogs, domain.Blog{
-			ID:        blog.ID,
-			Title:     blog.Title,
-			Text:      blog.Text,
-			AuthorID:  blog.AuthorID,
-			CreatedAt: blog.CreatedAt,
-			UpdatedAt: blog.UpdatedAt,
+			ID:          blog.ID,
+			Title:       blog.Title,
+			Slug:        stringValue(blog.Slug),
+			Summary:     blog.Summary,
+			Excerpt:     stringValue(blog.Excerpt),
+			WordCount:   blog.WordCount,
+			ReadingTime: blog.ReadingTime,
+			Text:        blog.Text,
+			AuthorID:    blog.AuthorID,
+			Version:     blog.Version,
+			CreatedAt:   blog.CreatedAt,
+			UpdatedAt:   blog.UpdatedAt,
 		})
 	}
 	return domainBlogs, nil
@@ -70,7 +102,7 @@ func (br *BlogRepository) GetListBlogs(ctx context.Context, skip, limit int) ([]
 	blogs := []schema.Blog{}
 
 	err := br.db.WithContext(ctx).Select(
-		"id", "title", "author_id", "created_at", "updated_at",
+		"id", "title", "slug", "summary", "excerpt", "word_count", "reading_time", "author_id", "version", "created_at", "updated_at",
 	).Limit(limit).Offset((skip - 1) * limit).Find(&blogs).Error
 
 	if err != nil {
@@ -82,24 +114,143 @@ func (br *BlogRepository) GetListBlogs(ctx context.Context, skip, limit int) ([]
 	}
 
 	domainBlogs := []domain.Blog{}
+	for _, blog := range blogs {
+		domainBlogs = append(domainBlogs, domain.Blog{
+			ID:          blog.ID,
+			Title:       blog.Title,
+			Slug:        stringValue(blog.Slug),
+			Summary:     blog.Summary,
+			Excerpt:     stringValue(blog.Excerpt),
+			WordCount:   blog.WordCount,
+			ReadingTime: blog.ReadingTime,
+			Text:        blog.Text,
+			AuthorID:    blog.AuthorID,
+			Version:     blog.Version,
+			CreatedAt:   blog.CreatedAt,
+			UpdatedAt:   blog.UpdatedAt,
+		})
+	}
+	return domainBlogs, nil
+}
+
+func (br *BlogRepository) GetRecentBlogs(ctx context.Context, authorID uuid.UUID, limit int) ([]domain.Blog, error) {
+	blogs := []schema.Blog{}
+
+	query := br.db.WithContext(ctx)
+	if authorID != uuid.Nil {
+		query = query.Where("author_id = ?", authorID)
+	}
+	if err := query.Order("created_at DESC").Limit(limit).Find(&blogs).Error; err != nil {
+		return nil, err
+	}
+
+	domainBlogs := make([]domain.Blog, 0, len(blogs))
+	for _, blog := range blogs {
+		domainBlogs = append(domainBlogs, domain.Blog{
+			ID:          blog.ID,
+			Title:       blog.Title,
+			Slug:        stringValue(blog.Slug),
+			Summary:     blog.Summary,
+			Excerpt:     stringValue(blog.Excerpt),
+			WordCount:   blog.WordCount,
+			ReadingTime: blog.ReadingTime,
+			Text:        blog.Text,
+			AuthorID:    blog.AuthorID,
+			Version:     blog.Version,
+			CreatedAt:   blog.CreatedAt,
+			UpdatedAt:   blog.UpdatedAt,
+		})
+	}
+	return domainBlogs, nil
+}
+
+func (br *BlogRepository) GetRecentBlogsByTag(ctx context.Context, tag string, limit int) ([]domain.Blog, error) {
+	blogs := []schema.Blog{}
+
+	tagged := br.db.WithContext(ctx).Model(&schema.BlogTag{}).Select("blog_id").Where("tag = ?", tag)
+	err := br.db.WithContext(ctx).Where("id IN (?)", tagged).Order("created_at DESC").Limit(limit).Find(&blogs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	domainBlogs := make([]domain.Blog, 0, len(blogs))
+	for _, blog := range blogs {
+		domainBlogs = append(domainBlogs, domain.Blog{
+			ID:          blog.ID,
+			Title:       blog.Title,
+			Slug:        stringValue(blog.Slug),
+			Summary:     blog.Summary,
+			Excerpt:     stringValue(blog.Excerpt),
+			WordCount:   blog.WordCount,
+			ReadingTime: blog.ReadingTime,
+			Text:        blog.Text,
+			AuthorID:    blog.AuthorID,
+			Version:     blog.Version,
+			CreatedAt:   blog.CreatedAt,
+			UpdatedAt:   blog.UpdatedAt,
+		})
+	}
+	return domainBlogs, nil
+}
+
+func (br *BlogRepository) GetSitemapBlogs(ctx context.Context) ([]domain.Blog, error) {
+	blogs := []schema.Blog{}
+
+	query := br.db.WithContext(ctx).Select("id", "author_id", "slug", "updated_at")
+	if err := query.Order("created_at").Order("id").Find(&blogs).Error; err != nil {
+		return nil, err
+	}
+
+	domainBlogs := make([]domain.Blog, 0, len(blogs))
 	for _, blog := range blogs {
 		domainBlogs = append(domainBlogs, domain.Blog{
 			ID:        blog.ID,
-			Title:     blog.Title,
-			Text:      blog.Text,
+			Slug:      stringValue(blog.Slug),
 			AuthorID:  blog.AuthorID,
-			CreatedAt: blog.CreatedAt,
 			UpdatedAt: blog.UpdatedAt,
 		})
 	}
 	return domainBlogs, nil
 }
 
+func (br *BlogRepository) GetBlogsByAuthors(ctx context.Context, authorIDs []uuid.UUID, cursor *domain.TimelineCursor, limit int) ([]domain.Blog, error) {
+	blogs := []schema.Blog{}
+
+	query := br.db.WithContext(ctx).Select(
+		"id", "title", "slug", "summary", "excerpt", "word_count", "reading_time", "author_id", "version", "created_at", "updated_at",
+	).Where("author_id IN ?", authorIDs)
+	// keyset pagination, the id orders the blogs created at the same time
+	if cursor != nil {
+		query = query.Where("created_at < ? OR (created_at = ? AND id < ?)", cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+	}
+	if err := query.Order("created_at DESC").Order("id DESC").Limit(limit).Find(&blogs).Error; err != nil {
+		return nil, err
+	}
+
+	domainBlogs := make([]domain.Blog, 0, len(blogs))
+	for _, blog := range blogs {
+		domainBlogs = append(domainBlogs, domain.Blog{
+			ID:          blog.ID,
+			Title:       blog.Title,
+			Slug:        stringValue(blog.Slug),
+			Summary:     blog.Summary,
+			Excerpt:     stringValue(blog.Excerpt),
+			WordCount:   blog.WordCount,
+			ReadingTime: blog.ReadingTime,
+			AuthorID:    blog.AuthorID,
+			Version:     blog.Version,
+			CreatedAt:   blog.CreatedAt,
+			UpdatedAt:   blog.UpdatedAt,
+		})
+	}
+	return domainBlogs, nil
+}
+
 func (br *BlogRepository) SearchBlogsByTitle(ctx context.Context, title string, skip, limit int) ([]domain.Blog, error) {
 	blogs := []schema.Blog{}
 
 	err := br.db.WithContext(ctx).Select(
-		"id", "title", "author_id", "created_at", "updated_at",
+		"id", "title", "slug", "summary", "excerpt", "word_count", "reading_time", "author_id", "version", "created_at", "updated_at",
 	).Where("title LIKE ?", fmt.Sprintf("%%%v%%", title)).Limit(limit).Offset((skip - 1) * limit).Find(&blogs).Error
 
 	if err != nil {
@@ -113,12 +264,18 @@ func (br *BlogRepository) SearchBlogsByTitle(ctx context.Context, title string,
 	domainBlogs := []domain.Blog{}
 	for _, blog := range blogs {
 		domainBlogs = append(domainBlogs, domain.Blog{
-			ID:        blog.ID,
-			Title:     blog.Title,
-			Text:      blog.Text,
-			AuthorID:  blog.AuthorID,
-			CreatedAt: blog.CreatedAt,
-			UpdatedAt: blog.UpdatedAt,
+			ID:          blog.ID,
+			Title:       blog.Title,
+			Slug:        stringValue(blog.Slug),
+			Summary:     blog.Summary,
+			Excerpt:     stringValue(blog.Excerpt),
+			WordCount:   blog.WordCount,
+			ReadingTime: blog.ReadingTime,
+			Text:        blog.Text,
+			AuthorID:    blog.AuthorID,
+			Version:     blog.Version,
+			CreatedAt:   blog.CreatedAt,
+			UpdatedAt:   blog.UpdatedAt,
 		})
 	}
 	return domainBlogs, nil
@@ -131,59 +288,508 @@ func (br *BlogRepository) CreateBlog(ctx context.Context, blog *domain.Blog) (*d
 	}
 
 	newBlog := &schema.Blog{
-		Title:    blog.Title,
-		Text:     blog.Text,
-		AuthorID: blog.AuthorID,
+		Title:       blog.Title,
+		Text:        blog.Text,
+		AuthorID:    blog.AuthorID,
+		CustomSlug:  blog.Slug != "",
+		Summary:     blog.Summary,
+		Excerpt:     &blog.Excerpt,
+		WordCount:   blog.WordCount,
+		ReadingTime: blog.ReadingTime,
+	}
+
+	tx := br.db.WithContext(ctx).Begin()
+
+	if err := tx.Create(newBlog).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	slug, err := assignSlug(tx, newBlog.ID, newBlog.Title, blog.Slug)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Model(newBlog).UpdateColumn("slug", slug).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	newBlog.Slug = &slug
+
+	if err := setBlogTags(tx, newBlog.ID, blog.Tags); err != nil {
+		tx.Rollback()
+		return nil, err
 	}
 
-	if err := br.db.WithContext(ctx).Create(newBlog).Error; err != nil {
+	if err := tx.Create(toRevision(newBlog, 1)).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
 
 	return &domain.Blog{
-		ID:        newBlog.ID,
-		Title:     newBlog.Title,
-		Text:      newBlog.Text,
-		AuthorID:  newBlog.AuthorID,
-		CreatedAt: newBlog.CreatedAt,
-		UpdatedAt: newBlog.UpdatedAt,
+		ID:          newBlog.ID,
+		Title:       newBlog.Title,
+		Slug:        stringValue(newBlog.Slug),
+		Tags:        blog.Tags,
+		Summary:     newBlog.Summary,
+		Excerpt:     stringValue(newBlog.Excerpt),
+		WordCount:   newBlog.WordCount,
+		ReadingTime: newBlog.ReadingTime,
+		Text:        newBlog.Text,
+		AuthorID:    newBlog.AuthorID,
+		Version:     newBlog.Version,
+		CreatedAt:   newBlog.CreatedAt,
+		UpdatedAt:   newBlog.UpdatedAt,
 	}, nil
 }
 
 func (br *BlogRepository) UpdateBlog(ctx context.Context, blog *domain.Blog) (*domain.Blog, error) {
-	updateData := &schema.Blog{
-		Title: blog.Title,
-		Text:  blog.Text,
+	updateData := map[string]interface{}{}
+	if blog.Title != "" {
+		updateData["title"] = blog.Title
+	}
+	if blog.Text != "" {
+		updateData["text"] = blog.Text
+		updateData["excerpt"] = blog.Excerpt
+		updateData["word_count"] = blog.WordCount
+		updateData["reading_time"] = blog.ReadingTime
+	}
+	if blog.Summary != "" {
+		updateData["summary"] = blog.Summary
+	}
+	if blog.Slug != "" {
+		updateData["slug"] = blog.Slug
+	}
+	if blog.Tags != nil {
+		updateData["tags"] = blog.Tags
 	}
+
+	return br.UpdateBlogByMap(ctx, blog.ID, blog.Version, &updateData)
+}
+
+func (br *BlogRepository) UpdateBlogByMap(ctx context.Context, id uuid.UUID, version int, data *map[string]interface{}) (*domain.Blog, error) {
+	updateData := map[string]interface{}{
+		"version": gorm.Expr("version + 1"),
+	}
+	slug, setSlug := "", false
+	var tags []string
+	setTags := false
+	for key, value := range *data {
+		switch key {
+		case "slug":
+			slug, setSlug = value.(string), true
+			continue
+		case "tags":
+			tags, setTags = value.([]string), true
+			continue
+		}
+		updateData[key] = value
+	}
+	title, setTitle := updateData["title"].(string)
+	_, setText := updateData["text"]
 	updatedData := &schema.Blog{}
 
-	upd := br.db.WithContext(ctx).Clauses(clause.Returning{}).Model(updatedData).Where("id = ?", blog.ID).Updates(updateData)
+	tx := br.db.WithContext(ctx).Begin()
+
+	current := &schema.Blog{}
+	if err := tx.Where("id = ?", id).First(current).Error; err != nil {
+		tx.Rollback()
+		return nil, domain.ErrNoUpdatedData
+	}
+	if version != 0 && version != current.Version {
+		tx.Rollback()
+		return nil, domain.ErrPreconditionFailed
+	}
+
+	// a slug made from the title follows it, the old slug stays reserved to redirect
+	if setSlug || (setTitle && !current.CustomSlug) {
+		if !setTitle {
+			title = current.Title
+		}
+		newSlug, err := assignSlug(tx, id, title, slug)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		updateData["slug"] = newSlug
+		updateData["custom_slug"] = slug != ""
+	}
+
+	var number int
+	err := tx.Model(&schema.BlogRevision{}).Select("COALESCE(MAX(number), 0)").Where("blog_id = ?", id).Scan(&number).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// blogs written before revisions existed keep their content as the first revision
+	if number == 0 {
+		number++
+		revision := toRevision(current, number)
+		revision.CreatedAt = current.UpdatedAt
+		if err := tx.Create(revision).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	// the version is checked again by the update, another save may have come in since the read
+	upd := tx.Clauses(clause.Returning{}).Model(updatedData).
+		Where("id = ? AND version = ?", id, current.Version).Updates(updateData)
 	if err := upd.Error; err != nil {
+		tx.Rollback()
 		return nil, err
 	}
 	if row := upd.RowsAffected; row == 0 {
-		return nil, domain.ErrNoUpdatedData
+		tx.Rollback()
+		return nil, domain.ErrPreconditionFailed
+	}
+
+	// a new slug alone does not change the content, nor do new tags
+	if setTitle || setText {
+		number++
+		if err := tx.Create(toRevision(updatedData, number)).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if setTags {
+		if err := setBlogTags(tx, id, tags); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	tags, err = blogTags(tx, id)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
 	}
 
 	return &domain.Blog{
-		ID:        updatedData.ID,
-		Title:     updatedData.Title,
-		Text:      updatedData.Text,
-		AuthorID:  updatedData.AuthorID,
-		CreatedAt: updatedData.CreatedAt,
-		UpdatedAt: updatedData.UpdatedAt,
+		ID:          updatedData.ID,
+		Title:       updatedData.Title,
+		Slug:        stringValue(updatedData.Slug),
+		Tags:        tags,
+		Summary:     updatedData.Summary,
+		Excerpt:     stringValue(updatedData.Excerpt),
+		WordCount:   updatedData.WordCount,
+		ReadingTime: updatedData.ReadingTime,
+		Text:        updatedData.Text,
+		AuthorID:    updatedData.AuthorID,
+		Version:     updatedData.Version,
+		CreatedAt:   updatedData.CreatedAt,
+		UpdatedAt:   updatedData.UpdatedAt,
 	}, nil
 }
 
-func (br *BlogRepository) DeleteBlog(ctx context.Context, id uuid.UUID) error {
-	dl := br.db.WithContext(ctx).Delete(&schema.Blog{}, id)
+func (br *BlogRepository) DeleteBlog(ctx context.Context, id uuid.UUID, version int) error {
+	query := br.db.WithContext(ctx).Where("id = ?", id)
+	if version != 0 {
+		query = query.Where("version = ?", version)
+	}
+
+	dl := query.Delete(&schema.Blog{})
 
 	if err := dl.Error; err != nil {
 		return err
 	}
 	if dl.RowsAffected == 0 {
+		if version != 0 && br.db.WithContext(ctx).Where("id = ?", id).First(&schema.Blog{}).Error == nil {
+			return domain.ErrPreconditionFailed
+		}
 		return domain.ErrNoUpdatedData
 	}
 
 	return nil
 }
+
+func (br *BlogRepository) GetDeletedBlogsByAuthorID(ctx context.Context, id uuid.UUID, skip, limit int) ([]domain.Blog, int, error) {
+	query := br.db.WithContext(ctx).Unscoped().Model(&schema.Blog{}).
+		Where("author_id = ? AND deleted_at IS NOT NULL", id)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	blogs := []schema.Blog{}
+	err := query.Select(
+		"id", "title", "slug", "summary", "excerpt", "word_count", "reading_time", "author_id", "version", "created_at", "updated_at", "deleted_at",
+	).Order("deleted_at DESC").Offset(skip).Limit(limit).Find(&blogs).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	domainBlogs := make([]domain.Blog, 0, len(blogs))
+	for _, blog := range blogs {
+		domainBlogs = append(domainBlogs, domain.Blog{
+			ID:          blog.ID,
+			Title:       blog.Title,
+			Slug:        stringValue(blog.Slug),
+			Summary:     blog.Summary,
+			Excerpt:     stringValue(blog.Excerpt),
+			WordCount:   blog.WordCount,
+			ReadingTime: blog.ReadingTime,
+			AuthorID:    blog.AuthorID,
+			Version:     blog.Version,
+			CreatedAt:   blog.CreatedAt,
+			UpdatedAt:   blog.UpdatedAt,
+			DeletedAt:   &blog.DeletedAt.Time,
+		})
+	}
+	return domainBlogs, int(total), nil
+}
+
+func (br *BlogRepository) RestoreBlog(ctx context.Context, authorID, id uuid.UUID) (*domain.Blog, error) {
+	restoredBlog := &schema.Blog{}
+
+	// update the column only, a restore is not an edit of the blog
+	upd := br.db.WithContext(ctx).Unscoped().Clauses(clause.Returning{}).Model(restoredBlog).
+		Where("id = ? AND author_id = ? AND deleted_at IS NOT NULL", id, authorID).
+		UpdateColumn("deleted_at", nil)
+	if err := upd.Error; err != nil {
+		return nil, err
+	}
+	if upd.RowsAffected == 0 {
+		return nil, domain.ErrDataNotFound
+	}
+
+	tags, err := blogTags(br.db.WithContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Blog{
+		ID:          restoredBlog.ID,
+		Title:       restoredBlog.Title,
+		Slug:        stringValue(restoredBlog.Slug),
+		Tags:        tags,
+		Summary:     restoredBlog.Summary,
+		Excerpt:     stringValue(restoredBlog.Excerpt),
+		WordCount:   restoredBlog.WordCount,
+		ReadingTime: restoredBlog.ReadingTime,
+		Text:        restoredBlog.Text,
+		AuthorID:    restoredBlog.AuthorID,
+		Version:     restoredBlog.Version,
+		CreatedAt:   restoredBlog.CreatedAt,
+		UpdatedAt:   restoredBlog.UpdatedAt,
+	}, nil
+}
+
+func (br *BlogRepository) PurgeBlogs(ctx context.Context, before time.Time) (int, error) {
+	ids := []uuid.UUID{}
+	tx := br.db.WithContext(ctx).Unscoped().Begin()
+
+	err := tx.Model(&schema.Blog{}).Where("deleted_at < ?", before).Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err = tx.Where("blog_id IN ?", ids).Delete(&schema.BlogRevision{}).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if err = tx.Where("blog_id IN ?", ids).Delete(&schema.BlogSlug{}).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if err = tx.Where("blog_id IN ?", ids).Delete(&schema.BlogTag{}).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if err = tx.Where("blog_id IN ?", ids).Delete(&schema.BlogReaction{}).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if err = tx.Where("blog_id IN ?", ids).Delete(&schema.BlogReactionCount{}).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if err = tx.Where("blog_id IN ?", ids).Delete(&schema.Bookmark{}).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if err = tx.Where("blog_id IN ?", ids).Delete(&schema.BlogViewDaily{}).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	if err = tx.Where("blog_id IN ?", ids).Delete(&schema.BlogReferrerDaily{}).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	dl := tx.Where("id IN ?", ids).Delete(&schema.Blog{})
+	if err = dl.Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return 0, err
+	}
+	return int(dl.RowsAffected), nil
+}
+
+func (br *BlogRepository) AssignSlugs(ctx context.Context) (int, error) {
+	blogs := []schema.Blog{}
+
+	// blogs in the trash get one too, they can be restored
+	err := br.db.WithContext(ctx).Unscoped().Select("id", "title").Where("slug IS NULL").Find(&blogs).Error
+	if err != nil || len(blogs) == 0 {
+		return 0, err
+	}
+
+	tx := br.db.WithContext(ctx).Unscoped().Begin()
+
+	for _, blog := range blogs {
+		slug, err := assignSlug(tx, blog.ID, blog.Title, "")
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		if err := tx.Model(&blog).UpdateColumn("slug", slug).Error; err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return 0, err
+	}
+	return len(blogs), nil
+}
+
+func (br *BlogRepository) GetBlogsForMetadata(ctx context.Context, after uuid.UUID, all bool, limit int) ([]domain.Blog, error) {
+	blogs := []schema.Blog{}
+
+	// blogs in the trash get it too, they can be restored
+	query := br.db.WithContext(ctx).Unscoped().Select("id", "text").Where("id > ?", after)
+	if !all {
+		query = query.Where("excerpt IS NULL")
+	}
+	if err := query.Order("id").Limit(limit).Find(&blogs).Error; err != nil {
+		return nil, err
+	}
+
+	domainBlogs := make([]domain.Blog, 0, len(blogs))
+	for _, blog := range blogs {
+		domainBlogs = append(domainBlogs, domain.Blog{
+			ID:   blog.ID,
+			Text: blog.Text,
+		})
+	}
+	return domainBlogs, nil
+}
+
+func (br *BlogRepository) SetBlogMetadata(ctx context.Context, id uuid.UUID, metadata *domain.TextMetadata) error {
+	// update the columns only, computing the metadata is not an edit of the blog
+	return br.db.WithContext(ctx).Unscoped().Model(&schema.Blog{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"excerpt":      metadata.Excerpt,
+		"word_count":   metadata.WordCount,
+		"reading_time": metadata.ReadingTime,
+	}).Error
+}
+
+// setBlogTags replace the tags of a blog
+func setBlogTags(tx *gorm.DB, blogID uuid.UUID, tags []string) error {
+	if err := tx.Where("blog_id = ?", blogID).Delete(&schema.BlogTag{}).Error; err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	rows := make([]schema.BlogTag, 0, len(tags))
+	for _, tag := range tags {
+		rows = append(rows, schema.BlogTag{BlogID: blogID, Tag: tag})
+	}
+	return tx.Create(&rows).Error
+}
+
+// blogTags select the tags of a blog in alphabetical order
+func blogTags(db *gorm.DB, blogID uuid.UUID) ([]string, error) {
+	tags := []string{}
+	err := db.Model(&schema.BlogTag{}).Where("blog_id = ?", blogID).Order("tag").Pluck("tag", &tags).Error
+	return tags, err
+}
+
+// slugCandidates is the number of slugs checked at once when looking for a free one
+const slugCandidates = 20
+
+// defaultSlug is the slug base of titles without any letter or digit
+const defaultSlug = "blog"
+
+// assignSlug reserve the slug of a blog and return it, a custom slug taken by another blog is a conflict,
+// without one the first free slug made from the title is used, numbered if needed.
+// The slugs a blog had before are free for it again
+func assignSlug(tx *gorm.DB, blogID uuid.UUID, title, custom string) (string, error) {
+	candidates := []string{custom}
+	base := util.Slugify(title)
+	if base == "" {
+		base = defaultSlug
+	}
+
+	for n := 1; ; {
+		if custom == "" {
+			candidates = candidates[:0]
+			for end := n + slugCandidates; n < end; n++ {
+				if n == 1 {
+					candidates = append(candidates, base)
+				} else {
+					candidates = append(candidates, util.SlugWithSuffix(base, n))
+				}
+			}
+		}
+
+		taken := []schema.BlogSlug{}
+		if err := tx.Where("slug IN ?", candidates).Find(&taken).Error; err != nil {
+			return "", err
+		}
+		owners := make(map[string]uuid.UUID, len(taken))
+		for _, blogSlug := range taken {
+			owners[blogSlug.Slug] = blogSlug.BlogID
+		}
+
+		for _, slug := range candidates {
+			owner, ok := owners[slug]
+			if !ok {
+				if err := tx.Create(&schema.BlogSlug{Slug: slug, BlogID: blogID}).Error; err != nil {
+					if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+						return "", domain.ErrConflictingData
+					}
+					return "", err
+				}
+				return slug, nil
+			}
+			if owner == blogID {
+				return slug, nil
+			}
+		}
+
+		if custom != "" {
+			return "", domain.ErrConflictingData
+		}
+	}
+}
+
+// toRevision make the revision with the number from the content of a blog
+func toRevision(blog *schema.Blog, number int) *schema.BlogRevision {
+	return &schema.BlogRevision{
+		BlogID:      blog.ID,
+		Number:      number,
+		AuthorID:    blog.AuthorID,
+		Title:       blog.Title,
+		Text:        blog.Text,
+		ContentHash: util.HashContent(blog.Title, blog.Text),
+	}
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite/schema"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+// implement ports.IBlogRevisionRepository
+type BlogRevisionRepository struct {
+	db *sqlite.DB
+}
+
+func NewBlogRevisionRepository(db *sqlite.DB) ports.IBlogRevisionRepository {
+	return &BlogRevisionRepository{
+		db: db,
+	}
+}
+
+func (rr *BlogRevisionRepository) GetRevisions(ctx context.Context, blogID uuid.UUID, skip, limit int) ([]domain.BlogRevision, int, error) {
+	query := rr.db.WithContext(ctx).Model(&schema.BlogRevision{}).Where("blog_id = ?", blogID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	revisions := []schema.BlogRevision{}
+	err := query.Select(
+		"id", "blog_id", "number", "author_id", "title", "content_hash", "created_at",
+	).Order("number DESC").Offset(skip).Limit(limit).Find(&revisions).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]domain.BlogRevision, 0, len(revisions))
+	for i := range revisions {
+		result = append(result, *toDomainBlogRevision(&revisions[i]))
+	}
+
+	return result, int(total), nil
+}
+
+func (rr *BlogRevisionRepository) GetRevision(ctx context.Context, blogID uuid.UUID, number int) (*domain.BlogRevision, error) {
+	revision := &schema.BlogRevision{}
+
+	err := rr.db.WithContext(ctx).Where("blog_id = ? AND number = ?", blogID, number).First(revision).Error
+	if err != nil {
+		return nil, domain.ErrDataNotFound
+	}
+
+	return toDomainBlogRevision(revision), nil
+}
+
+func toDomainBlogRevision(revision *schema.BlogRevision) *domain.BlogRevision {
+	return &domain.BlogRevision{
+		ID:          revision.ID,
+		BlogID:      revision.BlogID,
+		Number:      revision.Number,
+		AuthorID:    revision.AuthorID,
+		Title:       revision.Title,
+		Text:        revision.Text,
+		ContentHash: revision.ContentHash,
+		CreatedAt:   revision.CreatedAt,
+	}
+}
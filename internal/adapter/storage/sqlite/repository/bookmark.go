@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite/schema"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"gorm.io/gorm/clause"
+)
+
+// implement ports.IBookmarkRepository
+type BookmarkRepository struct {
+	db *sqlite.DB
+}
+
+func NewBookmarkRepository(db *sqlite.DB) ports.IBookmarkRepository {
+	return &BookmarkRepository{
+		db: db,
+	}
+}
+
+func (br *BookmarkRepository) SaveBookmark(ctx context.Context, bookmark *domain.Bookmark) (*domain.Bookmark, error) {
+	saved := &schema.Bookmark{
+		UserID:    bookmark.UserID,
+		BlogID:    bookmark.BlogID,
+		Note:      bookmark.Note,
+		CreatedAt: bookmark.CreatedAt,
+	}
+
+	// the note is replaced, the bookmark keeps the time it was first saved
+	err := br.db.WithContext(ctx).Clauses(
+		clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "blog_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"note"}),
+		},
+		clause.Returning{},
+	).Create(saved).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Bookmark{
+		UserID:    saved.UserID,
+		BlogID:    saved.BlogID,
+		Note:      saved.Note,
+		CreatedAt: saved.CreatedAt,
+	}, nil
+}
+
+func (br *BookmarkRepository) DeleteBookmark(ctx context.Context, userID, blogID uuid.UUID) error {
+	dl := br.db.WithContext(ctx).Where("user_id = ? AND blog_id = ?", userID, blogID).Delete(&schema.Bookmark{})
+	if dl.Error != nil {
+		return dl.Error
+	}
+	if dl.RowsAffected == 0 {
+		return domain.ErrDataNotFound
+	}
+	return nil
+}
+
+// bookmarkRow is a bookmark joined with the summary of its blog
+type bookmarkRow struct {
+	BlogID          uuid.UUID
+	Note            string
+	CreatedAt       time.Time
+	BlogTitle       string
+	BlogSlug        *string
+	BlogSummary     string
+	BlogExcerpt     *string
+	BlogWordCount   int
+	BlogReadingTime int
+	BlogAuthorID    uuid.UUID
+	BlogVersion     int
+	BlogCreatedAt   time.Time
+	BlogUpdatedAt   time.Time
+}
+
+func (br *BookmarkRepository) GetBookmarks(ctx context.Context, userID uuid.UUID, skip, limit int) ([]domain.Bookmark, int, error) {
+	query := br.db.WithContext(ctx).Model(&schema.Bookmark{}).
+		Joins("JOIN blogs ON blogs.id = bookmarks.blog_id AND blogs.deleted_at IS NULL").
+		Where("bookmarks.user_id = ?", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	rows := []bookmarkRow{}
+	err := query.Select(
+		"bookmarks.blog_id", "bookmarks.note", "bookmarks.created_at",
+		"blogs.title AS blog_title", "blogs.slug AS blog_slug", "blogs.summary AS blog_summary",
+		"blogs.excerpt AS blog_excerpt", "blogs.word_count AS blog_word_count", "blogs.reading_time AS blog_reading_time", "blogs.author_id AS blog_author_id", "blogs.version AS blog_version",
+		"blogs.created_at AS blog_created_at", "blogs.updated_at AS blog_updated_at",
+	).Order("bookmarks.created_at DESC").Offset(skip).Limit(limit).Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	bookmarks := make([]domain.Bookmark, 0, len(rows))
+	for _, row := range rows {
+		bookmarks = append(bookmarks, domain.Bookmark{
+			UserID:    userID,
+			BlogID:    row.BlogID,
+			Note:      row.Note,
+			CreatedAt: row.CreatedAt,
+			Blog: &domain.Blog{
+				ID:          row.BlogID,
+				Title:       row.BlogTitle,
+				Slug:        stringValue(row.BlogSlug),
+				Summary:     row.BlogSummary,
+				Excerpt:     stringValue(row.BlogExcerpt),
+				WordCount:   row.BlogWordCount,
+				ReadingTime: row.BlogReadingTime,
+				AuthorID:    row.BlogAuthorID,
+				Version:     row.BlogVersion,
+				CreatedAt:   row.BlogCreatedAt,
+				UpdatedAt:   row.BlogUpdatedAt,
+			},
+		})
+	}
+	return bookmarks, int(total), nil
+}
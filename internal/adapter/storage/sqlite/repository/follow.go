@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite/schema"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"gorm.io/gorm/clause"
+)
+
+// implement ports.IFollowRepository
+type FollowRepository struct {
+	db *sqlite.DB
+}
+
+func NewFollowRepository(db *sqlite.DB) ports.IFollowRepository {
+	return &FollowRepository{
+		db: db,
+	}
+}
+
+func (fr *FollowRepository) SaveFollow(ctx context.Context, follow *domain.Follow) error {
+	return fr.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&schema.Follow{
+		FollowerID: follow.FollowerID,
+		FolloweeID: follow.FolloweeID,
+		CreatedAt:  follow.CreatedAt,
+	}).Error
+}
+
+func (fr *FollowRepository) DeleteFollow(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	dl := fr.db.WithContext(ctx).Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Delete(&schema.Follow{})
+	if dl.Error != nil {
+		return dl.Error
+	}
+	if dl.RowsAffected == 0 {
+		return domain.ErrDataNotFound
+	}
+	return nil
+}
+
+// followRow is a follow joined with the user on the other side of it
+type followRow struct {
+	FollowerID    uuid.UUID
+	FolloweeID    uuid.UUID
+	CreatedAt     time.Time
+	UserName      string
+	UserCreatedAt time.Time
+	UserUpdatedAt time.Time
+}
+
+func (fr *FollowRepository) GetFollowers(ctx context.Context, userID uuid.UUID, skip, limit int) ([]domain.Follow, int, error) {
+	return fr.getFollows(ctx, "followee_id", "follower_id", userID, skip, limit)
+}
+
+func (fr *FollowRepository) GetFollowing(ctx context.Context, userID uuid.UUID, skip, limit int) ([]domain.Follow, int, error) {
+	return fr.getFollows(ctx, "follower_id", "followee_id", userID, skip, limit)
+}
+
+// getFollows select the follows where the column is the user, joined with the user of the other column
+func (fr *FollowRepository) getFollows(ctx context.Context, column, other string, userID uuid.UUID, skip, limit int) ([]domain.Follow, int, error) {
+	query := fr.db.WithContext(ctx).Model(&schema.Follow{}).
+		Joins("JOIN users ON users.id = follows."+other+" AND users.deleted_at IS NULL").
+		Where("follows."+column+" = ?", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	rows := []followRow{}
+	err := query.Select(
+		"follows.follower_id", "follows.followee_id", "follows.created_at",
+		"users.name AS user_name", "users.created_at AS user_created_at", "users.updated_at AS user_updated_at",
+	).Order("follows.created_at DESC").Offset(skip).Limit(limit).Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	follows := make([]domain.Follow, 0, len(rows))
+	for _, row := range rows {
+		user := &domain.User{
+			ID:        row.FollowerID,
+			Name:      row.UserName,
+			CreatedAt: row.UserCreatedAt,
+			UpdatedAt: row.UserUpdatedAt,
+		}
+		if other == "followee_id" {
+			user.ID = row.FolloweeID
+		}
+
+		follows = append(follows, domain.Follow{
+			FollowerID: row.FollowerID,
+			FolloweeID: row.FolloweeID,
+			CreatedAt:  row.CreatedAt,
+			User:       user,
+		})
+	}
+	return follows, int(total), nil
+}
+
+func (fr *FollowRepository) GetFolloweeIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	err := fr.db.WithContext(ctx).Model(&schema.Follow{}).Where("follower_id = ?", userID).Pluck("followee_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (fr *FollowRepository) CountFollows(ctx context.Context, userID uuid.UUID) (*domain.FollowCounts, error) {
+	var followers, following int64
+
+	err := fr.db.WithContext(ctx).Model(&schema.Follow{}).
+		Joins("JOIN users ON users.id = follows.follower_id AND users.deleted_at IS NULL").
+		Where("follows.followee_id = ?", userID).Count(&followers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = fr.db.WithContext(ctx).Model(&schema.Follow{}).
+		Joins("JOIN users ON users.id = follows.followee_id AND users.deleted_at IS NULL").
+		Where("follows.follower_id = ?", userID).Count(&following).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.FollowCounts{
+		UserID:    userID,
+		Followers: int(followers),
+		Following: int(following),
+	}, nil
+}
@@ -0,0 +1,27 @@
+package repository
+
+import "strings"
+
+// likeEscaper escape the wildcards of a LIKE pattern, used with ESCAPE '\'
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// nullString return nil for an empty string, used for nullable unique columns
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// stringValue return the value of a nullable string column
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// escapeLike escape s to match it literally in a LIKE pattern
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite/schema"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+// implement ports.IPasskeyRepository
+type PasskeyRepository struct {
+	db *sqlite.DB
+}
+
+func NewPasskeyRepository(db *sqlite.DB) ports.IPasskeyRepository {
+	return &PasskeyRepository{
+		db: db,
+	}
+}
+
+func (pr *PasskeyRepository) CreatePasskey(ctx context.Context, passkey *domain.Passkey) (*domain.Passkey, error) {
+	createdPasskey := &schema.Passkey{
+		UserID:          passkey.UserID,
+		Name:            passkey.Name,
+		CredentialID:    passkey.CredentialID,
+		PublicKey:       passkey.PublicKey,
+		AttestationType: passkey.AttestationType,
+		Transports:      strings.Join(passkey.Transports, ","),
+		AAGUID:          passkey.AAGUID,
+		SignCount:       passkey.SignCount,
+		BackupEligible:  passkey.BackupEligible,
+		BackupState:     passkey.BackupState,
+	}
+
+	if err := pr.db.WithContext(ctx).Create(createdPasskey).Error; err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return toDomainPasskey(createdPasskey), nil
+}
+
+func (pr *PasskeyRepository) GetPasskeysByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Passkey, error) {
+	passkeys := []schema.Passkey{}
+
+	err := pr.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at").Find(&passkeys).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Passkey, 0, len(passkeys))
+	for i := range passkeys {
+		result = append(result, *toDomainPasskey(&passkeys[i]))
+	}
+
+	return result, nil
+}
+
+func (pr *PasskeyRepository) GetPasskeyByCredentialID(ctx context.Context, credentialID []byte) (*domain.Passkey, error) {
+	passkey := &schema.Passkey{}
+
+	err := pr.db.WithContext(ctx).Where("credential_id = ?", credentialID).First(passkey).Error
+	if err != nil {
+		return nil, domain.ErrDataNotFound
+	}
+
+	return toDomainPasskey(passkey), nil
+}
+
+func (pr *PasskeyRepository) UpdateSignCount(ctx context.Context, id uuid.UUID, signCount uint32, backupState bool, usedAt time.Time) error {
+	// the condition makes two logins with the same counter race to a single winner,
+	// except for authenticators that do not count and always send 0, their replays are
+	// stopped by the single use of the challenge
+	upd := pr.db.WithContext(ctx).Model(&schema.Passkey{}).
+		Where("id = ? AND (sign_count < ? OR (sign_count = 0 AND ? = 0))", id, signCount, signCount).
+		Updates(map[string]interface{}{
+			"sign_count":   signCount,
+			"backup_state": backupState,
+			"last_used_at": usedAt,
+		})
+
+	if err := upd.Error; err != nil {
+		return err
+	}
+	if upd.RowsAffected == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (pr *PasskeyRepository) DeletePasskey(ctx context.Context, userID, id uuid.UUID) error {
+	d := pr.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&schema.Passkey{})
+
+	if err := d.Error; err != nil {
+		return err
+	}
+	if d.RowsAffected == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func toDomainPasskey(passkey *schema.Passkey) *domain.Passkey {
+	var transports []string
+	if passkey.Transports != "" {
+		transports = strings.Split(passkey.Transports, ",")
+	}
+
+	return &domain.Passkey{
+		ID:              passkey.ID,
+		UserID:          passkey.UserID,
+		Name:            passkey.Name,
+		CredentialID:    passkey.CredentialID,
+		PublicKey:       passkey.PublicKey,
+		AttestationType: passkey.AttestationType,
+		Transports:      transports,
+		AAGUID:          passkey.AAGUID,
+		SignCount:       passkey.SignCount,
+		BackupEligible:  passkey.BackupEligible,
+		BackupState:     passkey.BackupState,
+		LastUsedAt:      passkey.LastUsedAt,
+		CreatedAt:       passkey.CreatedAt,
+	}
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite/schema"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"gorm.io/gorm/clause"
+)
+
+// implement ports.IReactionRepository
+type ReactionRepository struct {
+	db *sqlite.DB
+}
+
+func NewReactionRepository(db *sqlite.DB) ports.IReactionRepository {
+	return &ReactionRepository{
+		db: db,
+	}
+}
+
+func (rr *ReactionRepository) AddReaction(ctx context.Context, reaction *domain.BlogReaction) (bool, error) {
+	ins := rr.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&schema.BlogReaction{
+		BlogID:    reaction.BlogID,
+		UserID:    reaction.UserID,
+		Kind:      reaction.Kind,
+		CreatedAt: reaction.CreatedAt,
+	})
+	if ins.Error != nil {
+		return false, ins.Error
+	}
+	return ins.RowsAffected > 0, nil
+}
+
+func (rr *ReactionRepository) RemoveReaction(ctx context.Context, blogID, userID uuid.UUID, kind string) (bool, error) {
+	dl := rr.db.WithContext(ctx).Where("blog_id = ? AND user_id = ? AND kind = ?", blogID, userID, kind).Delete(&schema.BlogReaction{})
+	if dl.Error != nil {
+		return false, dl.Error
+	}
+	return dl.RowsAffected > 0, nil
+}
+
+func (rr *ReactionRepository) GetReactionCounts(ctx context.Context, blogIDs []uuid.UUID) (map[uuid.UUID]domain.ReactionCounts, error) {
+	rows := []schema.BlogReactionCount{}
+	err := rr.db.WithContext(ctx).Model(&schema.BlogReaction{}).
+		Select("blog_reactions.blog_id", "blog_reactions.kind", "COUNT(*) AS count").
+		Joins("JOIN users ON users.id = blog_reactions.user_id AND users.deleted_at IS NULL").
+		Where("blog_reactions.blog_id IN ?", blogIDs).
+		Group("blog_reactions.blog_id").Group("blog_reactions.kind").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return reactionCountsOf(rows), nil
+}
+
+func (rr *ReactionRepository) ReconcileReactionCounts(ctx context.Context, kinds []string) (map[uuid.UUID]domain.ReactionCounts, error) {
+	tx := rr.db.WithContext(ctx).Begin()
+
+	before := []schema.BlogReactionCount{}
+	if err := tx.Find(&before).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Where("1 = 1").Delete(&schema.BlogReactionCount{}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if len(kinds) > 0 {
+		err := tx.Exec(`INSERT INTO blog_reaction_counts (blog_id, kind, count)
+			SELECT r.blog_id, r.kind, COUNT(*) FROM blog_reactions r
+			JOIN users u ON u.id = r.user_id AND u.deleted_at IS NULL
+			WHERE r.kind IN ?
+			GROUP BY r.blog_id, r.kind`, kinds).Error
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	after := []schema.BlogReactionCount{}
+	if err := tx.Find(&after).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	// a blog that lost every reaction has empty counts, its counters are set back to 0
+	oldCounts, counts := reactionCountsOf(before), reactionCountsOf(after)
+	for id := range oldCounts {
+		if _, ok := counts[id]; !ok {
+			counts[id] = domain.ReactionCounts{}
+		}
+	}
+
+	return counts, nil
+}
+
+func reactionCountsOf(rows []schema.BlogReactionCount) map[uuid.UUID]domain.ReactionCounts {
+	counts := map[uuid.UUID]domain.ReactionCounts{}
+	for _, row := range rows {
+		if counts[row.BlogID] == nil {
+			counts[row.BlogID] = domain.ReactionCounts{}
+		}
+		counts[row.BlogID][row.Kind] = row.Count
+	}
+	return counts
+}
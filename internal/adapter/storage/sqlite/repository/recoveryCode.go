@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite/schema"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+// implement ports.IRecoveryCodeRepository
+type RecoveryCodeRepository struct {
+	db *sqlite.DB
+}
+
+func NewRecoveryCodeRepository(db *sqlite.DB) ports.IRecoveryCodeRepository {
+	return &RecoveryCodeRepository{
+		db: db,
+	}
+}
+
+func (rr *RecoveryCodeRepository) ReplaceCodes(ctx context.Context, userID uuid.UUID, hashes []string) error {
+	var err error
+	tx := rr.db.WithContext(ctx).Begin()
+
+	if err = tx.Where("user_id = ?", userID).Delete(&schema.RecoveryCode{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	codes := make([]schema.RecoveryCode, 0, len(hashes))
+	for _, hash := range hashes {
+		codes = append(codes, schema.RecoveryCode{
+			UserID:   userID,
+			CodeHash: hash,
+		})
+	}
+
+	if err = tx.Create(&codes).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (rr *RecoveryCodeRepository) ConsumeCode(ctx context.Context, userID uuid.UUID, hash string) error {
+	upd := rr.db.WithContext(ctx).Model(&schema.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).Update("used_at", time.Now())
+
+	if err := upd.Error; err != nil {
+		return err
+	}
+	if upd.RowsAffected == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (rr *RecoveryCodeRepository) DeleteCodes(ctx context.Context, userID uuid.UUID) error {
+	return rr.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&schema.RecoveryCode{}).Error
+}
@@ -3,12 +3,14 @@ package repository
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite"
 	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite/schema"
 	"github.com/tommjj/go-blog-api/internal/core/domain"
 	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
@@ -32,11 +34,18 @@ func (ur *UserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*domai
 	}
 
 	return &domain.User{
-		ID:        user.ID,
-		Name:      user.Name,
-		Password:  user.Password,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         stringValue(user.Email),
+		EmailVerified: user.EmailVerified,
+		Password:      user.Password,
+		TokenVersion:  user.TokenVersion,
+		TOTPSecret:    user.TOTPSecret,
+		TOTPEnabled:   user.TOTPEnabled,
+		Role:          domain.UserRole(user.Role),
+		Status:        domain.UserStatus(user.Status),
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
 	}, nil
 }
 
@@ -49,18 +58,100 @@ func (ur *UserRepository) GetUserByName(ctx context.Context, name string) (*doma
 	}
 
 	return &domain.User{
-		ID:        user.ID,
-		Name:      user.Name,
-		Password:  user.Password,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         stringValue(user.Email),
+		EmailVerified: user.EmailVerified,
+		Password:      user.Password,
+		TokenVersion:  user.TokenVersion,
+		TOTPSecret:    user.TOTPSecret,
+		TOTPEnabled:   user.TOTPEnabled,
+		Role:          domain.UserRole(user.Role),
+		Status:        domain.UserStatus(user.Status),
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
 	}, nil
 }
 
+func (ur *UserRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	user := &schema.User{}
+
+	err := ur.db.WithContext(ctx).Where("email = ?", email).First(user).Error
+	if err != nil {
+		return nil, domain.ErrDataNotFound
+	}
+
+	return &domain.User{
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         stringValue(user.Email),
+		EmailVerified: user.EmailVerified,
+		Password:      user.Password,
+		TokenVersion:  user.TokenVersion,
+		TOTPSecret:    user.TOTPSecret,
+		TOTPEnabled:   user.TOTPEnabled,
+		Role:          domain.UserRole(user.Role),
+		Status:        domain.UserStatus(user.Status),
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}, nil
+}
+
+func (ur *UserRepository) ListUsers(ctx context.Context, filter domain.UserFilter, skip, limit int) ([]domain.User, int, error) {
+	query := ur.db.WithContext(ctx).Model(&schema.User{})
+
+	if filter.NamePrefix != "" {
+		query = query.Where("name LIKE ? ESCAPE '\\'", escapeLike(filter.NamePrefix)+"%")
+	}
+	if filter.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		query = query.Where("created_at < ?", *filter.CreatedBefore)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	users := []schema.User{}
+	err := query.Order("created_at DESC").Offset(skip).Limit(limit).Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	domainUsers := make([]domain.User, 0, len(users))
+	for _, user := range users {
+		domainUsers = append(domainUsers, domain.User{
+			ID:            user.ID,
+			Name:          user.Name,
+			Email:         stringValue(user.Email),
+			EmailVerified: user.EmailVerified,
+			TokenVersion:  user.TokenVersion,
+			TOTPEnabled:   user.TOTPEnabled,
+			Role:          domain.UserRole(user.Role),
+			Status:        domain.UserStatus(user.Status),
+			CreatedAt:     user.CreatedAt,
+			UpdatedAt:     user.UpdatedAt,
+		})
+	}
+
+	return domainUsers, int(total), nil
+}
+
 func (ur *UserRepository) CreateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
 	createdUser := &schema.User{
-		Name:     user.Name,
-		Password: user.Password,
+		Name:          user.Name,
+		Email:         nullString(user.Email),
+		EmailVerified: user.EmailVerified,
+		Password:      user.Password,
 	}
 
 	if err := ur.db.WithContext(ctx).Create(createdUser).Error; err != nil {
@@ -71,11 +162,18 @@ func (ur *UserRepository) CreateUser(ctx context.Context, user *domain.User) (*d
 	}
 
 	return &domain.User{
-		ID:        createdUser.ID,
-		Name:      createdUser.Name,
-		Password:  createdUser.Password,
-		CreatedAt: createdUser.CreatedAt,
-		UpdatedAt: createdUser.UpdatedAt,
+		ID:            createdUser.ID,
+		Name:          createdUser.Name,
+		Email:         stringValue(createdUser.Email),
+		EmailVerified: createdUser.EmailVerified,
+		Password:      createdUser.Password,
+		TokenVersion:  createdUser.TokenVersion,
+		TOTPSecret:    createdUser.TOTPSecret,
+		TOTPEnabled:   createdUser.TOTPEnabled,
+		Role:          domain.UserRole(createdUser.Role),
+		Status:        domain.UserStatus(createdUser.Status),
+		CreatedAt:     createdUser.CreatedAt,
+		UpdatedAt:     createdUser.UpdatedAt,
 	}, nil
 }
 
@@ -84,6 +182,7 @@ func (ur *UserRepository) UpdateUser(ctx context.Context, user *domain.User) (*d
 	updatedUser := schema.User{
 		ID:       user.ID,
 		Name:     user.Name,
+		Email:    nullString(user.Email),
 		Password: user.Password,
 	}
 
@@ -102,11 +201,18 @@ func (ur *UserRepository) UpdateUser(ctx context.Context, user *domain.User) (*d
 	}
 
 	return &domain.User{
-		ID:        newUserData.ID,
-		Name:      newUserData.Name,
-		Password:  newUserData.Password,
-		CreatedAt: newUserData.CreatedAt,
-		UpdatedAt: newUserData.UpdatedAt,
+		ID:            newUserData.ID,
+		Name:          newUserData.Name,
+		Email:         stringValue(newUserData.Email),
+		EmailVerified: newUserData.EmailVerified,
+		Password:      newUserData.Password,
+		TokenVersion:  newUserData.TokenVersion,
+		TOTPSecret:    newUserData.TOTPSecret,
+		TOTPEnabled:   newUserData.TOTPEnabled,
+		Role:          domain.UserRole(newUserData.Role),
+		Status:        domain.UserStatus(newUserData.Status),
+		CreatedAt:     newUserData.CreatedAt,
+		UpdatedAt:     newUserData.UpdatedAt,
 	}, nil
 }
 
@@ -127,24 +233,45 @@ func (ur *UserRepository) UpdateUserByMap(ctx context.Context, id uuid.UUID, dat
 	}
 
 	return &domain.User{
-		ID:        updatedUser.ID,
-		Name:      updatedUser.Name,
-		Password:  updatedUser.Password,
-		CreatedAt: updatedUser.CreatedAt,
-		UpdatedAt: updatedUser.UpdatedAt,
+		ID:            updatedUser.ID,
+		Name:          updatedUser.Name,
+		Email:         stringValue(updatedUser.Email),
+		EmailVerified: updatedUser.EmailVerified,
+		Password:      updatedUser.Password,
+		TokenVersion:  updatedUser.TokenVersion,
+		TOTPSecret:    updatedUser.TOTPSecret,
+		TOTPEnabled:   updatedUser.TOTPEnabled,
+		Role:          domain.UserRole(updatedUser.Role),
+		Status:        domain.UserStatus(updatedUser.Status),
+		CreatedAt:     updatedUser.CreatedAt,
+		UpdatedAt:     updatedUser.UpdatedAt,
 	}, nil
 }
 
+func (ur *UserRepository) UpdateCredentialsByMap(ctx context.Context, id uuid.UUID, data *map[string]interface{}) (*domain.User, error) {
+	updateData := map[string]interface{}{
+		"token_version": gorm.Expr("token_version + 1"),
+	}
+	for key, value := range *data {
+		updateData[key] = value
+	}
+
+	return ur.UpdateUserByMap(ctx, id, &updateData)
+}
+
+// DeleteUser soft delete the user and its blogs at the same time, RestoreUser relies on it
+// to tell them from the blogs deleted before
 func (ur *UserRepository) DeleteUser(ctx context.Context, id uuid.UUID) error {
 	var err error
+	now := time.Now()
 	tx := ur.db.WithContext(ctx).Begin()
 
-	if err = tx.Where("author_id = ?", id).Delete(&schema.Blog{}).Error; err != nil {
+	if err = tx.Model(&schema.Blog{}).Where("author_id = ?", id).UpdateColumn("deleted_at", now).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
 
-	d := tx.Delete(&schema.User{}, id)
+	d := tx.Model(&schema.User{}).Where("id = ?", id).UpdateColumn("deleted_at", now)
 
 	if err := d.Error; err != nil {
 		tx.Rollback()
@@ -158,3 +285,138 @@ func (ur *UserRepository) DeleteUser(ctx context.Context, id uuid.UUID) error {
 	tx.Commit()
 	return nil
 }
+
+func (ur *UserRepository) GetDeletedUsers(ctx context.Context, skip, limit int) ([]domain.User, int, error) {
+	query := ur.db.WithContext(ctx).Unscoped().Model(&schema.User{}).Where("deleted_at IS NOT NULL")
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	users := []schema.User{}
+	err := query.Order("deleted_at DESC").Offset(skip).Limit(limit).Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	domainUsers := make([]domain.User, 0, len(users))
+	for _, user := range users {
+		domainUsers = append(domainUsers, domain.User{
+			ID:            user.ID,
+			Name:          user.Name,
+			Email:         stringValue(user.Email),
+			EmailVerified: user.EmailVerified,
+			TokenVersion:  user.TokenVersion,
+			TOTPEnabled:   user.TOTPEnabled,
+			Role:          domain.UserRole(user.Role),
+			Status:        domain.UserStatus(user.Status),
+			CreatedAt:     user.CreatedAt,
+			UpdatedAt:     user.UpdatedAt,
+			DeletedAt:     &user.DeletedAt.Time,
+		})
+	}
+
+	return domainUsers, int(total), nil
+}
+
+func (ur *UserRepository) RestoreUser(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	user := &schema.User{}
+	tx := ur.db.WithContext(ctx).Unscoped().Begin()
+
+	if err := tx.Where("id = ? AND deleted_at IS NOT NULL", id).First(user).Error; err != nil {
+		tx.Rollback()
+		return nil, domain.ErrDataNotFound
+	}
+
+	// the blogs the user had deleted itself stay in the trash
+	err := tx.Model(&schema.Blog{}).Where("author_id = ? AND deleted_at = ?", id, user.DeletedAt).
+		UpdateColumn("deleted_at", nil).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err = tx.Model(&schema.User{}).Where("id = ?", id).UpdateColumn("deleted_at", nil).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	tx.Commit()
+
+	return &domain.User{
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         stringValue(user.Email),
+		EmailVerified: user.EmailVerified,
+		TokenVersion:  user.TokenVersion,
+		TOTPEnabled:   user.TOTPEnabled,
+		Role:          domain.UserRole(user.Role),
+		Status:        domain.UserStatus(user.Status),
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}, nil
+}
+
+// PurgeUsers also delete the rows referencing the users, sqlite does not enforce the foreign keys
+func (ur *UserRepository) PurgeUsers(ctx context.Context, before time.Time) (int, error) {
+	ids := []uuid.UUID{}
+	tx := ur.db.WithContext(ctx).Unscoped().Begin()
+
+	err := tx.Model(&schema.User{}).Where("deleted_at < ?", before).Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		tx.Rollback()
+		return 0, err
+	}
+
+	blogIDs := tx.Model(&schema.Blog{}).Select("id").Where("author_id IN ?", ids)
+	for _, model := range []interface{}{
+		&schema.BlogRevision{},
+		&schema.BlogSlug{},
+		&schema.BlogTag{},
+		&schema.BlogReaction{},
+		&schema.BlogReactionCount{},
+		&schema.Bookmark{},
+		&schema.BlogViewDaily{},
+		&schema.BlogReferrerDaily{},
+	} {
+		if err = tx.Where("blog_id IN (?)", blogIDs).Delete(model).Error; err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	if err = tx.Where("author_id IN ?", ids).Delete(&schema.Blog{}).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	for _, model := range []interface{}{
+		&schema.ActionToken{},
+		&schema.RecoveryCode{},
+		&schema.Passkey{},
+		&schema.UserIdentity{},
+		&schema.APIKey{},
+		&schema.BlogReaction{},
+		&schema.Bookmark{},
+	} {
+		if err = tx.Where("user_id IN ?", ids).Delete(model).Error; err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	if err = tx.Where("follower_id IN ? OR followee_id IN ?", ids, ids).Delete(&schema.Follow{}).Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	d := tx.Where("id IN ?", ids).Delete(&schema.User{})
+	if err = d.Error; err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	tx.Commit()
+	return int(d.RowsAffected), nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite/schema"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+// implement ports.IUserIdentityRepository
+type UserIdentityRepository struct {
+	db *sqlite.DB
+}
+
+func NewUserIdentityRepository(db *sqlite.DB) ports.IUserIdentityRepository {
+	return &UserIdentityRepository{
+		db: db,
+	}
+}
+
+func (ur *UserIdentityRepository) CreateIdentity(ctx context.Context, identity *domain.UserIdentity) (*domain.UserIdentity, error) {
+	createdIdentity := &schema.UserIdentity{
+		UserID:   identity.UserID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}
+
+	if err := ur.db.WithContext(ctx).Create(createdIdentity).Error; err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return &domain.UserIdentity{
+		ID:        createdIdentity.ID,
+		UserID:    createdIdentity.UserID,
+		Provider:  createdIdentity.Provider,
+		Subject:   createdIdentity.Subject,
+		Email:     createdIdentity.Email,
+		CreatedAt: createdIdentity.CreatedAt,
+	}, nil
+}
+
+func (ur *UserIdentityRepository) GetIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	identity := &schema.UserIdentity{}
+
+	err := ur.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(identity).Error
+	if err != nil {
+		return nil, domain.ErrDataNotFound
+	}
+
+	return &domain.UserIdentity{
+		ID:        identity.ID,
+		UserID:    identity.UserID,
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		Email:     identity.Email,
+		CreatedAt: identity.CreatedAt,
+	}, nil
+}
@@ -4,23 +4,187 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type User struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey;default:(gen_random_uuid())"`
+	Name          string    `gorm:"size:24;uniqueIndex;not null"`
+	Email         *string   `gorm:"size:254;uniqueIndex"`
+	EmailVerified bool      `gorm:"not null;default:false"`
+	Password      string    `gorm:"not null"`
+	TokenVersion  int       `gorm:"not null;default:0"`
+	TOTPSecret    string    `gorm:"column:totp_secret;size:64;not null;default:''"`
+	TOTPEnabled   bool      `gorm:"column:totp_enabled;not null;default:false"`
+	Role          string    `gorm:"size:16;not null;default:'user';index"`
+	Status        string    `gorm:"size:16;not null;default:'active';index"`
+	Blogs         []Blog    `gorm:"foreignKey:AuthorID"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt `gorm:"index"` // soft deleted rows are skipped by every query, the name and email stay reserved
+}
+
+type Blog struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;default:(gen_random_uuid())"`
+	Title       string    `gorm:"not null;index"`
+	Text        string    `gorm:"not null"`
+	AuthorID    uuid.UUID `gorm:"not null;index:idx_blog_author_created"` // the timelines read the latest blogs of authors
+	Author      User      `gorm:"foreignKey:AuthorID;constraint:OnDelete:CASCADE"`
+	Version     int       `gorm:"not null;default:1"`
+	Slug        *string   `gorm:"size:320;uniqueIndex"`   // NULL until a slug is assigned to a blog written before slugs existed
+	CustomSlug  bool      `gorm:"not null;default:false"` // set by the author, it does not follow the title
+	Summary     string    `gorm:"not null;default:''"`
+	Excerpt     *string   // NULL until the metadata of a blog written before it existed is computed
+	WordCount   int       `gorm:"not null;default:0"`
+	ReadingTime int       `gorm:"not null;default:0"`
+	CreatedAt   time.Time `gorm:"index:idx_blog_author_created"`
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+}
+
+// BlogTag is a tag of a blog, the feed of a tag lists the blogs that have it
+type BlogTag struct {
+	BlogID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Blog   Blog      `gorm:"foreignKey:BlogID;constraint:OnDelete:CASCADE"`
+	Tag    string    `gorm:"size:32;primaryKey;index"`
+}
+
+// BlogSlug is a slug a blog has used, old slugs stay reserved to redirect to the blog
+type BlogSlug struct {
+	Slug      string    `gorm:"size:320;primaryKey"`
+	BlogID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	Blog      Blog      `gorm:"foreignKey:BlogID;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time
+}
+
+type BlogRevision struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;default:(gen_random_uuid())"`
+	BlogID      uuid.UUID `gorm:"not null;uniqueIndex:idx_blog_revision_number"`
+	Blog        Blog      `gorm:"foreignKey:BlogID;constraint:OnDelete:CASCADE"`
+	Number      int       `gorm:"not null;uniqueIndex:idx_blog_revision_number"`
+	AuthorID    uuid.UUID `gorm:"type:uuid;not null"`
+	Title       string    `gorm:"not null"`
+	Text        string    `gorm:"not null"`
+	ContentHash string    `gorm:"size:64;not null"`
+	CreatedAt   time.Time
+}
+
+// BlogReaction is a reaction of a user to a blog, a user adds each kind once
+type BlogReaction struct {
+	BlogID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Blog      Blog      `gorm:"foreignKey:BlogID;constraint:OnDelete:CASCADE"`
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+	User      User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Kind      string    `gorm:"size:32;primaryKey"`
+	CreatedAt time.Time
+}
+
+// BlogReactionCount is the number of reactions of a kind to a blog, counted again from the reactions when they are reconciled
+type BlogReactionCount struct {
+	BlogID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Kind   string    `gorm:"size:32;primaryKey"`
+	Count  int       `gorm:"not null;default:0"`
+}
+
+// BlogViewDaily is the number of views of a blog on a day, rolled up from the views buffered in redis
+type BlogViewDaily struct {
+	BlogID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Day    string    `gorm:"size:10;primaryKey"` // UTC date, 2006-01-02
+	Views  int       `gorm:"not null;default:0"`
+}
+
+// BlogReferrerDaily is the number of views of a blog on a day from a referrer host
+type BlogReferrerDaily struct {
+	BlogID   uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Day      string    `gorm:"size:10;primaryKey"`
+	Referrer string    `gorm:"size:255;primaryKey"`
+	Views    int       `gorm:"not null;default:0"`
+}
+
+// Bookmark is a blog a user saved to read later
+type Bookmark struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	User      User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	BlogID    uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+	Blog      Blog      `gorm:"foreignKey:BlogID;constraint:OnDelete:CASCADE"`
+	Note      string    `gorm:"size:500;not null;default:''"`
+	CreatedAt time.Time `gorm:"index"`
+}
+
+// Follow is a user following an author
+type Follow struct {
+	FollowerID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Follower   User      `gorm:"foreignKey:FollowerID;constraint:OnDelete:CASCADE"`
+	FolloweeID uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+	Followee   User      `gorm:"foreignKey:FolloweeID;constraint:OnDelete:CASCADE"`
+	CreatedAt  time.Time `gorm:"index"`
+}
+
+type ActionToken struct {
 	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:(gen_random_uuid())"`
-	Name      string    `gorm:"size:24;uniqueIndex;not null"`
-	Password  string    `gorm:"not null"`
-	Blogs     []Blog    `gorm:"foreignKey:AuthorID"`
+	UserID    uuid.UUID `gorm:"not null;index"`
+	User      User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Purpose   string    `gorm:"size:32;not null;uniqueIndex:idx_action_token_purpose_hash"`
+	TokenHash string    `gorm:"size:64;not null;uniqueIndex:idx_action_token_purpose_hash"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
 	CreatedAt time.Time
-	UpdatedAt time.Time
 }
 
-type Blog struct {
+type RecoveryCode struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:(gen_random_uuid())"`
+	UserID    uuid.UUID `gorm:"not null;index"`
+	User      User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	CodeHash  string    `gorm:"size:64;not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+type Passkey struct {
+	ID              uuid.UUID `gorm:"type:uuid;primaryKey;default:(gen_random_uuid())"`
+	UserID          uuid.UUID `gorm:"not null;index"`
+	User            User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Name            string    `gorm:"size:64;not null"`
+	CredentialID    []byte    `gorm:"size:1023;not null;uniqueIndex"`
+	PublicKey       []byte    `gorm:"not null"`
+	AttestationType string    `gorm:"size:32;not null"`
+	Transports      string    `gorm:"not null;default:''"` // comma separated
+	AAGUID          []byte    `gorm:"column:aaguid"`
+	SignCount       uint32    `gorm:"not null;default:0"`
+	BackupEligible  bool      `gorm:"not null;default:false"`
+	BackupState     bool      `gorm:"not null;default:false"`
+	LastUsedAt      *time.Time
+	CreatedAt       time.Time
+}
+
+type UserIdentity struct {
 	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:(gen_random_uuid())"`
-	Title     string    `gorm:"not null;index"`
-	Text      string    `gorm:"not null"`
-	AuthorID  uuid.UUID `gorm:"not null"`
-	Author    User      `gorm:"foreignKey:AuthorID;constraint:OnDelete:CASCADE"`
+	UserID    uuid.UUID `gorm:"not null;index"`
+	User      User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Provider  string    `gorm:"size:32;not null;uniqueIndex:idx_user_identity_provider_subject"`
+	Subject   string    `gorm:"size:255;not null;uniqueIndex:idx_user_identity_provider_subject"`
+	Email     string    `gorm:"size:254;not null;default:''"`
 	CreatedAt time.Time
-	UpdatedAt time.Time
+}
+
+type APIKey struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey;default:(gen_random_uuid())"`
+	UserID     uuid.UUID `gorm:"not null;index"`
+	User       User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Name       string    `gorm:"size:64;not null"`
+	Prefix     string    `gorm:"size:16;not null"`
+	KeyHash    string    `gorm:"size:64;not null;uniqueIndex"`
+	Scopes     string    `gorm:"not null;default:''"` // comma separated
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+type AuditLog struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey;default:(gen_random_uuid())"`
+	ActorID   *uuid.UUID `gorm:"type:uuid;index"`
+	Action    string     `gorm:"size:64;not null"`
+	TargetID  uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Reason    string     `gorm:"size:255;not null;default:''"`
+	CreatedAt time.Time  `gorm:"index"`
 }
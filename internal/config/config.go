@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
@@ -11,12 +12,22 @@ import (
 
 type (
 	Config struct {
-		App    *App
-		Logger *Logger
-		DB     *DB
-		Auth   *Auth
-		Http   *Http
-		Redis  *Redis
+		App       *App
+		Logger    *Logger
+		DB        *DB
+		Auth      *Auth
+		Http      *Http
+		Redis     *Redis
+		Password  *Password
+		Mail      *Mail
+		WebAuthn  *WebAuthn
+		OIDC      *OIDC
+		Trash     *Trash
+		Site      *Site
+		Feed      *Feed
+		Sitemap   *Sitemap
+		Reaction  *Reaction
+		Analytics *Analytics
 	}
 
 	App struct {
@@ -42,8 +53,24 @@ type (
 	}
 
 	Auth struct {
-		SecretKey string
-		Duration  string
+		SecretKey             string
+		Duration              string
+		PasswordResetDuration string
+		PasswordResetURL      string
+		VerificationDuration  string
+		VerificationURL       string
+		RequireVerifiedEmail  bool
+		MFADuration           string
+		MFAAttemptLimit       int    // wrong codes a two-factor challenge accepts before it is rejected
+		MFAUserAttemptLimit   int    // wrong codes a user can send per window over all its challenges
+		MFAAttemptWindow      string // window of the wrong codes of a user
+		TOTPIssuer            string
+		MagicLinkDuration     string
+		MagicLinkURL          string
+		MagicLinkLimit        int
+		MagicLinkWindow       string
+		ImpersonationDuration string
+		AdminUsernames        []string // users made admin on startup
 	}
 
 	Http struct {
@@ -52,12 +79,97 @@ type (
 		URL            string
 		Port           int
 		Logger         Logger
+		SessionCookie  bool // set the access token in an HttpOnly cookie on login
+		CookieDomain   string
+		CookieSecure   bool
+		CookieSameSite string   // lax | strict | none
+		RequireIfMatch bool     // blog updates and deletes must send the ETag they apply to
+		TrustedProxies []string // addresses or CIDRs of the proxies whose X-Forwarded-For is used as client IP, none by default
 	}
 
 	Redis struct {
 		Addr     string
 		Password string
 	}
+
+	Password struct {
+		MinLength        int
+		MaxLength        int
+		RequireUpper     bool
+		RequireLower     bool
+		RequireDigit     bool
+		RequireSymbol    bool
+		DisallowUsername bool
+		BreachedFile     string
+	}
+
+	Mail struct {
+		Driver       string
+		From         string
+		SMTPHost     string
+		SMTPPort     int
+		SMTPUsername string
+		SMTPPassword string
+		LogFile      string
+	}
+
+	WebAuthn struct {
+		RPID          string
+		RPDisplayName string
+		RPOrigins     []string
+		Timeout       string
+	}
+
+	OIDC struct {
+		StateDuration string
+		Providers     []OIDCProvider
+	}
+
+	Trash struct {
+		Retention     string // time deleted users and blogs can be restored
+		PurgeInterval string
+	}
+
+	Site struct {
+		URL       string // home page of the blog
+		BlogURL   string // page of a blog, followed by its slug
+		AuthorURL string // page of an author, followed by the author id
+	}
+
+	Feed struct {
+		Title         string
+		Description   string
+		Size          int // number of blogs in a feed
+		CacheDuration string
+	}
+
+	Sitemap struct {
+		URL            string   // public URL of the api root the sitemaps are served from
+		CacheDuration  string   // sitemaps are generated again on blog writes or once expired
+		RobotsDisallow []string // paths robots.txt disallows
+	}
+
+	Reaction struct {
+		Kinds             []string // kinds of reactions users can add, in the order of the counts
+		CounterDuration   string   // reaction counters not written for this long are read again from sqlite
+		ReconcileInterval string   // the counters are counted again from the reactions and saved to sqlite
+	}
+
+	Analytics struct {
+		ViewWindow     string   // a visitor is counted once per blog in this window
+		BotUserAgents  []string // views of user agents containing one of these are not counted
+		FlushInterval  string   // the views buffered in redis are saved to sqlite at this interval
+		FlushBatchSize int      // number of rows inserted by statement when the views are saved
+	}
+
+	OIDCProvider struct {
+		Name         string
+		Issuer       string
+		ClientID     string
+		ClientSecret string
+		RedirectURL  string
+		Scopes       []string
+	}
 )
 
 func New() (*Config, error) {
@@ -77,7 +189,10 @@ func New() (*Config, error) {
 
 	db := GetDBConf()
 
-	auth := GetAuthConf()
+	auth, err := GetAuthConf()
+	if err != nil {
+		return nil, err
+	}
 
 	http, err := GetHTTPConf()
 	if err != nil {
@@ -86,13 +201,55 @@ func New() (*Config, error) {
 
 	redis := GetRedisConf()
 
+	password, err := GetPasswordConf()
+	if err != nil {
+		return nil, err
+	}
+
+	mail, err := GetMailConf()
+	if err != nil {
+		return nil, err
+	}
+
+	webAuthn := GetWebAuthnConf()
+
+	oidc := GetOIDCConf()
+
+	trash := GetTrashConf()
+
+	site := GetSiteConf()
+
+	feed, err := GetFeedConf()
+	if err != nil {
+		return nil, err
+	}
+
+	sitemap := GetSitemapConf()
+
+	reaction := GetReactionConf()
+
+	analytics, err := GetAnalyticsConf()
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
-		App:    app,
-		Logger: logger,
-		DB:     db,
-		Auth:   auth,
-		Http:   http,
-		Redis:  redis,
+		App:       app,
+		Logger:    logger,
+		DB:        db,
+		Auth:      auth,
+		Http:      http,
+		Redis:     redis,
+		Password:  password,
+		Mail:      mail,
+		WebAuthn:  webAuthn,
+		OIDC:      oidc,
+		Trash:     trash,
+		Site:      site,
+		Feed:      feed,
+		Sitemap:   sitemap,
+		Reaction:  reaction,
+		Analytics: analytics,
 	}, nil
 }
 
@@ -143,11 +300,49 @@ func GetDBConf() *DB {
 	}
 }
 
-func GetAuthConf() *Auth {
-	return &Auth{
-		SecretKey: os.Getenv("AUTH_SECRET"),
-		Duration:  os.Getenv("AUTH_TOKEN_DURATION"),
+func GetAuthConf() (*Auth, error) {
+	magicLinkLimit, err := strconv.Atoi(os.Getenv("AUTH_MAGIC_LINK_LIMIT"))
+	if err != nil {
+		return nil, fmt.Errorf("AUTH_MAGIC_LINK_LIMIT must to be a number: %v", err)
 	}
+
+	mfaAttemptLimit, err := strconv.Atoi(os.Getenv("AUTH_MFA_ATTEMPT_LIMIT"))
+	if err != nil {
+		return nil, fmt.Errorf("AUTH_MFA_ATTEMPT_LIMIT must to be a number: %v", err)
+	}
+
+	mfaUserAttemptLimit, err := strconv.Atoi(os.Getenv("AUTH_MFA_USER_ATTEMPT_LIMIT"))
+	if err != nil {
+		return nil, fmt.Errorf("AUTH_MFA_USER_ATTEMPT_LIMIT must to be a number: %v", err)
+	}
+
+	adminUsernames := []string{}
+	for _, name := range strings.Split(os.Getenv("AUTH_ADMIN_USERNAMES"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			adminUsernames = append(adminUsernames, name)
+		}
+	}
+
+	return &Auth{
+		SecretKey:             os.Getenv("AUTH_SECRET"),
+		Duration:              os.Getenv("AUTH_TOKEN_DURATION"),
+		PasswordResetDuration: os.Getenv("AUTH_PASSWORD_RESET_DURATION"),
+		PasswordResetURL:      os.Getenv("AUTH_PASSWORD_RESET_URL"),
+		VerificationDuration:  os.Getenv("AUTH_EMAIL_VERIFICATION_DURATION"),
+		VerificationURL:       os.Getenv("AUTH_EMAIL_VERIFICATION_URL"),
+		RequireVerifiedEmail:  os.Getenv("AUTH_REQUIRE_VERIFIED_EMAIL") == "true",
+		MFADuration:           os.Getenv("AUTH_MFA_TOKEN_DURATION"),
+		MFAAttemptLimit:       mfaAttemptLimit,
+		MFAUserAttemptLimit:   mfaUserAttemptLimit,
+		MFAAttemptWindow:      os.Getenv("AUTH_MFA_ATTEMPT_WINDOW"),
+		TOTPIssuer:            os.Getenv("AUTH_TOTP_ISSUER"),
+		MagicLinkDuration:     os.Getenv("AUTH_MAGIC_LINK_DURATION"),
+		MagicLinkURL:          os.Getenv("AUTH_MAGIC_LINK_URL"),
+		MagicLinkLimit:        magicLinkLimit,
+		MagicLinkWindow:       os.Getenv("AUTH_MAGIC_LINK_WINDOW"),
+		ImpersonationDuration: os.Getenv("AUTH_IMPERSONATION_DURATION"),
+		AdminUsernames:        adminUsernames,
+	}, nil
 }
 
 func GetHTTPConf() (*Http, error) {
@@ -185,12 +380,38 @@ func GetHTTPConf() (*Http, error) {
 		}
 	}
 
+	cookieSameSite := strings.ToLower(os.Getenv("HTTP_COOKIE_SAME_SITE"))
+	if cookieSameSite == "" {
+		cookieSameSite = "lax"
+	}
+	if cookieSameSite != "lax" && cookieSameSite != "strict" && cookieSameSite != "none" {
+		return nil, fmt.Errorf("HTTP_COOKIE_SAME_SITE must to be lax, strict or none: %v", cookieSameSite)
+	}
+
+	trustedProxies := []string{}
+	for _, proxy := range strings.Split(os.Getenv("HTTP_TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+
+	cookieSecure := os.Getenv("HTTP_COOKIE_SECURE") == "true"
+	if cookieSameSite == "none" && !cookieSecure {
+		return nil, errors.New("HTTP_COOKIE_SECURE must to be true when HTTP_COOKIE_SAME_SITE is none")
+	}
+
 	return &Http{
 		Env:            os.Getenv("APP_ENV"),
 		AllowedOrigins: allowedOrigins,
 		URL:            os.Getenv("HTTP_URL"),
 		Port:           port,
 		Logger:         logger,
+		SessionCookie:  os.Getenv("HTTP_SESSION_COOKIE") == "true",
+		CookieDomain:   os.Getenv("HTTP_COOKIE_DOMAIN"),
+		CookieSecure:   cookieSecure,
+		CookieSameSite: cookieSameSite,
+		RequireIfMatch: os.Getenv("HTTP_REQUIRE_IF_MATCH") == "true",
+		TrustedProxies: trustedProxies,
 	}, nil
 }
 
@@ -200,3 +421,169 @@ func GetRedisConf() *Redis {
 		Password: os.Getenv("REDIS_PASS"),
 	}
 }
+
+func GetPasswordConf() (*Password, error) {
+	minLength, err := strconv.Atoi(os.Getenv("PASSWORD_MIN_LENGTH"))
+	if err != nil {
+		return nil, fmt.Errorf("PASSWORD_MIN_LENGTH must to be a number: %v", err)
+	}
+	maxLength, err := strconv.Atoi(os.Getenv("PASSWORD_MAX_LENGTH"))
+	if err != nil {
+		return nil, fmt.Errorf("PASSWORD_MAX_LENGTH must to be a number: %v", err)
+	}
+
+	return &Password{
+		MinLength:        minLength,
+		MaxLength:        maxLength,
+		RequireUpper:     os.Getenv("PASSWORD_REQUIRE_UPPER") == "true",
+		RequireLower:     os.Getenv("PASSWORD_REQUIRE_LOWER") == "true",
+		RequireDigit:     os.Getenv("PASSWORD_REQUIRE_DIGIT") == "true",
+		RequireSymbol:    os.Getenv("PASSWORD_REQUIRE_SYMBOL") == "true",
+		DisallowUsername: os.Getenv("PASSWORD_DISALLOW_USERNAME") == "true",
+		BreachedFile:     os.Getenv("PASSWORD_BREACHED_FILE"),
+	}, nil
+}
+
+func GetMailConf() (*Mail, error) {
+	mail := &Mail{
+		Driver:  os.Getenv("MAIL_DRIVER"),
+		From:    os.Getenv("MAIL_FROM"),
+		LogFile: os.Getenv("MAIL_LOG_FILE"),
+	}
+
+	if mail.Driver == "smtp" {
+		port, err := strconv.Atoi(os.Getenv("MAIL_SMTP_PORT"))
+		if err != nil {
+			return nil, fmt.Errorf("MAIL_SMTP_PORT must to be a number: %v", err)
+		}
+
+		mail.SMTPHost = os.Getenv("MAIL_SMTP_HOST")
+		mail.SMTPPort = port
+		mail.SMTPUsername = os.Getenv("MAIL_SMTP_USERNAME")
+		mail.SMTPPassword = os.Getenv("MAIL_SMTP_PASSWORD")
+	}
+
+	return mail, nil
+}
+
+func GetWebAuthnConf() *WebAuthn {
+	return &WebAuthn{
+		RPID:          os.Getenv("WEBAUTHN_RP_ID"),
+		RPDisplayName: os.Getenv("WEBAUTHN_RP_NAME"),
+		RPOrigins:     strings.Split(os.Getenv("WEBAUTHN_RP_ORIGINS"), ","),
+		Timeout:       os.Getenv("WEBAUTHN_TIMEOUT"),
+	}
+}
+
+// GetOIDCConf read the providers listed in OIDC_PROVIDERS,
+// each one is configured by the OIDC_<NAME>_* variables
+func GetOIDCConf() *OIDC {
+	oidc := &OIDC{
+		StateDuration: os.Getenv("OIDC_STATE_DURATION"),
+	}
+
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+
+		scopes := strings.Fields(os.Getenv(prefix + "SCOPES"))
+		if len(scopes) == 0 {
+			scopes = []string{"openid", "email", "profile"}
+		}
+
+		oidc.Providers = append(oidc.Providers, OIDCProvider{
+			Name:         strings.ToLower(name),
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       scopes,
+		})
+	}
+
+	return oidc
+}
+
+func GetTrashConf() *Trash {
+	return &Trash{
+		Retention:     os.Getenv("TRASH_RETENTION"),
+		PurgeInterval: os.Getenv("TRASH_PURGE_INTERVAL"),
+	}
+}
+
+func GetSiteConf() *Site {
+	return &Site{
+		URL:       os.Getenv("SITE_URL"),
+		BlogURL:   os.Getenv("SITE_BLOG_URL"),
+		AuthorURL: os.Getenv("SITE_AUTHOR_URL"),
+	}
+}
+
+func GetFeedConf() (*Feed, error) {
+	size, err := strconv.Atoi(os.Getenv("FEED_SIZE"))
+	if err != nil {
+		return nil, fmt.Errorf("FEED_SIZE must to be a number: %v", err)
+	}
+
+	return &Feed{
+		Title:         os.Getenv("FEED_TITLE"),
+		Description:   os.Getenv("FEED_DESCRIPTION"),
+		Size:          size,
+		CacheDuration: os.Getenv("FEED_CACHE_DURATION"),
+	}, nil
+}
+
+func GetSitemapConf() *Sitemap {
+	disallow := []string{}
+	for _, path := range strings.Split(os.Getenv("SITEMAP_ROBOTS_DISALLOW"), ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			disallow = append(disallow, path)
+		}
+	}
+
+	return &Sitemap{
+		URL:            strings.TrimSuffix(os.Getenv("SITEMAP_URL"), "/"),
+		CacheDuration:  os.Getenv("SITEMAP_CACHE_DURATION"),
+		RobotsDisallow: disallow,
+	}
+}
+
+func GetReactionConf() *Reaction {
+	kinds := []string{}
+	for _, kind := range strings.Split(os.Getenv("REACTION_KINDS"), ",") {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			kinds = append(kinds, kind)
+		}
+	}
+
+	return &Reaction{
+		Kinds:             kinds,
+		CounterDuration:   os.Getenv("REACTION_COUNTER_DURATION"),
+		ReconcileInterval: os.Getenv("REACTION_RECONCILE_INTERVAL"),
+	}
+}
+
+func GetAnalyticsConf() (*Analytics, error) {
+	batchSize, err := strconv.Atoi(os.Getenv("ANALYTICS_FLUSH_BATCH_SIZE"))
+	if err != nil {
+		return nil, fmt.Errorf("ANALYTICS_FLUSH_BATCH_SIZE must to be a number: %v", err)
+	}
+
+	bots := []string{}
+	for _, bot := range strings.Split(os.Getenv("ANALYTICS_BOT_USER_AGENTS"), ",") {
+		if bot = strings.ToLower(strings.TrimSpace(bot)); bot != "" {
+			bots = append(bots, bot)
+		}
+	}
+
+	return &Analytics{
+		ViewWindow:     os.Getenv("ANALYTICS_VIEW_WINDOW"),
+		BotUserAgents:  bots,
+		FlushInterval:  os.Getenv("ANALYTICS_FLUSH_INTERVAL"),
+		FlushBatchSize: batchSize,
+	}, nil
+}
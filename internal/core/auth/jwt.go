@@ -13,16 +13,27 @@ import (
 
 var jwtMethod *jwt.SigningMethodHMAC = jwt.SigningMethodHS256
 
+// mfaPurpose is the purpose claim of the two-factor authentication challenge token,
+// access tokens have no purpose
+const mfaPurpose = "mfa"
+
 type CustomClaims struct {
-	ID   uuid.UUID `json:"id"`
-	Name string    `json:"name"`
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	Version  int       `json:"ver"`
+	Verified bool      `json:"verified"`
+	Purpose  string    `json:"purpose,omitempty"`
+	// Impersonator is the admin acting as the user
+	Impersonator *uuid.UUID `json:"imp,omitempty"`
 	jwt.RegisteredClaims
 }
 
 type JWTService struct {
-	key      []byte
-	keyFunc  func(token *jwt.Token) (interface{}, error)
-	duration time.Duration
+	key                   []byte
+	keyFunc               func(token *jwt.Token) (interface{}, error)
+	duration              time.Duration
+	mfaDuration           time.Duration
+	impersonationDuration time.Duration
 }
 
 func NewJWTTokenService(conf config.Auth) (ports.ITokenService, error) {
@@ -31,6 +42,16 @@ func NewJWTTokenService(conf config.Auth) (ports.ITokenService, error) {
 		return nil, err
 	}
 
+	mfaDuration, err := time.ParseDuration(conf.MFADuration)
+	if err != nil {
+		return nil, err
+	}
+
+	impersonationDuration, err := time.ParseDuration(conf.ImpersonationDuration)
+	if err != nil {
+		return nil, err
+	}
+
 	keyFunc := func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, domain.ErrInvalidToken
@@ -40,9 +61,11 @@ func NewJWTTokenService(conf config.Auth) (ports.ITokenService, error) {
 	}
 
 	return &JWTService{
-		key:      []byte(conf.SecretKey),
-		keyFunc:  keyFunc,
-		duration: duration,
+		key:                   []byte(conf.SecretKey),
+		keyFunc:               keyFunc,
+		duration:              duration,
+		mfaDuration:           mfaDuration,
+		impersonationDuration: impersonationDuration,
 	}, nil
 }
 
@@ -50,6 +73,10 @@ func (j *JWTService) CreateToken(user *domain.User) (string, error) {
 	claims := jwt.NewWithClaims(jwtMethod, CustomClaims{
 		user.ID,
 		user.Name,
+		user.TokenVersion,
+		user.EmailVerified,
+		"",
+		nil,
 		jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.duration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -72,10 +99,15 @@ func (j *JWTService) VerifyToken(tokenString string) (*domain.TokenPayload, erro
 	token, err := jwt.ParseWithClaims(tokenString, claims, j.keyFunc)
 
 	switch {
+	case token.Valid && claims.Purpose != "":
+		return nil, domain.ErrInvalidToken
 	case token.Valid:
 		return &domain.TokenPayload{
-			ID:   claims.ID,
-			Name: claims.Name,
+			ID:             claims.ID,
+			Name:           claims.Name,
+			Version:        claims.Version,
+			Verified:       claims.Verified,
+			ImpersonatorID: claims.Impersonator,
 		}, nil
 	case errors.Is(err, jwt.ErrTokenMalformed) || errors.Is(err, jwt.ErrTokenSignatureInvalid):
 		return nil, domain.ErrInvalidToken
@@ -85,3 +117,69 @@ func (j *JWTService) VerifyToken(tokenString string) (*domain.TokenPayload, erro
 		return nil, err
 	}
 }
+
+func (j *JWTService) CreateImpersonationToken(user *domain.User, impersonatorID uuid.UUID) (string, error) {
+	claims := jwt.NewWithClaims(jwtMethod, CustomClaims{
+		ID:           user.ID,
+		Name:         user.Name,
+		Version:      user.TokenVersion,
+		Verified:     user.EmailVerified,
+		Impersonator: &impersonatorID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.impersonationDuration)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Issuer:    "blog-api",
+		},
+	})
+
+	str, err := claims.SignedString(j.key)
+	if err != nil {
+		return "", domain.ErrTokenCreation
+	}
+
+	return str, nil
+}
+
+func (j *JWTService) CreateMFAToken(user *domain.User) (string, error) {
+	claims := jwt.NewWithClaims(jwtMethod, CustomClaims{
+		ID:      user.ID,
+		Version: user.TokenVersion,
+		Purpose: mfaPurpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.mfaDuration)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Issuer:    "blog-api",
+		},
+	})
+
+	str, err := claims.SignedString(j.key)
+	if err != nil {
+		return "", domain.ErrTokenCreation
+	}
+
+	return str, nil
+}
+
+func (j *JWTService) VerifyMFAToken(tokenString string) (*domain.TokenPayload, error) {
+	claims := &CustomClaims{}
+
+	token, err := jwt.ParseWithClaims(tokenString, claims, j.keyFunc)
+
+	switch {
+	case token.Valid && claims.Purpose == mfaPurpose && claims.RegisteredClaims.ID != "":
+		return &domain.TokenPayload{
+			ID:          claims.ID,
+			Version:     claims.Version,
+			ChallengeID: claims.RegisteredClaims.ID,
+		}, nil
+	case token.Valid:
+		return nil, domain.ErrInvalidToken
+	case errors.Is(err, jwt.ErrTokenExpired) || errors.Is(err, jwt.ErrTokenNotValidYet):
+		return nil, domain.ErrExpiredToken
+	default:
+		return nil, domain.ErrInvalidToken
+	}
+}
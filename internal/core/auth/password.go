@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+// bcryptMaxLength is the max password length in bytes that bcrypt hashes,
+// everything after it is silently ignored
+const bcryptMaxLength = 72
+
+// hashPrefixLength is the length of the SHA-1 hash prefix sent to the breached password repository
+const hashPrefixLength = 5
+
+const passwordField = "password"
+
+// implement ports.IPasswordPolicy
+type PasswordPolicy struct {
+	conf     config.Password
+	breached ports.IBreachedPasswordRepository // nil to disable breached password check
+}
+
+func NewPasswordPolicy(conf config.Password, breached ports.IBreachedPasswordRepository) ports.IPasswordPolicy {
+	if conf.MaxLength <= 0 || conf.MaxLength > bcryptMaxLength {
+		conf.MaxLength = bcryptMaxLength
+	}
+	if conf.MinLength > conf.MaxLength {
+		conf.MinLength = conf.MaxLength
+	}
+
+	return &PasswordPolicy{
+		conf:     conf,
+		breached: breached,
+	}
+}
+
+func (pp *PasswordPolicy) Validate(ctx context.Context, username, password string) error {
+	var fields []domain.FieldError
+
+	if len([]rune(password)) < pp.conf.MinLength {
+		fields = append(fields, newPasswordFieldError("min_length",
+			fmt.Sprintf("password must be at least %v characters long", pp.conf.MinLength)))
+	}
+	if len(password) > pp.conf.MaxLength {
+		fields = append(fields, newPasswordFieldError("max_length",
+			fmt.Sprintf("password must be at most %v bytes long", pp.conf.MaxLength)))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	if pp.conf.RequireUpper && !hasUpper {
+		fields = append(fields, newPasswordFieldError("upper", "password must contain an uppercase letter"))
+	}
+	if pp.conf.RequireLower && !hasLower {
+		fields = append(fields, newPasswordFieldError("lower", "password must contain a lowercase letter"))
+	}
+	if pp.conf.RequireDigit && !hasDigit {
+		fields = append(fields, newPasswordFieldError("digit", "password must contain a digit"))
+	}
+	if pp.conf.RequireSymbol && !hasSymbol {
+		fields = append(fields, newPasswordFieldError("symbol", "password must contain a symbol"))
+	}
+
+	if pp.conf.DisallowUsername && isSimilarToUsername(username, password) {
+		fields = append(fields, newPasswordFieldError("username", "password must not be similar to the username"))
+	}
+
+	// only spend a lookup on passwords that pass the local rules
+	if len(fields) == 0 && pp.breached != nil {
+		count, err := pp.breachedCount(ctx, password)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			fields = append(fields, newPasswordFieldError("breached",
+				"password has appeared in a data breach, please choose another one"))
+		}
+	}
+
+	if len(fields) != 0 {
+		return domain.NewValidationError(domain.ErrWeakPassword, fields...)
+	}
+	return nil
+}
+
+// breachedCount return how many times password appears in the breached repository,
+// only the first 5 characters of the SHA-1 hash leave this function (k-anonymity)
+func (pp *PasswordPolicy) breachedCount(ctx context.Context, password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	suffixes, err := pp.breached.GetSuffixesByPrefix(ctx, hash[:hashPrefixLength])
+	if err != nil {
+		return 0, err
+	}
+
+	return suffixes[hash[hashPrefixLength:]], nil
+}
+
+// isSimilarToUsername check if password contains username, is contained by it
+// or is the username reversed, case insensitive
+func isSimilarToUsername(username, password string) bool {
+	username = strings.ToLower(strings.TrimSpace(username))
+	password = strings.ToLower(password)
+	if username == "" || password == "" {
+		return false
+	}
+
+	return strings.Contains(password, username) ||
+		strings.Contains(username, password) ||
+		strings.Contains(password, reverse(username))
+}
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+func newPasswordFieldError(rule, message string) domain.FieldError {
+	return domain.FieldError{
+		Field:   passwordField,
+		Rule:    rule,
+		Message: message,
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 var (
 	blogPrefix            = "blog"
+	blogSlugPrefix        = "blogSlug"
 	listBlogsPrefix       = "blogs"
 	searchListBlogsPrefix = "searchBlogs"
 )
@@ -41,6 +42,12 @@ func (bcs *blogCache) SetBlog(ctx context.Context, blog *domain.Blog) error {
 	return bcs.cache.Set(ctx, generateCacheKeyParams(blogPrefix, blog.ID), bytes, bcs.blogDuration)
 }
 
+// SetBlogSlug cache the id of the blog a slug belongs to, it stays valid after a rename
+// since old slugs keep pointing to their blog
+func (bcs *blogCache) SetBlogSlug(ctx context.Context, slug string, id uuid.UUID) error {
+	return bcs.cache.Set(ctx, generateCacheKeyParams(blogSlugPrefix, slug), []byte(id.String()), bcs.blogDuration)
+}
+
 func (bcs *blogCache) SetList(ctx context.Context, skip int, limit int, list []domain.Blog) error {
 	bytes, err := marshal(list)
 	if err != nil {
@@ -73,6 +80,15 @@ func (bcs *blogCache) GetBlog(ctx context.Context, id uuid.UUID) (*domain.Blog,
 	return blog, nil
 }
 
+func (bcs *blogCache) GetBlogIDBySlug(ctx context.Context, slug string) (uuid.UUID, error) {
+	bytes, err := bcs.cache.Get(ctx, generateCacheKeyParams(blogSlugPrefix, slug))
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return uuid.ParseBytes(bytes)
+}
+
 func (bcs *blogCache) GetList(ctx context.Context, skip int, limit int) ([]domain.Blog, error) {
 	bytes, err := bcs.cache.Get(ctx, generateCacheKeyParams(listBlogsPrefix, skip, limit))
 	if err != nil {
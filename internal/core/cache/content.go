@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+var renderedPrefix = "rendered"
+
+type contentCache struct {
+	cache    ports.ICacheRepository
+	duration time.Duration
+}
+
+func NewContentCache(cache ports.ICacheRepository, duration time.Duration) ports.IContentCache {
+	return &contentCache{
+		cache:    cache,
+		duration: duration,
+	}
+}
+
+func (ccs *contentCache) SetRendered(ctx context.Context, hash string, content *domain.RenderedContent) error {
+	bytes, err := marshal(content)
+	if err != nil {
+		return err
+	}
+
+	return ccs.cache.Set(ctx, generateCacheKeyParams(renderedPrefix, hash), bytes, ccs.duration)
+}
+
+func (ccs *contentCache) GetRendered(ctx context.Context, hash string) (*domain.RenderedContent, error) {
+	bytes, err := ccs.cache.Get(ctx, generateCacheKeyParams(renderedPrefix, hash))
+	if err != nil {
+		return nil, err
+	}
+
+	content := &domain.RenderedContent{}
+	err = unmarshal(bytes, content)
+	if err != nil {
+		return nil, err
+	}
+	return content, nil
+}
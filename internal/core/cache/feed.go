@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+var feedPrefix = "feed"
+
+type feedCache struct {
+	cache    ports.ICacheRepository
+	duration time.Duration
+}
+
+// NewFeedCache create the cache of the feeds, the feed of the site is cached with the nil author id
+// and no tag, the blog writes delete them
+func NewFeedCache(cache ports.ICacheRepository, duration time.Duration) ports.IFeedCache {
+	return &feedCache{
+		cache:    cache,
+		duration: duration,
+	}
+}
+
+func (fcs *feedCache) SetFeed(ctx context.Context, authorID uuid.UUID, tag string, feed *domain.Feed) error {
+	bytes, err := marshal(feed)
+	if err != nil {
+		return err
+	}
+
+	return fcs.cache.Set(ctx, generateCacheKeyParams(feedPrefix, feed.Format, authorID, tag), bytes, fcs.duration)
+}
+
+func (fcs *feedCache) GetFeed(ctx context.Context, format domain.FeedFormat, authorID uuid.UUID, tag string) (*domain.Feed, error) {
+	bytes, err := fcs.cache.Get(ctx, generateCacheKeyParams(feedPrefix, format, authorID, tag))
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &domain.Feed{}
+	err = unmarshal(bytes, feed)
+	if err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+func (fcs *feedCache) DeleteAllFeeds(ctx context.Context) error {
+	return fcs.cache.DeleteByPrefix(ctx, fmt.Sprintf("%v-*", feedPrefix))
+}
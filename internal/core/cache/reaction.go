@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+var reactionPrefix = "reaction"
+
+type reactionCache struct {
+	cache    ports.ICacheRepository
+	duration time.Duration
+}
+
+// NewReactionCache create the counters of the reactions, a counter per kind of reaction to a blog
+func NewReactionCache(cache ports.ICacheRepository, duration time.Duration) ports.IReactionCache {
+	return &reactionCache{
+		cache:    cache,
+		duration: duration,
+	}
+}
+
+func (rcs *reactionCache) GetCounts(ctx context.Context, blogID uuid.UUID, kinds []string) (domain.ReactionCounts, error) {
+	counts := domain.ReactionCounts{}
+	for _, kind := range kinds {
+		bytes, err := rcs.cache.Get(ctx, generateCacheKeyParams(reactionPrefix, blogID, kind))
+		if err != nil {
+			return nil, err
+		}
+
+		count, err := strconv.Atoi(string(bytes))
+		if err != nil {
+			return nil, err
+		}
+		counts[kind] = count
+	}
+	return counts, nil
+}
+
+func (rcs *reactionCache) InitCounts(ctx context.Context, blogID uuid.UUID, counts domain.ReactionCounts) error {
+	for kind, count := range counts {
+		_, err := rcs.cache.SetNX(ctx, generateCacheKeyParams(reactionPrefix, blogID, kind), []byte(strconv.Itoa(count)), rcs.duration)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rcs *reactionCache) AddCount(ctx context.Context, blogID uuid.UUID, kind string, value int) (int, error) {
+	count, err := rcs.cache.IncrBy(ctx, generateCacheKeyParams(reactionPrefix, blogID, kind), int64(value), rcs.duration)
+	return int(count), err
+}
+
+func (rcs *reactionCache) SetCounts(ctx context.Context, blogID uuid.UUID, counts domain.ReactionCounts) error {
+	for kind, count := range counts {
+		err := rcs.cache.Set(ctx, generateCacheKeyParams(reactionPrefix, blogID, kind), []byte(strconv.Itoa(count)), rcs.duration)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
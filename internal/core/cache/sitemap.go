@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+var sitemapPrefix = "sitemap"
+
+type sitemapCache struct {
+	cache    ports.ICacheRepository
+	duration time.Duration
+}
+
+// NewSitemapCache create the cache of the sitemaps, the blog writes delete them
+func NewSitemapCache(cache ports.ICacheRepository, duration time.Duration) ports.ISitemapCache {
+	return &sitemapCache{
+		cache:    cache,
+		duration: duration,
+	}
+}
+
+func (scs *sitemapCache) SetSitemap(ctx context.Context, sitemap *domain.Sitemap) error {
+	bytes, err := marshal(sitemap)
+	if err != nil {
+		return err
+	}
+
+	return scs.cache.Set(ctx, generateCacheKeyParams(sitemapPrefix, sitemap.Page), bytes, scs.duration)
+}
+
+func (scs *sitemapCache) GetSitemap(ctx context.Context, page int) (*domain.Sitemap, error) {
+	bytes, err := scs.cache.Get(ctx, generateCacheKeyParams(sitemapPrefix, page))
+	if err != nil {
+		return nil, err
+	}
+
+	sitemap := &domain.Sitemap{}
+	err = unmarshal(bytes, sitemap)
+	if err != nil {
+		return nil, err
+	}
+	return sitemap, nil
+}
+
+func (scs *sitemapCache) DeleteAllSitemaps(ctx context.Context) error {
+	return scs.cache.DeleteByPrefix(ctx, fmt.Sprintf("%v-*", sitemapPrefix))
+}
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+var timelinePrefix = "timeline"
+
+type timelineCache struct {
+	cache    ports.ICacheRepository
+	duration time.Duration
+}
+
+// NewTimelineCache create the cache of the timeline pages of the users, a page is kept for the duration
+// so the new blogs of the followed authors show after it at the latest
+func NewTimelineCache(cache ports.ICacheRepository, duration time.Duration) ports.ITimelineCache {
+	return &timelineCache{
+		cache:    cache,
+		duration: duration,
+	}
+}
+
+// timelineKey is the key of a page of the timeline of a user, the first page has no cursor
+func timelineKey(userID uuid.UUID, cursor *domain.TimelineCursor, limit int) string {
+	if cursor == nil {
+		return generateCacheKeyParams(timelinePrefix, userID, "first", limit)
+	}
+	return generateCacheKeyParams(timelinePrefix, userID, cursor.CreatedAt.UnixNano(), cursor.ID, limit)
+}
+
+func (tcs *timelineCache) SetTimeline(ctx context.Context, userID uuid.UUID, cursor *domain.TimelineCursor, limit int, timeline *domain.Timeline) error {
+	bytes, err := marshal(timeline)
+	if err != nil {
+		return err
+	}
+
+	return tcs.cache.Set(ctx, timelineKey(userID, cursor, limit), bytes, tcs.duration)
+}
+
+func (tcs *timelineCache) GetTimeline(ctx context.Context, userID uuid.UUID, cursor *domain.TimelineCursor, limit int) (*domain.Timeline, error) {
+	bytes, err := tcs.cache.Get(ctx, timelineKey(userID, cursor, limit))
+	if err != nil {
+		return nil, err
+	}
+
+	timeline := &domain.Timeline{}
+	err = unmarshal(bytes, timeline)
+	if err != nil {
+		return nil, err
+	}
+	return timeline, nil
+}
+
+func (tcs *timelineCache) DeleteTimelines(ctx context.Context, userID uuid.UUID) error {
+	return tcs.cache.DeleteByPrefix(ctx, fmt.Sprintf("%v-%v-*", timelinePrefix, userID))
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+)
+
+var (
+	viewPrefix    = "view"
+	viewBufferKey = "viewBuffer"
+)
+
+type viewCache struct {
+	cache  ports.ICacheRepository
+	window time.Duration
+}
+
+// NewViewCache create the cache of the views, the visitors of a blog in the view window
+// and a hash buffering the view counts by day, blog and referrer until they are saved
+func NewViewCache(cache ports.ICacheRepository, window time.Duration) ports.IViewCache {
+	return &viewCache{
+		cache:  cache,
+		window: window,
+	}
+}
+
+func (vcs *viewCache) MarkViewed(ctx context.Context, blogID uuid.UUID, visitor string) (bool, error) {
+	count, err := vcs.cache.Incr(ctx, generateCacheKeyParams(viewPrefix, blogID, visitor), vcs.window)
+	if err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
+
+// the fields of the buffer are the day, the blog id and the referrer host joined by |
+// which none of them contains
+func (vcs *viewCache) AddViews(ctx context.Context, counts ...domain.ViewCount) error {
+	for _, count := range counts {
+		field := strings.Join([]string{count.Day, count.BlogID.String(), count.Referrer}, "|")
+		_, err := vcs.cache.HIncrBy(ctx, viewBufferKey, field, int64(count.Views))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vcs *viewCache) TakeViews(ctx context.Context) ([]domain.ViewCount, error) {
+	fields, err := vcs.cache.HGetDelAll(ctx, viewBufferKey)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]domain.ViewCount, 0, len(fields))
+	for field, value := range fields {
+		parts := strings.SplitN(field, "|", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		blogID, err := uuid.Parse(parts[1])
+		if err != nil {
+			continue
+		}
+		views, err := strconv.Atoi(value)
+		if err != nil || views <= 0 {
+			continue
+		}
+
+		counts = append(counts, domain.ViewCount{
+			BlogID:   blogID,
+			Day:      parts[0],
+			Referrer: parts[2],
+			Views:    views,
+		})
+	}
+	return counts, nil
+}
@@ -0,0 +1,284 @@
+package content
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	gutil "github.com/yuin/goldmark/util"
+)
+
+// DefaultStyle is the highlighting style of the stylesheet when none is asked
+const DefaultStyle = "github"
+
+// plaintext is the language of the code blocks no lexer is found for
+const plaintext = "plaintext"
+
+// codeBlockAttribute is the attribute of a fenced code block node holding its *codeBlock
+const codeBlockAttribute = "code-block"
+
+// ErrUnknownStyle is returned for a highlighting style that does not exist
+var ErrUnknownStyle = errors.New("unknown highlighting style")
+
+// cssFormatter write the stylesheet of the classes of the highlighted code blocks
+var cssFormatter = chromahtml.New(chromahtml.WithClasses(true), chromahtml.WithLineNumbers(true))
+
+// codeBlock is a fenced code block read from its node, with the lexer it is highlighted with
+type codeBlock struct {
+	domain.CodeBlock
+	lexer chroma.Lexer
+}
+
+// newCodeBlock read a fenced code block, its code and the language and attributes of its info string.
+// The info string is the language followed by attributes in braces:
+//
+//	go {title="main.go" linenos=true linenostart=10 hl_lines=[2,"4-6"]}
+//
+// without language the lexer is found from the title, then from the code itself
+func newCodeBlock(node *ast.FencedCodeBlock, source []byte, index int) *codeBlock {
+	var code strings.Builder
+	lines := node.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		code.Write(segment.Value(source))
+	}
+
+	info := ""
+	if node.Info != nil {
+		info = string(node.Info.Segment.Value(source))
+	}
+	lang, attrs := parseInfo(info)
+
+	cb := &codeBlock{
+		CodeBlock: domain.CodeBlock{
+			Index:           index,
+			Title:           attrs["title"],
+			Code:            code.String(),
+			Lines:           lines.Len(),
+			LineNumberStart: 1,
+		},
+	}
+
+	if value, ok := attrs["linenos"]; ok {
+		cb.LineNumbers = value != "false"
+	}
+	if start, err := strconv.Atoi(attrs["linenostart"]); err == nil && start >= 0 {
+		cb.LineNumberStart = start
+	}
+	cb.HighlightLines = parseLineRanges(attrs["hl_lines"], cb.Lines)
+
+	switch {
+	case lang != "":
+		cb.lexer = lexers.Get(lang)
+		if cb.lexer == nil {
+			// an unknown language is kept for the client, the code is shown as it is
+			cb.lexer = lexers.Get(plaintext)
+			cb.Language = strings.ToLower(lang)
+		}
+	case cb.Title != "":
+		cb.lexer = lexers.Match(cb.Title)
+	}
+	if cb.lexer == nil {
+		cb.lexer = lexers.Analyse(cb.Code)
+	}
+	if cb.lexer == nil {
+		cb.lexer = lexers.Get(plaintext)
+	}
+	if cb.Language == "" {
+		cb.Language = strings.ToLower(strings.ReplaceAll(cb.lexer.Config().Name, " ", "-"))
+	}
+	cb.lexer = chroma.Coalesce(cb.lexer)
+
+	return cb
+}
+
+// highlight write the highlighted code, with classes for the stylesheet of WriteStyleCSS
+func (cb *codeBlock) highlight(w io.Writer) error {
+	// the highlighted lines are given to chroma as shown line numbers
+	offset := cb.LineNumberStart - 1
+	ranges := [][2]int{}
+	for _, line := range cb.HighlightLines {
+		line += offset
+		if n := len(ranges); n > 0 && ranges[n-1][1] == line-1 {
+			ranges[n-1][1] = line
+		} else {
+			ranges = append(ranges, [2]int{line, line})
+		}
+	}
+
+	formatter := chromahtml.New(
+		chromahtml.WithClasses(true),
+		chromahtml.WithPreWrapper(languagePreWrapper(cb.Language)),
+		chromahtml.WithLineNumbers(cb.LineNumbers),
+		chromahtml.BaseLineNumber(cb.LineNumberStart),
+		chromahtml.HighlightLines(ranges),
+	)
+
+	iterator, err := cb.lexer.Tokenise(nil, cb.Code)
+	if err != nil {
+		return err
+	}
+	return formatter.Format(w, styles.Fallback, iterator)
+}
+
+// languagePreWrapper wrap the highlighted code in pre and code elements, the code element has the class of its language
+type languagePreWrapper string
+
+func (lang languagePreWrapper) Start(code bool, styleAttr string) string {
+	if code {
+		return fmt.Sprintf(`<pre%s><code class="language-%s">`, styleAttr, html.EscapeString(string(lang)))
+	}
+	return fmt.Sprintf(`<pre%s>`, styleAttr)
+}
+
+func (lang languagePreWrapper) End(code bool) string {
+	if code {
+		return `</code></pre>`
+	}
+	return `</pre>`
+}
+
+// codeBlockRenderer render the fenced code blocks highlighted
+type codeBlockRenderer struct{}
+
+func (r *codeBlockRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindFencedCodeBlock, r.renderFencedCodeBlock)
+}
+
+func (r *codeBlockRenderer) renderFencedCodeBlock(w gutil.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	value, _ := node.AttributeString(codeBlockAttribute)
+	cb, ok := value.(*codeBlock)
+	if !ok {
+		cb = newCodeBlock(node.(*ast.FencedCodeBlock), source, 0)
+	}
+
+	return ast.WalkSkipChildren, cb.highlight(w)
+}
+
+// WriteStyleCSS write the stylesheet of a highlighting style for the classes of the rendered code blocks
+func WriteStyleCSS(w io.Writer, name string) error {
+	style, ok := styles.Registry[name]
+	if !ok {
+		return ErrUnknownStyle
+	}
+	return cssFormatter.WriteCSS(w, style)
+}
+
+// parseInfo split the info string of a fenced code block in its language and the attributes in braces after it
+func parseInfo(info string) (string, map[string]string) {
+	attrs := map[string]string{}
+
+	info = strings.TrimSpace(info)
+	if start := strings.IndexByte(info, '{'); start >= 0 && strings.HasSuffix(info, "}") {
+		attrs = parseAttributes(info[start+1 : len(info)-1])
+		info = info[:start]
+	}
+
+	lang := ""
+	if fields := strings.Fields(info); len(fields) > 0 {
+		lang = fields[0]
+	}
+	return lang, attrs
+}
+
+// parseAttributes read key=value attributes separated by commas or spaces,
+// values can be quoted or lists in brackets
+func parseAttributes(text string) map[string]string {
+	attrs := map[string]string{}
+
+	var field strings.Builder
+	flush := func() {
+		key, value, _ := strings.Cut(field.String(), "=")
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			attrs[key] = unquote(strings.TrimSpace(value))
+		}
+		field.Reset()
+	}
+
+	depth := 0
+	var quote rune
+	for _, r := range text {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '[':
+			depth++
+		case r == ']':
+			if depth > 0 {
+				depth--
+			}
+		case depth == 0 && (r == ',' || unicode.IsSpace(r)):
+			flush()
+			continue
+		}
+		field.WriteRune(r)
+	}
+	flush()
+
+	return attrs
+}
+
+// parseLineRanges read a list of lines and ranges of lines like [2,"4-6"] or "2 4-6",
+// return the sorted lines between 1 and count
+func parseLineRanges(text string, count int) []int {
+	text = strings.Trim(text, "[]")
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+
+	set := map[int]bool{}
+	for _, field := range fields {
+		from, to, isRange := strings.Cut(unquote(field), "-")
+		start, err := strconv.Atoi(from)
+		if err != nil {
+			continue
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(to); err != nil {
+				continue
+			}
+		}
+
+		for line := max(start, 1); line <= end && line <= count; line++ {
+			set[line] = true
+		}
+	}
+
+	if len(set) == 0 {
+		return nil
+	}
+	lines := make([]int, 0, len(set))
+	for line := range set {
+		lines = append(lines, line)
+	}
+	sort.Ints(lines)
+	return lines
+}
+
+func unquote(text string) string {
+	if len(text) >= 2 && (text[0] == '"' || text[0] == '\'') && text[len(text)-1] == text[0] {
+		return text[1 : len(text)-1]
+	}
+	return text
+}
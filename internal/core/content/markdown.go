@@ -0,0 +1,198 @@
+package content
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/util"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	gutil "github.com/yuin/goldmark/util"
+)
+
+// Version is the version of the rendering, bump it when the output of Render changes
+// so renderings cached by Hash are not used anymore
+const Version = "2"
+
+// headingAnchorClass is the class of the link to its own anchor added in every heading
+const headingAnchorClass = "heading-anchor"
+
+var markdown = goldmark.New(
+	// the GitHub Flavored Markdown extensions, tables align their cells with the attribute the sanitizer allows
+	goldmark.WithExtensions(
+		extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+		extension.Strikethrough,
+		extension.Linkify,
+		extension.TaskList,
+	),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	// raw HTML is kept, the sanitizer decides what stays, fenced code blocks are highlighted
+	goldmark.WithRendererOptions(
+		html.WithUnsafe(),
+		renderer.WithNodeRenderers(gutil.Prioritized(&codeBlockRenderer{}, 100)),
+	),
+)
+
+// policy is the allow-list of the rendered HTML, the user generated content policy with the heading anchors,
+// task list checkboxes, highlighted code and cell alignments rendered from markdown
+var policy = newPolicy()
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^[\p{L}\p{N}\p{M}-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("class").Matching(regexp.MustCompile("^" + headingAnchorClass + "$")).OnElements("a")
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").Matching(regexp.MustCompile(`^$`)).OnElements("input")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#.-]+$`)).OnElements("code")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^chroma$`)).OnElements("pre")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^[a-z][a-z0-9]{0,6}( hl)?$`)).OnElements("span")
+	p.AllowAttrs("align").Matching(regexp.MustCompile(`^(left|center|right)$`)).OnElements("th", "td")
+	return p
+}
+
+// Hash return the key of the rendering of a text, it changes with the text and the version of the rendering
+func Hash(src string) string {
+	h := sha256.New()
+	h.Write([]byte(Version))
+	h.Write([]byte{0})
+	h.Write([]byte(src))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Render render a markdown text, CommonMark with the GitHub Flavored Markdown extensions,
+// to sanitized HTML. Headings get an id and a link to it, they make the table of contents,
+// fenced code blocks are highlighted and listed with the options of their info string
+func Render(src string) (*domain.RenderedContent, error) {
+	source := []byte(src)
+	ctx := parser.NewContext(parser.WithIDs(newHeadingIDs()))
+	doc := markdown.Parser().Parse(text.NewReader(source), parser.WithContext(ctx))
+
+	headings := []domain.TOCEntry{}
+	codeBlocks := []domain.CodeBlock{}
+	err := ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		if fenced, ok := node.(*ast.FencedCodeBlock); ok {
+			cb := newCodeBlock(fenced, source, len(codeBlocks))
+			fenced.SetAttributeString(codeBlockAttribute, cb)
+			codeBlocks = append(codeBlocks, cb.CodeBlock)
+			return ast.WalkSkipChildren, nil
+		}
+
+		heading, ok := node.(*ast.Heading)
+		if !ok {
+			return ast.WalkContinue, nil
+		}
+
+		id, _ := heading.AttributeString("id")
+		idBytes, _ := id.([]byte)
+		headings = append(headings, domain.TOCEntry{
+			ID:    string(idBytes),
+			Title: nodeText(heading, source),
+			Level: heading.Level,
+		})
+
+		anchor := ast.NewLink()
+		anchor.Destination = append([]byte("#"), idBytes...)
+		anchor.SetAttributeString("class", []byte(headingAnchorClass))
+		anchor.SetAttributeString("aria-hidden", []byte("true"))
+		anchor.AppendChild(anchor, ast.NewString([]byte("#")))
+		heading.InsertBefore(heading, heading.FirstChild(), anchor)
+
+		return ast.WalkSkipChildren, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := markdown.Renderer().Render(&buf, source, doc); err != nil {
+		return nil, err
+	}
+
+	return &domain.RenderedContent{
+		HTML:       policy.Sanitize(buf.String()),
+		TOC:        nestTOC(headings),
+		CodeBlocks: codeBlocks,
+	}, nil
+}
+
+// nestTOC put every heading under the closest heading of a lower level before it
+func nestTOC(headings []domain.TOCEntry) []domain.TOCEntry {
+	entries := []domain.TOCEntry{}
+	for i := 0; i < len(headings); {
+		entry := headings[i]
+		end := i + 1
+		for end < len(headings) && headings[end].Level > entry.Level {
+			end++
+		}
+		if end > i+1 {
+			entry.Children = nestTOC(headings[i+1 : end])
+		}
+		entries = append(entries, entry)
+		i = end
+	}
+	return entries
+}
+
+// nodeText return the plain text of the inline children of a node
+func nodeText(node ast.Node, source []byte) string {
+	var buf bytes.Buffer
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Text:
+			buf.Write(n.Segment.Value(source))
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				buf.WriteByte(' ')
+			}
+		case *ast.String:
+			buf.Write(n.Value)
+		case *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return buf.String()
+}
+
+// headingIDs make the ids of the headings of a text from their slug, numbered when a slug is used twice
+type headingIDs struct {
+	used map[string]bool
+}
+
+func newHeadingIDs() *headingIDs {
+	return &headingIDs{used: map[string]bool{}}
+}
+
+func (hi *headingIDs) Generate(value []byte, _ ast.NodeKind) []byte {
+	base := util.Slugify(string(value))
+	if base == "" {
+		base = "section"
+	}
+
+	id := base
+	for n := 2; hi.used[id]; n++ {
+		id = util.SlugWithSuffix(base, n)
+	}
+	hi.used[id] = true
+	return []byte(id)
+}
+
+func (hi *headingIDs) Put(value []byte) {
+	hi.used[string(value)] = true
+}
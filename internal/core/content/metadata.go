@@ -0,0 +1,97 @@
+package content
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// WordsPerMinute is the reading speed the reading time is estimated with
+const WordsPerMinute = 200
+
+// MaxExcerptLength is the maximum length of an excerpt in runes
+const MaxExcerptLength = 280
+
+// Metadata compute what is shown of a markdown text without it: the plain text of its first paragraph
+// as excerpt, its number of words and its reading time in minutes
+func Metadata(src string) *domain.TextMetadata {
+	source := []byte(src)
+	doc := markdown.Parser().Parse(text.NewReader(source))
+
+	metadata := &domain.TextMetadata{}
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch node := node.(type) {
+		case *ast.Paragraph:
+			if metadata.Excerpt == "" {
+				metadata.Excerpt = truncateText(strings.Join(strings.Fields(nodeText(node, source)), " "), MaxExcerptLength)
+			}
+		case *ast.Text:
+			metadata.WordCount += countWords(string(node.Segment.Value(source)))
+		case *ast.String:
+			metadata.WordCount += countWords(string(node.Value))
+		case *ast.AutoLink:
+			metadata.WordCount++
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			lines := node.Lines()
+			for i := 0; i < lines.Len(); i++ {
+				segment := lines.At(i)
+				metadata.WordCount += countWords(string(segment.Value(source)))
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.HTMLBlock, *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	if metadata.WordCount > 0 {
+		metadata.ReadingTime = (metadata.WordCount + WordsPerMinute - 1) / WordsPerMinute
+	}
+	return metadata
+}
+
+// countWords count the words of a text, runs of letters and digits, apostrophes inside words included.
+// Chinese and Japanese, written without spaces, count every character as a word
+func countWords(text string) int {
+	count := 0
+	inWord := false
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana):
+			count++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r):
+			if !inWord {
+				count++
+				inWord = true
+			}
+		case inWord && (r == '\'' || r == '’'):
+		default:
+			inWord = false
+		}
+	}
+	return count
+}
+
+// truncateText cut a text to at most max runes, at a space if there is one, with an ellipsis
+func truncateText(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+
+	cut := string(runes[:max-1])
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRightFunc(cut, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + "…"
+}
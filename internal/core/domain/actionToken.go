@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TokenPurpose is what a single-use action token can be used for
+type TokenPurpose string
+
+const (
+	// PasswordResetPurpose is the purpose of password reset tokens
+	PasswordResetPurpose TokenPurpose = "password_reset"
+	// EmailVerificationPurpose is the purpose of email verification tokens
+	EmailVerificationPurpose TokenPurpose = "email_verification"
+	// MagicLinkPurpose is the purpose of passwordless login tokens
+	MagicLinkPurpose TokenPurpose = "magic_link"
+)
+
+// ActionToken is a single-use, expiring token sent to a user,
+// only the hash of the token is stored
+type ActionToken struct {
+	ID        uuid.UUID    `json:"id"`
+	UserID    uuid.UUID    `json:"user_id"`
+	Purpose   TokenPurpose `json:"purpose"`
+	TokenHash string       `json:"-"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    *time.Time   `json:"used_at,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// IsUsable check if token has not been used and has not expired
+func (at *ActionToken) IsUsable(now time.Time) bool {
+	return at.UsedAt == nil && now.Before(at.ExpiresAt)
+}
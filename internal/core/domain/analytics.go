@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DirectReferrer is the referrer of the views without a Referer header
+	DirectReferrer = "direct"
+	// InternalReferrer is the referrer of the views coming from a page of the site
+	InternalReferrer = "internal"
+)
+
+// BlogView is a read of a blog, the visitor is known by its ip and user agent
+type BlogView struct {
+	BlogID    uuid.UUID
+	IP        string
+	UserAgent string
+	Referrer  string // the Referer header of the request
+	ViewedAt  time.Time
+}
+
+// ViewCount is a number of views of a blog on a day from a referrer host
+type ViewCount struct {
+	BlogID   uuid.UUID
+	Day      string // UTC date, 2006-01-02
+	Referrer string
+	Views    int
+}
+
+// DailyViews is the number of views of a blog on a day
+type DailyViews struct {
+	Day   string
+	Views int
+}
+
+// ReferrerViews is the number of views of a blog from a referrer host
+type ReferrerViews struct {
+	Referrer string
+	Views    int
+}
+
+// BlogStats is the views of a blog between two days, both included
+type BlogStats struct {
+	BlogID     uuid.UUID
+	From       string
+	To         string
+	TotalViews int
+	Days       []DailyViews    // every day of the range, oldest first
+	Referrers  []ReferrerViews // the referrers with most views first
+}
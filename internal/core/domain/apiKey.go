@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyScope is an action an api key is allowed to do
+type APIKeyScope string
+
+const (
+	// BlogsWriteScope allow creating, updating and deleting the blogs of the key owner
+	BlogsWriteScope APIKeyScope = "blogs:write"
+)
+
+// APIKey is a named, scoped credential of a user for automation,
+// only the hash of the key is stored
+type APIKey struct {
+	ID         uuid.UUID     `json:"id"`
+	UserID     uuid.UUID     `json:"user_id"`
+	Name       string        `json:"name"`
+	Prefix     string        `json:"prefix"` // first characters of the key to recognize it
+	KeyHash    string        `json:"-"`
+	Scopes     []APIKeyScope `json:"scopes"`
+	ExpiresAt  *time.Time    `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time    `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+// IsExpired check if the key has an expiry date and it has passed
+func (ak *APIKey) IsExpired(now time.Time) bool {
+	return ak.ExpiresAt != nil && !now.Before(*ak.ExpiresAt)
+}
+
+// HasScope check if the key was granted the scope
+func (ak *APIKey) HasScope(scope APIKeyScope) bool {
+	for _, s := range ak.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
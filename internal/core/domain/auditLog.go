@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditAction is an admin action recorded in the audit log
+type AuditAction string
+
+const (
+	// AuditSuspendUser is recorded when an admin suspends a user
+	AuditSuspendUser AuditAction = "user.suspend"
+	// AuditReactivateUser is recorded when an admin reactivates a user
+	AuditReactivateUser AuditAction = "user.reactivate"
+	// AuditForcePasswordReset is recorded when an admin forces a user to reset its password
+	AuditForcePasswordReset AuditAction = "user.force_password_reset"
+	// AuditImpersonateUser is recorded when an admin gets a token to act as a user
+	AuditImpersonateUser AuditAction = "user.impersonate"
+	// AuditRestoreUser is recorded when an admin takes a user out of the trash
+	AuditRestoreUser AuditAction = "user.restore"
+	// AuditPromoteUser is recorded when a user is made admin from the configuration
+	AuditPromoteUser AuditAction = "user.promote"
+)
+
+// AuditLog is a record of an action taken by an admin on a user
+type AuditLog struct {
+	ID        uuid.UUID   `json:"id"`
+	ActorID   *uuid.UUID  `json:"actor_id,omitempty"` // nil for actions of the system
+	Action    AuditAction `json:"action"`
+	TargetID  uuid.UUID   `json:"target_id"`
+	Reason    string      `json:"reason,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
@@ -7,10 +7,69 @@ import (
 )
 
 type Blog struct {
-	ID        uuid.UUID `json:"id"`
-	Title     string    `json:"title"`
-	Text      string    `json:"text,omitempty"`
-	AuthorID  uuid.UUID `json:"author_id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          uuid.UUID  `json:"id"`
+	Title       string     `json:"title"`
+	Slug        string     `json:"slug"`           // unique, made from the title unless the author set it
+	Tags        []string   `json:"tags,omitempty"` // in alphabetical order, only read with a single blog
+	Text        string     `json:"text,omitempty"`
+	Summary     string     `json:"summary,omitempty"` // set by the author, shown instead of the excerpt
+	Excerpt     string     `json:"excerpt,omitempty"` // computed from the text when it is written, like WordCount and ReadingTime
+	WordCount   int        `json:"word_count"`
+	ReadingTime int        `json:"reading_time"` // in minutes
+	AuthorID    uuid.UUID  `json:"author_id"`
+	Version     int        `json:"version"` // increased on every update, an update with a version only applies to it
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"` // only set on blogs in the trash
+}
+
+// BlogPatch is a partial update of a blog, nil fields are left unchanged
+// and an empty string clears the field
+type BlogPatch struct {
+	Title   *string
+	Text    *string
+	Slug    *string // an empty slug makes the slug follow the title again
+	Summary *string
+	Tags    *[]string // replaces every tag of the blog
+}
+
+// TextMetadata is what is computed from the text of a blog when it is written,
+// it is shown in place of the text in lists
+type TextMetadata struct {
+	Excerpt     string // the plain text of the first paragraph
+	WordCount   int
+	ReadingTime int // in minutes
+}
+
+// RenderedContent is the HTML rendering of the markdown text of a blog
+type RenderedContent struct {
+	HTML       string      `json:"html"`
+	TOC        []TOCEntry  `json:"toc"`
+	CodeBlocks []CodeBlock `json:"code_blocks"`
+}
+
+// TOCEntry is a heading of a rendered text, the headings under it are its children
+type TOCEntry struct {
+	ID       string     `json:"id"`
+	Title    string     `json:"title"`
+	Level    int        `json:"level"`
+	Children []TOCEntry `json:"children,omitempty"`
+}
+
+// CodeBlock is a fenced code block of a rendered text, with the options of its info string
+type CodeBlock struct {
+	// Index is the position of the block in the text, from 0
+	Index int `json:"index"`
+	// Language is the language the block is highlighted as, "plaintext" when none is found
+	Language string `json:"language"`
+	// Title is the file name given in the info string, if any
+	Title string `json:"title,omitempty"`
+	Code  string `json:"code"`
+	// Lines is the number of lines of the code
+	Lines int `json:"lines"`
+	// LineNumbers tells if the block is shown with line numbers, counted from LineNumberStart
+	LineNumbers     bool `json:"line_numbers"`
+	LineNumberStart int  `json:"line_number_start"`
+	// HighlightLines are the highlighted lines, counted from 1 at the first line of the block
+	HighlightLines []int `json:"highlight_lines,omitempty"`
 }
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BlogRevision is an immutable copy of the content of a blog, one is saved on every change
+type BlogRevision struct {
+	ID          uuid.UUID `json:"id"`
+	BlogID      uuid.UUID `json:"blog_id"`
+	Number      int       `json:"number"` // starts at 1 for each blog
+	AuthorID    uuid.UUID `json:"author_id"`
+	Title       string    `json:"title"`
+	Text        string    `json:"text,omitempty"`
+	ContentHash string    `json:"content_hash"` // hex SHA-256 of the title and text
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// DiffOp is the operation turning a line of the old revision into the new one
+type DiffOp string
+
+const (
+	// DiffEqual is a line in both revisions
+	DiffEqual DiffOp = "equal"
+	// DiffInsert is a line only in the new revision
+	DiffInsert DiffOp = "insert"
+	// DiffDelete is a line only in the old revision
+	DiffDelete DiffOp = "delete"
+)
+
+// DiffLine is a line of a diff
+type DiffLine struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// RevisionDiff is the line-level diff of the text of two revisions
+type RevisionDiff struct {
+	From      int        `json:"from"`
+	To        int        `json:"to"`
+	FromTitle string     `json:"from_title"`
+	ToTitle   string     `json:"to_title"`
+	Lines     []DiffLine `json:"lines"`
+}
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Bookmark is a blog a user saved to read later, with a note of the user
+type Bookmark struct {
+	UserID    uuid.UUID
+	BlogID    uuid.UUID
+	Note      string
+	CreatedAt time.Time
+	Blog      *Blog // summary of the blog, without its text
+}
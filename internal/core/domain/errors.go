@@ -23,6 +23,8 @@ var (
 	ErrExpiredToken = errors.New("access token has expired")
 	// ErrInvalidToken is an error for when the access token is invalid
 	ErrInvalidToken = errors.New("access token is invalid")
+	// ErrRevokedToken is an error for when the access token has been revoked
+	ErrRevokedToken = errors.New("access token has been revoked")
 	// ErrInvalidCredentials is an error for when the credentials are invalid
 	ErrInvalidCredentials = errors.New("invalid username or password")
 	// ErrEmptyAuthorizationHeader is an error for when the authorization header is empty
@@ -35,4 +37,58 @@ var (
 	ErrUnauthorized = errors.New("user is unauthorized to access the resource")
 	// ErrForbidden is an error for when the user is forbidden to access the resource
 	ErrForbidden = errors.New("user is forbidden to access the resource")
+	// ErrWeakPassword is an error for when the password does not satisfy the password policy
+	ErrWeakPassword = errors.New("password does not satisfy the password policy")
+	// ErrIncorrectPassword is an error for when the confirmation password is incorrect
+	ErrIncorrectPassword = errors.New("current password is incorrect")
+	// ErrInvalidActionToken is an error for when a single-use token is invalid, used or expired
+	ErrInvalidActionToken = errors.New("token is invalid or has expired")
+	// ErrEmailNotVerified is an error for when the action requires a verified email
+	ErrEmailNotVerified = errors.New("email address is not verified")
+	// ErrEmailAlreadyVerified is an error for when the email is already verified
+	ErrEmailAlreadyVerified = errors.New("email address is already verified")
+	// ErrInvalidMFACode is an error for when the TOTP or recovery code is invalid
+	ErrInvalidMFACode = errors.New("authentication code is invalid")
+	// ErrMFAAlreadyEnabled is an error for when the two-factor authentication is already enabled
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	// ErrMFANotEnabled is an error for when the two-factor authentication is not enabled
+	ErrMFANotEnabled = errors.New("two-factor authentication is not enabled")
+	// ErrMFANotEnrolled is an error for when the two-factor authentication enrolment has not been started
+	ErrMFANotEnrolled = errors.New("two-factor authentication enrolment has not been started")
+	// ErrInvalidPasskey is an error for when a passkey registration response is invalid or its ceremony has expired
+	ErrInvalidPasskey = errors.New("passkey response is invalid or has expired")
+	// ErrPasskeyLoginFailed is an error for when a passkey assertion can not be verified
+	ErrPasskeyLoginFailed = errors.New("passkey authentication failed")
+	// ErrTooManyRequests is an error for when a rate limit is exceeded
+	ErrTooManyRequests = errors.New("too many requests, try again later")
+	// ErrInvalidOIDCState is an error for when an external login state is unknown, used or expired
+	ErrInvalidOIDCState = errors.New("login state is invalid or has expired")
+	// ErrOIDCLoginFailed is an error for when the identity provider response can not be verified
+	ErrOIDCLoginFailed = errors.New("external login failed")
+	// ErrIdentityLinkConflict is an error for when an external identity matches the unverified email of an account
+	ErrIdentityLinkConflict = errors.New("an account already uses this email, sign in and verify the email before using this provider")
+	// ErrInvalidAPIKey is an error for when an api key is unknown, revoked or expired
+	ErrInvalidAPIKey = errors.New("api key is invalid or has expired")
+	// ErrInsufficientScope is an error for when an api key was not granted the scope the action requires
+	ErrInsufficientScope = errors.New("api key does not have the required scope")
+	// ErrInvalidCSRFToken is an error for when a cookie authenticated request fails the CSRF check
+	ErrInvalidCSRFToken = errors.New("csrf token is missing or invalid")
+	// ErrAccountSuspended is an error for when the user account has been suspended
+	ErrAccountSuspended = errors.New("user account is suspended")
+	// ErrAdminTarget is an error for when an admin action targets an admin
+	ErrAdminTarget = errors.New("action can not target an admin")
+	// ErrNoEmail is an error for when the user has no email address
+	ErrNoEmail = errors.New("user has no email address")
+	// ErrPreconditionFailed is an error for when the resource has changed since the version the client sent
+	ErrPreconditionFailed = errors.New("resource has been modified since it was read")
+	// ErrPreconditionRequired is an error for when a write does not say which version it applies to
+	ErrPreconditionRequired = errors.New("if-match header is required")
+	// ErrInvalidSlug is an error for when a slug is not lower case letters and digits joined by hyphens
+	ErrInvalidSlug = errors.New("slug must be lower case letters and digits joined by hyphens")
+	// ErrInvalidTag is an error for when a tag is not lower case letters and digits joined by hyphens
+	ErrInvalidTag = errors.New("tag must be lower case letters and digits joined by hyphens, up to 32 characters")
+	// ErrUnknownReaction is an error for when a reaction kind is not one of the configured kinds
+	ErrUnknownReaction = errors.New("unknown reaction kind")
+	// ErrSelfFollow is an error for when a user tries to follow themselves
+	ErrSelfFollow = errors.New("users can not follow themselves")
 )
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// FeedFormat is a format feeds are written in
+type FeedFormat string
+
+const (
+	FeedRSS  FeedFormat = "rss"  // RSS 2.0
+	FeedAtom FeedFormat = "atom" // Atom 1.0
+	FeedJSON FeedFormat = "json" // JSON Feed 1.1
+)
+
+// Feed is a feed document of the latest blogs
+type Feed struct {
+	Format    FeedFormat `json:"format"`
+	Content   string     `json:"content"`
+	ETag      string     `json:"etag"`       // hash of the content
+	UpdatedAt time.Time  `json:"updated_at"` // last update of the blogs in the feed
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Follow is a user following an author, the blogs of the author show in the timeline of the follower
+type Follow struct {
+	FollowerID uuid.UUID
+	FolloweeID uuid.UUID
+	CreatedAt  time.Time
+	User       *User // the follower in a list of followers, the followed author in a list of following
+}
+
+// FollowCounts is the number of followers of a user and the number of authors the user follows
+type FollowCounts struct {
+	UserID    uuid.UUID
+	Followers int
+	Following int
+}
+
+// TimelineCursor is the position of the last blog of a timeline page, the next page starts after it
+type TimelineCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// Timeline is a page of the latest blogs of the authors a user follows, newest first
+type Timeline struct {
+	Blogs []Blog
+	Next  *TimelineCursor // nil on the last page
+}
@@ -0,0 +1,9 @@
+package domain
+
+// Mail is an email message
+type Mail struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
@@ -0,0 +1,15 @@
+package domain
+
+// LoginResult is the result of a login, when the user has two-factor authentication
+// enabled Token is empty and MFAToken must be exchanged with a code for the access token
+type LoginResult struct {
+	Token       string
+	MFARequired bool
+	MFAToken    string
+}
+
+// TOTPEnrollment is the TOTP secret of a started enrolment
+type TOTPEnrollment struct {
+	Secret string
+	URI    string
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Passkey is a WebAuthn public key credential registered by a user
+type Passkey struct {
+	ID              uuid.UUID  `json:"id"`
+	UserID          uuid.UUID  `json:"user_id"`
+	Name            string     `json:"name"`
+	CredentialID    []byte     `json:"-"`
+	PublicKey       []byte     `json:"-"`
+	AttestationType string     `json:"-"`
+	Transports      []string   `json:"-"`
+	AAGUID          []byte     `json:"-"`
+	SignCount       uint32     `json:"-"`
+	BackupEligible  bool       `json:"-"`
+	BackupState     bool       `json:"-"`
+	LastUsedAt      *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
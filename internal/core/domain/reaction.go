@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BlogReaction is a reaction of a user to a blog, a user adds each kind of reaction once
+type BlogReaction struct {
+	BlogID    uuid.UUID
+	UserID    uuid.UUID
+	Kind      string
+	CreatedAt time.Time
+}
+
+// ReactionCounts is the number of reactions of each kind to a blog
+type ReactionCounts map[string]int
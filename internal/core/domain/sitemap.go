@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// Sitemap is a sitemap document, a list of pages of the site or the index of the sitemaps
+// when they are more than a sitemap can hold
+type Sitemap struct {
+	Page      int       `json:"page"`  // 0 for /sitemap.xml, from 1 for the sitemaps of an index
+	Pages     int       `json:"pages"` // number of sitemaps of the index, 0 without index
+	Content   string    `json:"content"`
+	ETag      string    `json:"etag"`       // hash of the content
+	UpdatedAt time.Time `json:"updated_at"` // last update of the listed pages
+}
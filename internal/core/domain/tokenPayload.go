@@ -3,6 +3,12 @@ package domain
 import "github.com/google/uuid"
 
 type TokenPayload struct {
-	ID   uuid.UUID `json:"id"`
-	Name string    `json:"name"`
+	ID             uuid.UUID  `json:"id"`
+	Name           string     `json:"name"`
+	Version        int        `json:"version"`
+	Verified       bool       `json:"verified"`                  // email verified
+	Role           UserRole   `json:"role"`                      // loaded from the user, not the token
+	APIKeyID       *uuid.UUID `json:"api_key_id,omitempty"`      // set when authenticated with an api key
+	ImpersonatorID *uuid.UUID `json:"impersonator_id,omitempty"` // set when an admin acts as the user
+	ChallengeID    string     `json:"-"`                         // set for two-factor challenges, its failed codes are counted
 }
@@ -6,10 +6,59 @@ import (
 	"github.com/google/uuid"
 )
 
+// UserRole is the role of a user
+type UserRole string
+
+const (
+	// RoleUser is the role of every registered user
+	RoleUser UserRole = "user"
+	// RoleAdmin is the role of users allowed to manage other users
+	RoleAdmin UserRole = "admin"
+)
+
+// UserStatus is the status of a user account
+type UserStatus string
+
+const (
+	// StatusActive is the status of a user allowed to sign in
+	StatusActive UserStatus = "active"
+	// StatusSuspended is the status of a user suspended by an admin, its tokens and logins are rejected
+	StatusSuspended UserStatus = "suspended"
+)
+
 type User struct {
-	ID        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	Password  string    `json:"password,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            uuid.UUID  `json:"id"`
+	Name          string     `json:"name"`
+	Email         string     `json:"email,omitempty"`
+	EmailVerified bool       `json:"email_verified"`
+	Password      string     `json:"password,omitempty"`
+	TokenVersion  int        `json:"token_version"` // increased when credentials change, older tokens are revoked
+	TOTPSecret    string     `json:"-"`             // never cached
+	TOTPEnabled   bool       `json:"totp_enabled"`
+	Role          UserRole   `json:"role"`
+	Status        UserStatus `json:"status"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"` // only set on users in the trash
+}
+
+// UserPatch is a partial update of the name and email of a user, nil fields are left unchanged
+// and an empty email removes the email of the user
+type UserPatch struct {
+	Name  *string
+	Email *string
+}
+
+// IsSuspended check if the user account is suspended
+func (u *User) IsSuspended() bool {
+	return u.Status == StatusSuspended
+}
+
+// UserFilter is the filter of a user listing, zero fields are not filtered
+type UserFilter struct {
+	NamePrefix    string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Role          UserRole
+	Status        UserStatus
 }
@@ -0,0 +1,41 @@
+package domain
+
+import "strings"
+
+// FieldError is a single validation failure of a field
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationError is a structured error holding every validation failure,
+// it wraps a domain error so it can be matched with errors.Is
+type ValidationError struct {
+	Err    error
+	Fields []FieldError
+}
+
+// NewValidationError create an new validation error wrapping err
+func NewValidationError(err error, fields ...FieldError) *ValidationError {
+	return &ValidationError{
+		Err:    err,
+		Fields: fields,
+	}
+}
+
+func (ve *ValidationError) Error() string {
+	msgs := make([]string, 0, len(ve.Fields))
+	for _, field := range ve.Fields {
+		msgs = append(msgs, field.Message)
+	}
+
+	if len(msgs) == 0 {
+		return ve.Err.Error()
+	}
+	return ve.Err.Error() + ": " + strings.Join(msgs, ", ")
+}
+
+func (ve *ValidationError) Unwrap() error {
+	return ve.Err
+}
@@ -0,0 +1,72 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	htmlTemplate "html/template"
+	"strings"
+	textTemplate "text/template"
+
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+var (
+	textTemplates = textTemplate.Must(textTemplate.ParseFS(templatesFS, "templates/*.txt"))
+	htmlTemplates = htmlTemplate.Must(htmlTemplate.ParseFS(templatesFS, "templates/*.html"))
+)
+
+const (
+	// PasswordResetTemplate is the password reset email, data is PasswordResetData
+	PasswordResetTemplate = "password_reset"
+	// EmailVerificationTemplate is the email verification email, data is EmailVerificationData
+	EmailVerificationTemplate = "email_verification"
+	// MagicLinkTemplate is the passwordless login email, data is MagicLinkData
+	MagicLinkTemplate = "magic_link"
+)
+
+// PasswordResetData is the data of the password reset email
+type PasswordResetData struct {
+	Username  string
+	ResetURL  string
+	ExpiresIn string
+}
+
+// EmailVerificationData is the data of the email verification email
+type EmailVerificationData struct {
+	Username  string
+	VerifyURL string
+	ExpiresIn string
+}
+
+// MagicLinkData is the data of the passwordless login email
+type MagicLinkData struct {
+	Username  string
+	LoginURL  string
+	ExpiresIn string
+}
+
+// Render render the named template to a mail sent to the given address,
+// the template files define "<name>_subject" and "<name>_text" in a .txt file and "<name>_html" in a .html file
+func Render(name string, to string, data any) (*domain.Mail, error) {
+	var subject, text, html bytes.Buffer
+
+	if err := textTemplates.ExecuteTemplate(&subject, name+"_subject", data); err != nil {
+		return nil, err
+	}
+	if err := textTemplates.ExecuteTemplate(&text, name+"_text", data); err != nil {
+		return nil, err
+	}
+	if err := htmlTemplates.ExecuteTemplate(&html, name+"_html", data); err != nil {
+		return nil, err
+	}
+
+	return &domain.Mail{
+		To:      []string{to},
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+)
+
+type IActionTokenRepository interface {
+	// CreateToken insert an new action token into the database
+	CreateToken(ctx context.Context, token *domain.ActionToken) (*domain.ActionToken, error)
+	// GetTokenByHash select a token by purpose and token hash
+	GetTokenByHash(ctx context.Context, purpose domain.TokenPurpose, hash string) (*domain.ActionToken, error)
+	// ConsumeToken mark an unused token as used, return domain.ErrInvalidActionToken if it is already used
+	ConsumeToken(ctx context.Context, id uuid.UUID) error
+	// DeleteTokensByUserID delete all tokens of a user with the purpose
+	DeleteTokensByUserID(ctx context.Context, userID uuid.UUID, purpose domain.TokenPurpose) error
+}
@@ -0,0 +1,37 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+)
+
+type IAuditLogRepository interface {
+	// CreateAuditLog insert a new audit log into the database
+	CreateAuditLog(ctx context.Context, log *domain.AuditLog) (*domain.AuditLog, error)
+	// ListAuditLogs select audit logs, newest first, filtered by target if targetID is not nil,
+	// return the total number of matching logs
+	ListAuditLogs(ctx context.Context, targetID *uuid.UUID, skip, limit int) ([]domain.AuditLog, int, error)
+}
+
+type IAdminService interface {
+	// ListUsers list the users matching the filter, return the total number of matching users
+	ListUsers(ctx context.Context, filter domain.UserFilter, skip, limit int) ([]domain.User, int, error)
+	// SuspendUser suspend a user, its tokens, api keys and logins are rejected until it is reactivated
+	SuspendUser(ctx context.Context, adminID, id uuid.UUID, reason string) (*domain.User, error)
+	// ReactivateUser reactivate a suspended user
+	ReactivateUser(ctx context.Context, adminID, id uuid.UUID, reason string) (*domain.User, error)
+	// ForcePasswordReset revoke the password and tokens of a user and email it a password reset link
+	ForcePasswordReset(ctx context.Context, adminID, id uuid.UUID, reason string) error
+	// Impersonate return a short-lived access token of a user for an admin acting as it
+	Impersonate(ctx context.Context, adminID, id uuid.UUID, reason string) (string, error)
+	// ListAuditLogs list the audit logs, filtered by target if targetID is not nil
+	ListAuditLogs(ctx context.Context, targetID *uuid.UUID, skip, limit int) ([]domain.AuditLog, int, error)
+	// ListDeletedUsers list the users in the trash, return the total number of users in the trash
+	ListDeletedUsers(ctx context.Context, skip, limit int) ([]domain.User, int, error)
+	// RestoreUser take a user out of the trash with the blogs deleted along with it
+	RestoreUser(ctx context.Context, adminID, id uuid.UUID, reason string) (*domain.User, error)
+	// PromoteAdmins make the users with the names admins, unknown names are skipped
+	PromoteAdmins(ctx context.Context, names []string) error
+}
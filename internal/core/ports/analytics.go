@@ -0,0 +1,40 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+)
+
+type IAnalyticsRepository interface {
+	// SaveViews add the view counts to the daily views and the daily referrer views of their blogs,
+	// inserted by batches of batchSize rows in one transaction
+	SaveViews(ctx context.Context, counts []domain.ViewCount, batchSize int) error
+	// GetDailyViews select the days of a blog with views between two days, both included, oldest first
+	GetDailyViews(ctx context.Context, blogID uuid.UUID, from, to string) ([]domain.DailyViews, error)
+	// GetReferrerViews select the views of a blog by referrer between two days, both included,
+	// the limit referrers with most views first
+	GetReferrerViews(ctx context.Context, blogID uuid.UUID, from, to string, limit int) ([]domain.ReferrerViews, error)
+}
+
+type IViewCache interface {
+	// MarkViewed mark a blog viewed by a visitor for the view window, return false if it already was
+	MarkViewed(ctx context.Context, blogID uuid.UUID, visitor string) (bool, error)
+	// AddViews add view counts to the buffer of views
+	AddViews(ctx context.Context, counts ...domain.ViewCount) error
+	// TakeViews get the buffered view counts and empty the buffer
+	TakeViews(ctx context.Context) ([]domain.ViewCount, error)
+}
+
+type IAnalyticsService interface {
+	// RecordView count a read of a blog unless the visitor is a bot or already read it in the view window,
+	// errors are logged, a view that is not recorded does not fail the read
+	RecordView(ctx context.Context, view *domain.BlogView)
+	// Flush save the buffered views to the daily views, return the number of views saved
+	Flush(ctx context.Context) (int, error)
+	// RunFlushJob flush the buffered views at every interval until the context is done
+	RunFlushJob(ctx context.Context)
+	// GetBlogStats get the views per day of a blog and its top referrers over the last days, today included
+	GetBlogStats(ctx context.Context, blogID uuid.UUID, days int) (*domain.BlogStats, error)
+}
@@ -0,0 +1,37 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+)
+
+type IAPIKeyRepository interface {
+	// CreateAPIKey insert a new api key into the database
+	CreateAPIKey(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error)
+	// GetAPIKeysByUserID select all api keys of a user
+	GetAPIKeysByUserID(ctx context.Context, userID uuid.UUID) ([]domain.APIKey, error)
+	// GetAPIKeyByHash select an api key by its key hash
+	GetAPIKeyByHash(ctx context.Context, hash string) (*domain.APIKey, error)
+	// UpdateLastUsed set the last time the api key was used
+	UpdateLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+	// DeleteAPIKey delete an api key of a user
+	DeleteAPIKey(ctx context.Context, userID, id uuid.UUID) error
+	// DeleteAPIKeysByUserID delete all api keys of a user
+	DeleteAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+type IAPIKeyService interface {
+	// CreateKey create a new api key, the returned key is only available here
+	CreateKey(ctx context.Context, userID uuid.UUID, name string, scopes []domain.APIKeyScope, expiresIn time.Duration) (*domain.APIKey, string, error)
+	// GetKeys get all api keys of a user
+	GetKeys(ctx context.Context, userID uuid.UUID) ([]domain.APIKey, error)
+	// RevokeKey delete an api key of a user, it can not be used anymore
+	RevokeKey(ctx context.Context, userID, id uuid.UUID) error
+	// RevokeAllKeys delete all api keys of a user
+	RevokeAllKeys(ctx context.Context, userID uuid.UUID) error
+	// VerifyKey check that the key exists, has not expired and was granted the scopes, record its use
+	VerifyKey(ctx context.Context, key string, scopes ...domain.APIKeyScope) (*domain.APIKey, error)
+}
@@ -3,11 +3,66 @@ package ports
 import (
 	"context"
 
+	"github.com/google/uuid"
 	"github.com/tommjj/go-blog-api/internal/core/domain"
 )
 
 type IAuthService interface {
-	Login(ctx context.Context, username, password string) (string, error)
+	// Login check credentials, return a two-factor challenge token instead of the access token if the user enabled it
+	Login(ctx context.Context, username, password string) (*domain.LoginResult, error)
+	// LoginMFA exchange a two-factor challenge token and a TOTP or recovery code for an access token
+	LoginMFA(ctx context.Context, mfaToken, code string) (string, error)
+	// VerifyToken verify string token and check that it has not been revoked
+	VerifyToken(ctx context.Context, token string) (*domain.TokenPayload, error)
+	// VerifyAPIKey verify an api key granted the scopes and return the payload of its owner
+	VerifyAPIKey(ctx context.Context, key string, scopes ...domain.APIKeyScope) (*domain.TokenPayload, error)
+}
+
+type IPasswordResetService interface {
+	// RequestReset email a password reset link to the user of the email, do nothing if no user has it
+	RequestReset(ctx context.Context, email string) error
+	// ResetPassword set a new password using a reset token, revoke all user tokens
+	ResetPassword(ctx context.Context, token, newPassword string) error
+}
+
+type IEmailVerificationService interface {
+	// SendVerification email a verification link to the user email address
+	SendVerification(ctx context.Context, user *domain.User) error
+	// ResendVerification send a new verification link to the user by user id
+	ResendVerification(ctx context.Context, id uuid.UUID) error
+	// VerifyEmail mark the email of the token owner as verified
+	VerifyEmail(ctx context.Context, token string) error
+	// CancelVerification invalidate the verification links already sent to the user
+	CancelVerification(ctx context.Context, id uuid.UUID) error
+}
+
+type IMagicLinkService interface {
+	// RequestLink email a single-use login link to the user of the email, do nothing if no user has it
+	RequestLink(ctx context.Context, email string) error
+	// Login consume a login link token, return a two-factor challenge token instead of the access token if the user enabled it
+	Login(ctx context.Context, token string) (*domain.LoginResult, error)
+}
+
+type IMFAService interface {
+	// EnrollTOTP start the TOTP enrolment, return a new secret and its otpauth URI
+	EnrollTOTP(ctx context.Context, id uuid.UUID) (*domain.TOTPEnrollment, error)
+	// ConfirmTOTP enable TOTP with the first code of the enrolled secret, return the recovery codes
+	ConfirmTOTP(ctx context.Context, id uuid.UUID, code string) ([]string, error)
+	// DisableTOTP disable TOTP after checking the user password, delete the recovery codes
+	DisableTOTP(ctx context.Context, id uuid.UUID, password string) error
+	// RegenerateRecoveryCodes replace the recovery codes after checking a TOTP code
+	RegenerateRecoveryCodes(ctx context.Context, id uuid.UUID, code string) ([]string, error)
+	// VerifyCode check a TOTP or an unused recovery code of the user, recovery codes are consumed
+	VerifyCode(ctx context.Context, user *domain.User, code string) error
+}
+
+type IRecoveryCodeRepository interface {
+	// ReplaceCodes delete all recovery codes of the user and insert the new code hashes
+	ReplaceCodes(ctx context.Context, userID uuid.UUID, hashes []string) error
+	// ConsumeCode mark an unused recovery code as used, return domain.ErrDataNotFound if there is none
+	ConsumeCode(ctx context.Context, userID uuid.UUID, hash string) error
+	// DeleteCodes delete all recovery codes of the user
+	DeleteCodes(ctx context.Context, userID uuid.UUID) error
 }
 
 type ITokenService interface {
@@ -15,4 +70,10 @@ type ITokenService interface {
 	CreateToken(user *domain.User) (string, error)
 	// VerifyToken verify string token
 	VerifyToken(token string) (*domain.TokenPayload, error)
+	// CreateImpersonationToken create a short-lived access token of the user for an admin acting as it
+	CreateImpersonationToken(user *domain.User, impersonatorID uuid.UUID) (string, error)
+	// CreateMFAToken create a short-lived two-factor challenge token
+	CreateMFAToken(user *domain.User) (string, error)
+	// VerifyMFAToken verify a two-factor challenge token
+	VerifyMFAToken(token string) (*domain.TokenPayload, error)
 }
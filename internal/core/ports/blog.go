@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/tommjj/go-blog-api/internal/core/domain"
@@ -10,18 +11,50 @@ import (
 type IBlogRepository interface {
 	// GetBlogByID select a blog by id
 	GetBlogByID(ctx context.Context, id uuid.UUID) (*domain.Blog, error)
+	// GetBlogBySlug select a blog by its slug or a slug it had before
+	GetBlogBySlug(ctx context.Context, slug string) (*domain.Blog, error)
 	// GetBlogsByAuthorID select blogs by author id, with out blog text
 	GetBlogsByAuthorID(ctx context.Context, id uuid.UUID, skip, limit int) ([]domain.Blog, error)
 	// GetListBlogs get blogs
 	GetListBlogs(ctx context.Context, skip, limit int) ([]domain.Blog, error)
+	// GetRecentBlogs select the latest blogs with their text, newest first, only the blogs of an author
+	// unless the author id is uuid.Nil
+	GetRecentBlogs(ctx context.Context, authorID uuid.UUID, limit int) ([]domain.Blog, error)
+	// GetRecentBlogsByTag select the latest blogs with a tag with their text, newest first
+	GetRecentBlogsByTag(ctx context.Context, tag string, limit int) ([]domain.Blog, error)
+	// GetSitemapBlogs select the id, author, slug and last update of every blog out of the trash, oldest first
+	GetSitemapBlogs(ctx context.Context) ([]domain.Blog, error)
+	// GetBlogsByAuthors select the blogs of any of the authors, newest first, with out blog text,
+	// only the blogs older than the cursor unless it is nil
+	GetBlogsByAuthors(ctx context.Context, authorIDs []uuid.UUID, cursor *domain.TimelineCursor, limit int) ([]domain.Blog, error)
 	// SearchBlogsByName search blogs by name, with out blog text
 	SearchBlogsByTitle(ctx context.Context, title string, skip, limit int) ([]domain.Blog, error)
-	// CreateBlog insert an new blog into the database
+	// CreateBlog insert an new blog into the database with its first revision,
+	// the slug is made from the title if the blog has none
 	CreateBlog(ctx context.Context, blog *domain.Blog) (*domain.Blog, error)
-	// UpdateBlog update blog, only update non-zero fields by default
+	// UpdateBlog update blog and save the new content as a revision, only update non-zero fields by default,
+	// a non-zero version must be the current version of the blog or domain.ErrPreconditionFailed is returned
 	UpdateBlog(ctx context.Context, blog *domain.Blog) (*domain.Blog, error)
-	// DeleteBlog delete blog by id
-	DeleteBlog(ctx context.Context, id uuid.UUID) error
+	// UpdateBlogByMap update blog by map data and save the new content as a revision, an empty "slug"
+	// makes the slug follow the title again, a non-zero version must be the current version of the blog
+	// or domain.ErrPreconditionFailed is returned
+	UpdateBlogByMap(ctx context.Context, id uuid.UUID, version int, data *map[string]interface{}) (*domain.Blog, error)
+	// DeleteBlog move a blog to the trash, a non-zero version must be the current version of the blog
+	DeleteBlog(ctx context.Context, id uuid.UUID, version int) error
+	// GetDeletedBlogsByAuthorID select the blogs of an author in the trash, last deleted first, with out blog text,
+	// return the total number of blogs in the trash
+	GetDeletedBlogsByAuthorID(ctx context.Context, id uuid.UUID, skip, limit int) ([]domain.Blog, int, error)
+	// RestoreBlog take a blog of an author out of the trash
+	RestoreBlog(ctx context.Context, authorID, id uuid.UUID) (*domain.Blog, error)
+	// PurgeBlogs permanently delete the blogs moved to the trash before the time, return the number of deleted blogs
+	PurgeBlogs(ctx context.Context, before time.Time) (int, error)
+	// AssignSlugs give a slug to the blogs written before slugs existed, return the number of blogs
+	AssignSlugs(ctx context.Context) (int, error)
+	// GetBlogsForMetadata select the id and text of the blogs after an id, ordered by id,
+	// only the blogs without computed metadata unless all
+	GetBlogsForMetadata(ctx context.Context, after uuid.UUID, all bool, limit int) ([]domain.Blog, error)
+	// SetBlogMetadata set the metadata computed from the text of a blog, the version of the blog is unchanged
+	SetBlogMetadata(ctx context.Context, id uuid.UUID, metadata *domain.TextMetadata) error
 }
 
 type IBlogCache interface {
@@ -31,8 +64,12 @@ type IBlogCache interface {
 	SetList(ctx context.Context, skip int, limit int, list []domain.Blog) error
 	// SetSearchList
 	SetSearchList(ctx context.Context, search string, skip int, limit int, list []domain.Blog) error
+	// SetBlogSlug
+	SetBlogSlug(ctx context.Context, slug string, id uuid.UUID) error
 	// GetBlog
 	GetBlog(ctx context.Context, id uuid.UUID) (*domain.Blog, error)
+	// GetBlogIDBySlug
+	GetBlogIDBySlug(ctx context.Context, slug string) (uuid.UUID, error)
 	// GetList
 	GetList(ctx context.Context, skip int, limit int) ([]domain.Blog, error)
 	// GetSearchList
@@ -57,9 +94,44 @@ type IBlogService interface {
 	// Authorized check if user owns blog
 	Authorized(ctx context.Context, userId, blogId uuid.UUID) error
 	GetBlogByID(ctx context.Context, id uuid.UUID) (*domain.Blog, error)
+	// GetBlogBySlug get a blog by its slug or a slug it had before, the slug of the returned blog
+	// is the current one
+	GetBlogBySlug(ctx context.Context, slug string) (*domain.Blog, error)
 	GetListBlogs(ctx context.Context, skip, limit int) ([]domain.Blog, error)
+	// GetRecentBlogs get the latest blogs with their text, newest first, only the blogs of an author
+	// unless the author id is uuid.Nil
+	GetRecentBlogs(ctx context.Context, authorID uuid.UUID, limit int) ([]domain.Blog, error)
+	// GetRecentBlogsByTag get the latest blogs with a tag with their text, newest first
+	GetRecentBlogsByTag(ctx context.Context, tag string, limit int) ([]domain.Blog, error)
+	// GetSitemapBlogs get the id, author, slug and last update of every blog out of the trash, oldest first
+	GetSitemapBlogs(ctx context.Context) ([]domain.Blog, error)
+	// GetBlogsByAuthors get the blogs of any of the authors, newest first, with out blog text,
+	// only the blogs older than the cursor unless it is nil
+	GetBlogsByAuthors(ctx context.Context, authorIDs []uuid.UUID, cursor *domain.TimelineCursor, limit int) ([]domain.Blog, error)
 	SearchBlogsByTitle(ctx context.Context, title string, skip, limit int) ([]domain.Blog, error)
 	CreateBlog(ctx context.Context, blog *domain.Blog) (*domain.Blog, error)
+	// UpdateBlog update a blog, a non-zero version must be the current version of the blog
 	UpdateBlog(ctx context.Context, blog *domain.Blog) (*domain.Blog, error)
-	DeleteBlog(ctx context.Context, id uuid.UUID) error
+	// PatchBlog apply a partial update to a blog, a non-zero version must be the current version of the blog
+	PatchBlog(ctx context.Context, id uuid.UUID, version int, patch *domain.BlogPatch) (*domain.Blog, error)
+	// DeleteBlog move a blog to the trash, a non-zero version must be the current version of the blog
+	DeleteBlog(ctx context.Context, id uuid.UUID, version int) error
+	// GetTrash list the blogs of an author in the trash, return the total number of blogs in the trash
+	GetTrash(ctx context.Context, authorID uuid.UUID, skip, limit int) ([]domain.Blog, int, error)
+	// RestoreBlog take a blog of an author out of the trash
+	RestoreBlog(ctx context.Context, authorID, id uuid.UUID) (*domain.Blog, error)
+	// GetRevisions list the revisions of a blog, newest first, without text,
+	// return the total number of revisions
+	GetRevisions(ctx context.Context, blogID uuid.UUID, skip, limit int) ([]domain.BlogRevision, int, error)
+	// GetRevision get a revision of a blog by number
+	GetRevision(ctx context.Context, blogID uuid.UUID, number int) (*domain.BlogRevision, error)
+	// DiffRevisions return the line-level diff of the text of two revisions of a blog
+	DiffRevisions(ctx context.Context, blogID uuid.UUID, from, to int) (*domain.RevisionDiff, error)
+	// RestoreRevision set the content of a blog back to a revision, saved as a new revision
+	RestoreRevision(ctx context.Context, blogID uuid.UUID, number int) (*domain.Blog, error)
+	// AssignSlugs give a slug to the blogs written before slugs existed
+	AssignSlugs(ctx context.Context) error
+	// BackfillMetadata compute the excerpt, word count and reading time of the blogs written before they existed,
+	// of every blog if all, return the number of blogs
+	BackfillMetadata(ctx context.Context, all bool) (int, error)
 }
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+)
+
+// revisions are written by IBlogRepository along with the blog
+type IBlogRevisionRepository interface {
+	// GetRevisions select the revisions of a blog, newest first, without text,
+	// return the total number of revisions
+	GetRevisions(ctx context.Context, blogID uuid.UUID, skip, limit int) ([]domain.BlogRevision, int, error)
+	// GetRevision select a revision of a blog by number
+	GetRevision(ctx context.Context, blogID uuid.UUID, number int) (*domain.BlogRevision, error)
+}
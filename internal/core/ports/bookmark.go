@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+)
+
+type IBookmarkRepository interface {
+	// SaveBookmark insert a bookmark, the note of a bookmark saved again is replaced
+	SaveBookmark(ctx context.Context, bookmark *domain.Bookmark) (*domain.Bookmark, error)
+	// DeleteBookmark delete a bookmark, domain.ErrDataNotFound if the user has not saved the blog
+	DeleteBookmark(ctx context.Context, userID, blogID uuid.UUID) error
+	// GetBookmarks select the bookmarks of a user with the summary of their blogs, last saved first,
+	// the blogs in the trash are skipped
+	GetBookmarks(ctx context.Context, userID uuid.UUID, skip, limit int) ([]domain.Bookmark, int, error)
+}
+
+type IBookmarkService interface {
+	// AddBookmark save a blog to the bookmarks of a user, saving it again replaces the note
+	AddBookmark(ctx context.Context, userID, blogID uuid.UUID, note string) (*domain.Bookmark, error)
+	// RemoveBookmark remove a blog from the bookmarks of a user
+	RemoveBookmark(ctx context.Context, userID, blogID uuid.UUID) error
+	// GetBookmarks get the bookmarks of a user with the summary of their blogs, last saved first
+	GetBookmarks(ctx context.Context, userID uuid.UUID, skip, limit int) ([]domain.Bookmark, int, error)
+}
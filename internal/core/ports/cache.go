@@ -8,10 +8,18 @@ import (
 type ICacheRepository interface {
 	// Set stores the value in the cache
 	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
-	// Set stores the value in the cache with NX mode
-	SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// SetNX stores the value in the cache with NX mode, return false if the key already exists
+	SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
 	// Get retrieves the value from the cache
 	Get(ctx context.Context, key string) ([]byte, error)
+	// Incr increments the counter of the key, the ttl is only set when the counter is created
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	// IncrBy adds the value to the counter of the key, the ttl is only set when the counter is created
+	IncrBy(ctx context.Context, key string, value int64, ttl time.Duration) (int64, error)
+	// HIncrBy adds the value to the counter of a field of the hash of the key
+	HIncrBy(ctx context.Context, key, field string, value int64) (int64, error)
+	// HGetDelAll retrieves every field of the hash of the key and removes the hash in one transaction
+	HGetDelAll(ctx context.Context, key string) (map[string]string, error)
 	// Delete removes the value from the cache
 	Delete(ctx context.Context, key string) error
 	// DeleteByPrefix removes the value from the cache with the given prefix
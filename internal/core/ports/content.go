@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+)
+
+type IContentCache interface {
+	// SetRendered
+	SetRendered(ctx context.Context, hash string, content *domain.RenderedContent) error
+	// GetRendered
+	GetRendered(ctx context.Context, hash string) (*domain.RenderedContent, error)
+}
+
+type IContentService interface {
+	// Render render a markdown text to sanitized HTML with its table of contents,
+	// renderings are cached by the hash of the text
+	Render(ctx context.Context, text string) (*domain.RenderedContent, error)
+	// HighlightCSS return the stylesheet of a highlighting style for the classes of the rendered code blocks,
+	// an empty style is the default one
+	HighlightCSS(ctx context.Context, style string) (string, error)
+}
@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+)
+
+type IFeedCache interface {
+	// SetFeed
+	SetFeed(ctx context.Context, authorID uuid.UUID, tag string, feed *domain.Feed) error
+	// GetFeed
+	GetFeed(ctx context.Context, format domain.FeedFormat, authorID uuid.UUID, tag string) (*domain.Feed, error)
+	// DeleteAllFeeds remove every feed, they are generated again on the next request
+	DeleteAllFeeds(ctx context.Context) error
+}
+
+type IFeedService interface {
+	// GetSiteFeed get the feed of the latest blogs of the site, feeds are cached for a while
+	GetSiteFeed(ctx context.Context, format domain.FeedFormat) (*domain.Feed, error)
+	// GetAuthorFeed get the feed of the latest blogs of an author
+	GetAuthorFeed(ctx context.Context, format domain.FeedFormat, authorID uuid.UUID) (*domain.Feed, error)
+	// GetTagFeed get the feed of the latest blogs with a tag
+	GetTagFeed(ctx context.Context, format domain.FeedFormat, tag string) (*domain.Feed, error)
+}
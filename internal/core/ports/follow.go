@@ -0,0 +1,46 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+)
+
+type IFollowRepository interface {
+	// SaveFollow insert a follow, following an author again does nothing
+	SaveFollow(ctx context.Context, follow *domain.Follow) error
+	// DeleteFollow delete a follow, domain.ErrDataNotFound if the user does not follow the author
+	DeleteFollow(ctx context.Context, followerID, followeeID uuid.UUID) error
+	// GetFollowers select the followers of a user, last followed first, deleted users are skipped
+	GetFollowers(ctx context.Context, userID uuid.UUID, skip, limit int) ([]domain.Follow, int, error)
+	// GetFollowing select the authors a user follows, last followed first, deleted users are skipped
+	GetFollowing(ctx context.Context, userID uuid.UUID, skip, limit int) ([]domain.Follow, int, error)
+	// GetFolloweeIDs select the ids of every author a user follows
+	GetFolloweeIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	// CountFollows count the followers of a user and the authors the user follows, deleted users are skipped
+	CountFollows(ctx context.Context, userID uuid.UUID) (*domain.FollowCounts, error)
+}
+
+type ITimelineCache interface {
+	// SetTimeline cache a page of the timeline of a user, the first page if the cursor is nil
+	SetTimeline(ctx context.Context, userID uuid.UUID, cursor *domain.TimelineCursor, limit int, timeline *domain.Timeline) error
+	// GetTimeline get a cached page of the timeline of a user, the first page if the cursor is nil
+	GetTimeline(ctx context.Context, userID uuid.UUID, cursor *domain.TimelineCursor, limit int) (*domain.Timeline, error)
+	// DeleteTimelines delete the cached pages of the timeline of a user
+	DeleteTimelines(ctx context.Context, userID uuid.UUID) error
+}
+
+type IFollowService interface {
+	// Follow make a user follow an author, return the follow counts of the author
+	Follow(ctx context.Context, followerID, followeeID uuid.UUID) (*domain.FollowCounts, error)
+	// Unfollow make a user stop following an author, return the follow counts of the author
+	Unfollow(ctx context.Context, followerID, followeeID uuid.UUID) (*domain.FollowCounts, error)
+	// GetFollowers get the followers of a user, last followed first, with the total number of followers
+	GetFollowers(ctx context.Context, userID uuid.UUID, skip, limit int) ([]domain.Follow, int, error)
+	// GetFollowing get the authors a user follows, last followed first, with the total number of authors
+	GetFollowing(ctx context.Context, userID uuid.UUID, skip, limit int) ([]domain.Follow, int, error)
+	// GetTimeline get a page of the latest blogs of the authors a user follows, newest first,
+	// the page after the cursor or the first page if the cursor is nil
+	GetTimeline(ctx context.Context, userID uuid.UUID, cursor *domain.TimelineCursor, limit int) (*domain.Timeline, error)
+}
@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+)
+
+type IMailer interface {
+	// Send send an email
+	Send(ctx context.Context, mail *domain.Mail) error
+}
@@ -0,0 +1,33 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+)
+
+type IOIDCProvider interface {
+	// Name return the name of the provider used in routes
+	Name() string
+	// AuthCodeURL return the authorization URL of the provider, the verifier is sent as a S256 PKCE challenge
+	AuthCodeURL(state, nonce, verifier string) string
+	// Exchange exchange the code for tokens, verify the ID token and its nonce and return its identity
+	Exchange(ctx context.Context, code, verifier, nonce string) (*domain.ExternalIdentity, error)
+}
+
+type IUserIdentityRepository interface {
+	// CreateIdentity link an external identity to a user
+	CreateIdentity(ctx context.Context, identity *domain.UserIdentity) (*domain.UserIdentity, error)
+	// GetIdentity select an identity by provider and subject
+	GetIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error)
+}
+
+type IOIDCService interface {
+	// Providers return the names of the configured providers
+	Providers() []string
+	// Authorize start a login on the provider, return the URL to redirect the user to
+	Authorize(ctx context.Context, provider string) (string, error)
+	// Callback finish a login with the code returned by the provider, link or create the user,
+	// return a two-factor challenge token instead of the access token if the user enabled it
+	Callback(ctx context.Context, provider, state, code string) (*domain.LoginResult, error)
+}
@@ -0,0 +1,38 @@
+package ports
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+)
+
+type IPasskeyRepository interface {
+	// CreatePasskey insert a new passkey into the database
+	CreatePasskey(ctx context.Context, passkey *domain.Passkey) (*domain.Passkey, error)
+	// GetPasskeysByUserID select all passkeys of a user
+	GetPasskeysByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Passkey, error)
+	// GetPasskeyByCredentialID select a passkey by its WebAuthn credential id
+	GetPasskeyByCredentialID(ctx context.Context, credentialID []byte) (*domain.Passkey, error)
+	// UpdateSignCount store the sign count of a login, return domain.ErrDataNotFound if the stored count is not lower
+	UpdateSignCount(ctx context.Context, id uuid.UUID, signCount uint32, backupState bool, usedAt time.Time) error
+	// DeletePasskey delete a passkey of a user
+	DeletePasskey(ctx context.Context, userID, id uuid.UUID) error
+}
+
+type IPasskeyService interface {
+	// BeginRegistration start a passkey registration, return the credential creation options for the browser
+	BeginRegistration(ctx context.Context, userID uuid.UUID) (json.RawMessage, error)
+	// FinishRegistration verify the attestation response of the browser and store the passkey
+	FinishRegistration(ctx context.Context, userID uuid.UUID, name string, response []byte) (*domain.Passkey, error)
+	// GetPasskeys get all passkeys of a user
+	GetPasskeys(ctx context.Context, userID uuid.UUID) ([]domain.Passkey, error)
+	// DeletePasskey delete a passkey of a user
+	DeletePasskey(ctx context.Context, userID, id uuid.UUID) error
+	// BeginLogin start a passkey login, return the credential request options for the browser
+	BeginLogin(ctx context.Context) (json.RawMessage, error)
+	// FinishLogin verify the assertion response of the browser and return an access token
+	FinishLogin(ctx context.Context, response []byte) (string, error)
+}
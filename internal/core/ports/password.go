@@ -0,0 +1,13 @@
+package ports
+
+import "context"
+
+type IBreachedPasswordRepository interface {
+	// GetSuffixesByPrefix get breached SHA-1 hash suffixes and their counts by a 5 characters hash prefix
+	GetSuffixesByPrefix(ctx context.Context, prefix string) (map[string]int, error)
+}
+
+type IPasswordPolicy interface {
+	// Validate check password against the policy, return a *domain.ValidationError if not satisfied
+	Validate(ctx context.Context, username, password string) error
+}
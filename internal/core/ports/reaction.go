@@ -0,0 +1,46 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+)
+
+type IReactionRepository interface {
+	// AddReaction insert a reaction, false if the user had already added it
+	AddReaction(ctx context.Context, reaction *domain.BlogReaction) (bool, error)
+	// RemoveReaction delete a reaction, false if the user had not added it
+	RemoveReaction(ctx context.Context, blogID, userID uuid.UUID, kind string) (bool, error)
+	// GetReactionCounts count the reactions to the blogs, the reactions of the deleted users are not counted,
+	// blogs without reactions are missing
+	GetReactionCounts(ctx context.Context, blogIDs []uuid.UUID) (map[uuid.UUID]domain.ReactionCounts, error)
+	// ReconcileReactionCounts count the reactions of the kinds again and save the counts,
+	// the reactions of the deleted users are not counted, return the counts of every blog with reactions
+	// and empty counts for the blogs that lost their last reaction since the last reconcile
+	ReconcileReactionCounts(ctx context.Context, kinds []string) (map[uuid.UUID]domain.ReactionCounts, error)
+}
+
+type IReactionCache interface {
+	// GetCounts get the counters of the kinds of reactions to a blog, domain.ErrDataNotFound if one is missing
+	GetCounts(ctx context.Context, blogID uuid.UUID, kinds []string) (domain.ReactionCounts, error)
+	// InitCounts set the counters of a blog that are missing
+	InitCounts(ctx context.Context, blogID uuid.UUID, counts domain.ReactionCounts) error
+	// AddCount add to the counter of a kind of reaction to a blog
+	AddCount(ctx context.Context, blogID uuid.UUID, kind string, value int) (int, error)
+	// SetCounts replace the counters of a blog
+	SetCounts(ctx context.Context, blogID uuid.UUID, counts domain.ReactionCounts) error
+}
+
+type IReactionService interface {
+	// AddReaction add a reaction of a user to a blog, adding it again does nothing, return the counts of the blog
+	AddReaction(ctx context.Context, blogID, userID uuid.UUID, kind string) (domain.ReactionCounts, error)
+	// RemoveReaction remove a reaction of a user to a blog, removing it again does nothing, return the counts of the blog
+	RemoveReaction(ctx context.Context, blogID, userID uuid.UUID, kind string) (domain.ReactionCounts, error)
+	// GetCounts get the reaction counts of blogs, every kind is counted
+	GetCounts(ctx context.Context, blogIDs ...uuid.UUID) (map[uuid.UUID]domain.ReactionCounts, error)
+	// Reconcile count the reactions again, save the counts to the database and reset the counters
+	Reconcile(ctx context.Context) error
+	// RunReconcileJob reconcile the counts at every interval until the context is done
+	RunReconcileJob(ctx context.Context)
+}
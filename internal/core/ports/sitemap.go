@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+)
+
+type ISitemapCache interface {
+	// SetSitemap
+	SetSitemap(ctx context.Context, sitemap *domain.Sitemap) error
+	// GetSitemap
+	GetSitemap(ctx context.Context, page int) (*domain.Sitemap, error)
+	// DeleteAllSitemaps remove every sitemap, they are generated again on the next request
+	DeleteAllSitemaps(ctx context.Context) error
+}
+
+type ISitemapService interface {
+	// GetSitemap get a sitemap of the blogs and author pages, page 0 is /sitemap.xml: the only sitemap,
+	// or the index of the sitemaps from page 1 when the pages are more than a sitemap can hold
+	GetSitemap(ctx context.Context, page int) (*domain.Sitemap, error)
+	// GetRobots get the robots.txt of the site, pointing to the sitemap
+	GetRobots(ctx context.Context) string
+}
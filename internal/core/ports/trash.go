@@ -0,0 +1,10 @@
+package ports
+
+import "context"
+
+type ITrashService interface {
+	// Purge permanently delete the users and blogs kept in the trash longer than the retention period
+	Purge(ctx context.Context) error
+	// RunPurgeJob purge the trash at every interval until the context is done
+	RunPurgeJob(ctx context.Context)
+}
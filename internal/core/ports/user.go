@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/tommjj/go-blog-api/internal/core/domain"
@@ -12,14 +13,29 @@ type IUserRepository interface {
 	GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
 	// GetUserByName select a user by name
 	GetUserByName(ctx context.Context, name string) (*domain.User, error)
+	// GetUserByEmail select a user by email
+	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
+	// ListUsers select the users matching the filter, newest first, without password and TOTP secret,
+	// return the total number of matching users
+	ListUsers(ctx context.Context, filter domain.UserFilter, skip, limit int) ([]domain.User, int, error)
 	// CreateUser insert a new user into the database
 	CreateUser(ctx context.Context, user *domain.User) (*domain.User, error)
 	// UpdateUser update a user, only update non-zero fields by default
 	UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error)
 	// UpdateUserByMap update a user, update by map data
 	UpdateUserByMap(ctx context.Context, id uuid.UUID, data *map[string]interface{}) (*domain.User, error)
-	// DeleteUser delete a user
+	// UpdateCredentialsByMap update a user by map data and bump its token version in the same statement
+	UpdateCredentialsByMap(ctx context.Context, id uuid.UUID, data *map[string]interface{}) (*domain.User, error)
+	// DeleteUser move a user and its blogs to the trash
 	DeleteUser(ctx context.Context, id uuid.UUID) error
+	// GetDeletedUsers select the users in the trash, last deleted first, without password and TOTP secret,
+	// return the total number of users in the trash
+	GetDeletedUsers(ctx context.Context, skip, limit int) ([]domain.User, int, error)
+	// RestoreUser take a user out of the trash with the blogs deleted along with it
+	RestoreUser(ctx context.Context, id uuid.UUID) (*domain.User, error)
+	// PurgeUsers permanently delete the users moved to the trash before the time with all their data,
+	// return the number of deleted users
+	PurgeUsers(ctx context.Context, before time.Time) (int, error)
 }
 
 type IUserCache interface {
@@ -36,10 +52,15 @@ type IUserCache interface {
 type IUserService interface {
 	// GetUserByID select user by user id
 	GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
-	// CreateUser create an new user
-	CreateUser(ctx context.Context, username, password string) (*domain.User, error)
-	// UpdateUser update a user, only update non-zero fields
+	// CreateUser create an new user, email is optional
+	CreateUser(ctx context.Context, user *domain.User) (*domain.User, error)
+	// UpdateUser update a user name and email, only update non-zero fields, password is not updated,
+	// a changed email has to be verified again
 	UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error)
-	// DeleteUser delete a user
+	// PatchUser apply a partial update to the name and email of a user, a changed email has to be verified again
+	PatchUser(ctx context.Context, id uuid.UUID, patch *domain.UserPatch) (*domain.User, error)
+	// ChangePassword change user password after checking the current one, revoke all user tokens
+	ChangePassword(ctx context.Context, id uuid.UUID, currentPassword, newPassword string) error
+	// DeleteUser move a user and its blogs to the trash, they can be restored by an admin until purged
 	DeleteUser(ctx context.Context, id uuid.UUID) error
 }
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/logger"
+)
+
+type AdminService struct {
+	tk        ports.ITokenService
+	userRepo  ports.IUserRepository
+	userCache ports.IUserCache
+	auditRepo ports.IAuditLogRepository
+	resetSvc  ports.IPasswordResetService
+	sitemaps  ports.ISitemapCache
+	feeds     ports.IFeedCache
+}
+
+func NewAdminService(
+	token ports.ITokenService,
+	userRepo ports.IUserRepository,
+	userCache ports.IUserCache,
+	auditRepo ports.IAuditLogRepository,
+	passwordResetService ports.IPasswordResetService,
+	sitemapCache ports.ISitemapCache,
+	feedCache ports.IFeedCache,
+) ports.IAdminService {
+	return &AdminService{
+		tk:        token,
+		userRepo:  userRepo,
+		userCache: userCache,
+		auditRepo: auditRepo,
+		resetSvc:  passwordResetService,
+		sitemaps:  sitemapCache,
+		feeds:     feedCache,
+	}
+}
+
+func (as *AdminService) ListUsers(ctx context.Context, filter domain.UserFilter, skip, limit int) ([]domain.User, int, error) {
+	users, total, err := as.userRepo.ListUsers(ctx, filter, skip, limit)
+	if err != nil {
+		return nil, 0, domain.ErrInternal
+	}
+
+	return users, total, nil
+}
+
+func (as *AdminService) SuspendUser(ctx context.Context, adminID, id uuid.UUID, reason string) (*domain.User, error) {
+	return as.setStatus(ctx, adminID, id, domain.StatusSuspended, domain.AuditSuspendUser, reason)
+}
+
+func (as *AdminService) ReactivateUser(ctx context.Context, adminID, id uuid.UUID, reason string) (*domain.User, error) {
+	return as.setStatus(ctx, adminID, id, domain.StatusActive, domain.AuditReactivateUser, reason)
+}
+
+func (as *AdminService) ForcePasswordReset(ctx context.Context, adminID, id uuid.UUID, reason string) error {
+	user, err := as.getTarget(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if user.Email == "" {
+		return domain.ErrNoEmail
+	}
+
+	// an empty hash never matches, the user can only sign in with a password again after the reset
+	updatedUser, err := as.userRepo.UpdateCredentialsByMap(ctx, id, &map[string]interface{}{
+		"password": "",
+	})
+	if err != nil {
+		return domain.ErrInternal
+	}
+	updatedUser.Password = ""
+
+	err = as.userCache.SetUser(ctx, updatedUser)
+	logOnError(err)
+
+	as.audit(ctx, adminID, domain.AuditForcePasswordReset, id, reason)
+
+	return as.resetSvc.RequestReset(ctx, user.Email)
+}
+
+func (as *AdminService) Impersonate(ctx context.Context, adminID, id uuid.UUID, reason string) (string, error) {
+	user, err := as.getTarget(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	if user.IsSuspended() {
+		return "", domain.ErrAccountSuspended
+	}
+
+	// no token is handed out without a trace of who asked for it
+	_, err = as.auditRepo.CreateAuditLog(ctx, &domain.AuditLog{
+		ActorID:  &adminID,
+		Action:   domain.AuditImpersonateUser,
+		TargetID: id,
+		Reason:   reason,
+	})
+	if err != nil {
+		return "", domain.ErrInternal
+	}
+
+	logger.Info(fmt.Sprintf("admin %v impersonates user %v", adminID, id))
+
+	token, err := as.tk.CreateImpersonationToken(user, adminID)
+	if err != nil {
+		return "", domain.ErrInternal
+	}
+
+	return token, nil
+}
+
+func (as *AdminService) ListAuditLogs(ctx context.Context, targetID *uuid.UUID, skip, limit int) ([]domain.AuditLog, int, error) {
+	logs, total, err := as.auditRepo.ListAuditLogs(ctx, targetID, skip, limit)
+	if err != nil {
+		return nil, 0, domain.ErrInternal
+	}
+
+	return logs, total, nil
+}
+
+func (as *AdminService) ListDeletedUsers(ctx context.Context, skip, limit int) ([]domain.User, int, error) {
+	users, total, err := as.userRepo.GetDeletedUsers(ctx, skip, limit)
+	if err != nil {
+		return nil, 0, domain.ErrInternal
+	}
+
+	return users, total, nil
+}
+
+func (as *AdminService) RestoreUser(ctx context.Context, adminID, id uuid.UUID, reason string) (*domain.User, error) {
+	user, err := as.userRepo.RestoreUser(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	// the blogs of the user are back with it
+	err = as.sitemaps.DeleteAllSitemaps(ctx)
+	logOnError(err)
+	err = as.feeds.DeleteAllFeeds(ctx)
+	logOnError(err)
+
+	as.audit(ctx, adminID, domain.AuditRestoreUser, id, reason)
+
+	return user, nil
+}
+
+func (as *AdminService) PromoteAdmins(ctx context.Context, names []string) error {
+	for _, name := range names {
+		user, err := as.userRepo.GetUserByName(ctx, name)
+		if err != nil {
+			if errors.Is(err, domain.ErrDataNotFound) {
+				logger.Warn(fmt.Sprintf("admin user %v does not exist", name))
+				continue
+			}
+			return domain.ErrInternal
+		}
+
+		if user.Role == domain.RoleAdmin {
+			continue
+		}
+
+		_, err = as.userRepo.UpdateUserByMap(ctx, user.ID, &map[string]interface{}{
+			"role": domain.RoleAdmin,
+		})
+		if err != nil {
+			return domain.ErrInternal
+		}
+
+		err = as.userCache.DeleteUser(ctx, user.ID)
+		logOnError(err)
+
+		_, err = as.auditRepo.CreateAuditLog(ctx, &domain.AuditLog{
+			Action:   domain.AuditPromoteUser,
+			TargetID: user.ID,
+			Reason:   "configured admin",
+		})
+		logOnError(err)
+
+		logger.Info(fmt.Sprintf("user %v is now admin", name))
+	}
+
+	return nil
+}
+
+// setStatus change the status of a user and record it in the audit log
+func (as *AdminService) setStatus(ctx context.Context, adminID, id uuid.UUID, status domain.UserStatus, action domain.AuditAction, reason string) (*domain.User, error) {
+	user, err := as.getTarget(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Status == status {
+		return nil, domain.ErrNoUpdatedData
+	}
+
+	updatedUser, err := as.userRepo.UpdateUserByMap(ctx, id, &map[string]interface{}{
+		"status": status,
+	})
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	updatedUser.Password = ""
+
+	err = as.userCache.SetUser(ctx, updatedUser)
+	logOnError(err)
+
+	as.audit(ctx, adminID, action, id, reason)
+
+	return updatedUser, nil
+}
+
+// getTarget get the user an admin action is applied to, admins can not be targeted
+func (as *AdminService) getTarget(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	user, err := as.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if user.Role == domain.RoleAdmin {
+		return nil, domain.ErrAdminTarget
+	}
+
+	return user, nil
+}
+
+// audit record an action that has already been applied, a failure is only logged
+func (as *AdminService) audit(ctx context.Context, adminID uuid.UUID, action domain.AuditAction, targetID uuid.UUID, reason string) {
+	_, err := as.auditRepo.CreateAuditLog(ctx, &domain.AuditLog{
+		ActorID:  &adminID,
+		Action:   action,
+		TargetID: targetID,
+		Reason:   reason,
+	})
+	if err != nil {
+		logger.Error(fmt.Sprintf("audit log of %v by admin %v on user %v failed: %v", action, adminID, targetID, err))
+	}
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/logger"
+)
+
+// MaxStatsReferrers is the number of referrers in the stats of a blog
+const MaxStatsReferrers = 20
+
+// dayLayout is the layout of the UTC dates the views are counted by
+const dayLayout = "2006-01-02"
+
+// AnalyticsService count the views of the blogs in a redis buffer, written on every counted view,
+// and saves them to the daily views in the database at every flush interval
+type AnalyticsService struct {
+	bots      []string
+	siteHost  string
+	interval  time.Duration
+	batchSize int
+	repo      ports.IAnalyticsRepository
+	cache     ports.IViewCache
+}
+
+func NewAnalyticsService(conf config.Analytics, site config.Site, repo ports.IAnalyticsRepository, cache ports.IViewCache) (ports.IAnalyticsService, error) {
+	interval, err := time.ParseDuration(conf.FlushInterval)
+	if err != nil {
+		return nil, err
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("analytics flush interval must be positive: %v", interval)
+	}
+	if conf.FlushBatchSize <= 0 {
+		return nil, fmt.Errorf("analytics flush batch size must be positive: %v", conf.FlushBatchSize)
+	}
+
+	siteURL, err := url.Parse(site.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AnalyticsService{
+		bots:      conf.BotUserAgents,
+		siteHost:  strings.ToLower(siteURL.Hostname()),
+		interval:  interval,
+		batchSize: conf.FlushBatchSize,
+		repo:      repo,
+		cache:     cache,
+	}, nil
+}
+
+func (as *AnalyticsService) RecordView(ctx context.Context, view *domain.BlogView) {
+	if as.isBot(view.UserAgent) {
+		return
+	}
+
+	// the visitor is not stored, only a hash of its ip and user agent for the view window
+	hash := sha256.Sum256([]byte(view.IP + "\n" + view.UserAgent))
+	first, err := as.cache.MarkViewed(ctx, view.BlogID, hex.EncodeToString(hash[:16]))
+	if err != nil {
+		logger.Error(err.Error())
+		return
+	}
+	if !first {
+		return
+	}
+
+	err = as.cache.AddViews(ctx, domain.ViewCount{
+		BlogID:   view.BlogID,
+		Day:      view.ViewedAt.UTC().Format(dayLayout),
+		Referrer: as.referrerHost(view.Referrer),
+		Views:    1,
+	})
+	logOnError(err)
+}
+
+// isBot check if a user agent is empty or contains one of the bot user agents
+func (as *AnalyticsService) isBot(userAgent string) bool {
+	userAgent = strings.ToLower(userAgent)
+	if strings.TrimSpace(userAgent) == "" {
+		return true
+	}
+	for _, bot := range as.bots {
+		if strings.Contains(userAgent, bot) {
+			return true
+		}
+	}
+	return false
+}
+
+// referrerHost get the host a view comes from, without www., the views without referrer are direct
+// and the ones from the site itself internal
+func (as *AnalyticsService) referrerHost(referrer string) string {
+	u, err := url.Parse(referrer)
+	if err != nil || u.Hostname() == "" {
+		return domain.DirectReferrer
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == as.siteHost {
+		return domain.InternalReferrer
+	}
+	return strings.TrimPrefix(host, "www.")
+}
+
+func (as *AnalyticsService) Flush(ctx context.Context) (int, error) {
+	counts, err := as.cache.TakeViews(ctx)
+	if err != nil {
+		logger.Error(err.Error())
+		return 0, domain.ErrInternal
+	}
+	if len(counts) == 0 {
+		return 0, nil
+	}
+
+	err = as.repo.SaveViews(ctx, counts, as.batchSize)
+	if err != nil {
+		logger.Error(err.Error())
+		// the views go back to the buffer for the next flush
+		err = as.cache.AddViews(ctx, counts...)
+		logOnError(err)
+		return 0, domain.ErrInternal
+	}
+
+	views := 0
+	for _, count := range counts {
+		views += count.Views
+	}
+	logger.Info(fmt.Sprintf("analytics flushed: %v views", views))
+	return views, nil
+}
+
+func (as *AnalyticsService) RunFlushJob(ctx context.Context) {
+	ticker := time.NewTicker(as.interval)
+	defer ticker.Stop()
+
+	for {
+		// the views of a failed flush are flushed at the next tick
+		_, _ = as.Flush(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (as *AnalyticsService) GetBlogStats(ctx context.Context, blogID uuid.UUID, days int) (*domain.BlogStats, error) {
+	today := time.Now().UTC()
+	start := today.AddDate(0, 0, 1-days)
+	from, to := start.Format(dayLayout), today.Format(dayLayout)
+
+	daily, err := as.repo.GetDailyViews(ctx, blogID, from, to)
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, domain.ErrInternal
+	}
+	referrers, err := as.repo.GetReferrerViews(ctx, blogID, from, to, MaxStatsReferrers)
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, domain.ErrInternal
+	}
+
+	// the days without views are in the stats with none
+	views := map[string]int{}
+	for _, day := range daily {
+		views[day.Day] = day.Views
+	}
+
+	stats := &domain.BlogStats{
+		BlogID:    blogID,
+		From:      from,
+		To:        to,
+		Days:      make([]domain.DailyViews, 0, days),
+		Referrers: referrers,
+	}
+	for i := 0; i < days; i++ {
+		day := start.AddDate(0, 0, i).Format(dayLayout)
+		stats.Days = append(stats.Days, domain.DailyViews{Day: day, Views: views[day]})
+		stats.TotalViews += views[day]
+	}
+	return stats, nil
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/core/util"
+)
+
+const (
+	// apiKeyPrefix mark the keys of this api so secret scanners can find leaked ones
+	apiKeyPrefix = "gbk_"
+	// apiKeySize is the number of random bytes of an api key
+	apiKeySize = 32
+	// apiKeyDisplayLength is the number of leading characters of a key kept to recognize it
+	apiKeyDisplayLength = len(apiKeyPrefix) + 8
+	// apiKeyLastUsedInterval limit how often the last use of a key is written
+	apiKeyLastUsedInterval = time.Minute
+)
+
+type APIKeyService struct {
+	repo   ports.IAPIKeyRepository
+	secret string
+}
+
+func NewAPIKeyService(conf config.Auth, apiKeyRepo ports.IAPIKeyRepository) ports.IAPIKeyService {
+	return &APIKeyService{
+		repo:   apiKeyRepo,
+		secret: conf.SecretKey,
+	}
+}
+
+func (as *APIKeyService) CreateKey(ctx context.Context, userID uuid.UUID, name string, scopes []domain.APIKeyScope, expiresIn time.Duration) (*domain.APIKey, string, error) {
+	random, err := util.GenerateToken(apiKeySize)
+	if err != nil {
+		return nil, "", domain.ErrInternal
+	}
+	key := apiKeyPrefix + random
+
+	var expiresAt *time.Time
+	if expiresIn > 0 {
+		t := time.Now().Add(expiresIn)
+		expiresAt = &t
+	}
+
+	created, err := as.repo.CreateAPIKey(ctx, &domain.APIKey{
+		UserID:    userID,
+		Name:      strings.TrimSpace(name),
+		Prefix:    key[:apiKeyDisplayLength],
+		KeyHash:   util.HashToken(as.secret, key),
+		Scopes:    uniqueScopes(scopes),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, "", domain.ErrInternal
+	}
+
+	return created, key, nil
+}
+
+func (as *APIKeyService) GetKeys(ctx context.Context, userID uuid.UUID) ([]domain.APIKey, error) {
+	keys, err := as.repo.GetAPIKeysByUserID(ctx, userID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return keys, nil
+}
+
+func (as *APIKeyService) RevokeKey(ctx context.Context, userID, id uuid.UUID) error {
+	err := as.repo.DeleteAPIKey(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (as *APIKeyService) RevokeAllKeys(ctx context.Context, userID uuid.UUID) error {
+	err := as.repo.DeleteAPIKeysByUserID(ctx, userID)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (as *APIKeyService) VerifyKey(ctx context.Context, key string, scopes ...domain.APIKeyScope) (*domain.APIKey, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	apiKey, err := as.repo.GetAPIKeyByHash(ctx, util.HashToken(as.secret, key))
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, domain.ErrInvalidAPIKey
+		}
+		return nil, domain.ErrInternal
+	}
+
+	now := time.Now()
+	if apiKey.IsExpired(now) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	for _, scope := range scopes {
+		if !apiKey.HasScope(scope) {
+			return nil, domain.ErrInsufficientScope
+		}
+	}
+
+	// a busy key would write on every request otherwise
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyLastUsedInterval {
+		err = as.repo.UpdateLastUsed(ctx, apiKey.ID, now)
+		logOnError(err)
+		apiKey.LastUsedAt = &now
+	}
+
+	return apiKey, nil
+}
+
+// uniqueScopes remove duplicated scopes keeping the first occurrence
+func uniqueScopes(scopes []domain.APIKeyScope) []domain.APIKeyScope {
+	seen := make(map[domain.APIKeyScope]bool, len(scopes))
+	result := make([]domain.APIKeyScope, 0, len(scopes))
+
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+
+	return result
+}
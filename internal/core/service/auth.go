@@ -2,35 +2,110 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/config"
 	"github.com/tommjj/go-blog-api/internal/core/domain"
 	"github.com/tommjj/go-blog-api/internal/core/ports"
 	"github.com/tommjj/go-blog-api/internal/core/util"
+	"github.com/tommjj/go-blog-api/internal/logger"
 )
 
 type AuthService struct {
-	tk   ports.ITokenService
-	repo ports.IUserRepository
+	tk       ports.ITokenService
+	repo     ports.IUserRepository
+	cache    ports.IUserCache
+	mfa      ports.IMFAService
+	keys     ports.IAPIKeyService
+	attempts ports.ICacheRepository
+	// the wrong codes of a two-factor challenge and of a user are limited so the codes can not be guessed
+	challengeLimit    int
+	challengeDuration time.Duration
+	userLimit         int
+	userWindow        time.Duration
 }
 
-func NewAuthService(token ports.ITokenService, userRepo ports.IUserRepository) ports.IAuthService {
-	return &AuthService{
-		tk:   token,
-		repo: userRepo,
+func NewAuthService(conf config.Auth, token ports.ITokenService, userRepo ports.IUserRepository, userCache ports.IUserCache, mfa ports.IMFAService, apiKeys ports.IAPIKeyService, cache ports.ICacheRepository) (ports.IAuthService, error) {
+	challengeDuration, err := time.ParseDuration(conf.MFADuration)
+	if err != nil {
+		return nil, err
+	}
+	userWindow, err := time.ParseDuration(conf.MFAAttemptWindow)
+	if err != nil {
+		return nil, err
+	}
+	if conf.MFAAttemptLimit <= 0 || conf.MFAUserAttemptLimit <= 0 {
+		return nil, fmt.Errorf("mfa attempt limits must be positive: %v, %v", conf.MFAAttemptLimit, conf.MFAUserAttemptLimit)
 	}
+
+	return &AuthService{
+		tk:                token,
+		repo:              userRepo,
+		cache:             userCache,
+		mfa:               mfa,
+		keys:              apiKeys,
+		attempts:          cache,
+		challengeLimit:    conf.MFAAttemptLimit,
+		challengeDuration: challengeDuration,
+		userLimit:         conf.MFAUserAttemptLimit,
+		userWindow:        userWindow,
+	}, nil
 }
 
-func (as *AuthService) Login(ctx context.Context, username, password string) (string, error) {
+func (as *AuthService) Login(ctx context.Context, username, password string) (*domain.LoginResult, error) {
 	user, err := as.repo.GetUserByName(ctx, username)
 	if err != nil {
-		return "", domain.ErrInvalidCredentials
+		return nil, domain.ErrInvalidCredentials
 	}
 
 	err = util.ComparePassword(password, user.Password)
 	if err != nil {
-		return "", domain.ErrInvalidCredentials
+		return nil, domain.ErrInvalidCredentials
 	}
 
+	return newLoginResult(as.tk, user)
+}
+
+func (as *AuthService) LoginMFA(ctx context.Context, mfaToken, code string) (string, error) {
+	payload, err := as.tk.VerifyMFAToken(mfaToken)
+	if err != nil {
+		return "", err
+	}
+
+	user, err := as.repo.GetUserByID(ctx, payload.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return "", domain.ErrInvalidToken
+		}
+		return "", domain.ErrInternal
+	}
+
+	// the challenge dies with the credentials it was issued for
+	if user.TokenVersion != payload.Version {
+		return "", domain.ErrRevokedToken
+	}
+
+	if user.IsSuspended() {
+		return "", domain.ErrAccountSuspended
+	}
+
+	err = as.countMFAAttempt(ctx, payload)
+	if err != nil {
+		return "", err
+	}
+
+	err = as.mfa.VerifyCode(ctx, user, code)
+	if err != nil {
+		return "", err
+	}
+
+	// the wrong codes sent before do not count against the next logins of the user
+	err = as.attempts.Delete(ctx, mfaUserAttemptsKey(user.ID))
+	logOnError(err)
+
 	token, err := as.tk.CreateToken(user)
 	if err != nil {
 		return "", domain.ErrInternal
@@ -38,3 +113,128 @@ func (as *AuthService) Login(ctx context.Context, username, password string) (st
 
 	return token, nil
 }
+
+// countMFAAttempt count a code sent for a two-factor challenge before it is checked, so concurrent
+// codes are counted too. A challenge dies after its limit of codes and a user is rejected after its
+// limit over all its challenges in the window, the right code resets the count of the user
+func (as *AuthService) countMFAAttempt(ctx context.Context, payload *domain.TokenPayload) error {
+	count, err := as.attempts.Incr(ctx, mfaChallengeAttemptsKey(payload.ChallengeID), as.challengeDuration)
+	if err != nil {
+		logger.Error(err.Error())
+		return domain.ErrInternal
+	}
+	if count > int64(as.challengeLimit) {
+		return domain.ErrTooManyRequests
+	}
+
+	count, err = as.attempts.Incr(ctx, mfaUserAttemptsKey(payload.ID), as.userWindow)
+	if err != nil {
+		logger.Error(err.Error())
+		return domain.ErrInternal
+	}
+	if count > int64(as.userLimit) {
+		return domain.ErrTooManyRequests
+	}
+
+	return nil
+}
+
+func mfaChallengeAttemptsKey(challengeID string) string {
+	return fmt.Sprintf("mfaChallengeAttempts-%v", challengeID)
+}
+
+func mfaUserAttemptsKey(id uuid.UUID) string {
+	return fmt.Sprintf("mfaUserAttempts-%v", id)
+}
+
+func (as *AuthService) VerifyToken(ctx context.Context, token string) (*domain.TokenPayload, error) {
+	payload, err := as.tk.VerifyToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := as.getUser(ctx, payload.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if user.TokenVersion != payload.Version {
+		return nil, domain.ErrRevokedToken
+	}
+	if user.IsSuspended() {
+		return nil, domain.ErrAccountSuspended
+	}
+
+	// an impersonation ends when its admin is no longer one
+	if payload.ImpersonatorID != nil {
+		admin, err := as.getUser(ctx, *payload.ImpersonatorID)
+		if err != nil {
+			if errors.Is(err, domain.ErrDataNotFound) {
+				return nil, domain.ErrRevokedToken
+			}
+			return nil, domain.ErrInternal
+		}
+		if admin.Role != domain.RoleAdmin || admin.IsSuspended() {
+			return nil, domain.ErrRevokedToken
+		}
+	}
+
+	// the email may have been verified and the role changed after the token was issued
+	payload.Verified = user.EmailVerified
+	payload.Role = user.Role
+
+	return payload, nil
+}
+
+func (as *AuthService) VerifyAPIKey(ctx context.Context, key string, scopes ...domain.APIKeyScope) (*domain.TokenPayload, error) {
+	apiKey, err := as.keys.VerifyKey(ctx, key, scopes...)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := as.getUser(ctx, apiKey.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, domain.ErrInvalidAPIKey
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if user.IsSuspended() {
+		return nil, domain.ErrAccountSuspended
+	}
+
+	return &domain.TokenPayload{
+		ID:       user.ID,
+		Name:     user.Name,
+		Version:  user.TokenVersion,
+		Verified: user.EmailVerified,
+		Role:     user.Role,
+		APIKeyID: &apiKey.ID,
+	}, nil
+}
+
+// getUser get user by id from cache, fallback to the repository
+func (as *AuthService) getUser(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	user, err := as.cache.GetUser(ctx, id)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, domain.ErrDataNotFound) {
+		logger.Error(err.Error())
+	}
+
+	user, err = as.repo.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	user.Password = "" // remove password
+
+	err = as.cache.SetUser(ctx, user)
+	logOnError(err)
+
+	return user, nil
+}
@@ -2,22 +2,32 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"slices"
 
 	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/content"
 	"github.com/tommjj/go-blog-api/internal/core/domain"
 	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/core/util"
 	"github.com/tommjj/go-blog-api/internal/logger"
 )
 
 type BlogService struct {
-	repo  ports.IBlogRepository
-	cache ports.IBlogCache
+	repo      ports.IBlogRepository
+	revisions ports.IBlogRevisionRepository
+	cache     ports.IBlogCache
+	sitemaps  ports.ISitemapCache
+	feeds     ports.IFeedCache
 }
 
-func NewBlogService(blogRepository ports.IBlogRepository, cache ports.IBlogCache) ports.IBlogService {
+func NewBlogService(blogRepository ports.IBlogRepository, revisionRepository ports.IBlogRevisionRepository, cache ports.IBlogCache, sitemapCache ports.ISitemapCache, feedCache ports.IFeedCache) ports.IBlogService {
 	return &BlogService{
-		repo:  blogRepository,
-		cache: cache,
+		repo:      blogRepository,
+		revisions: revisionRepository,
+		cache:     cache,
+		sitemaps:  sitemapCache,
+		feeds:     feedCache,
 	}
 }
 
@@ -81,6 +91,50 @@ func (bs *BlogService) GetListBlogs(ctx context.Context, skip, limit int) ([]dom
 	return blogs, nil
 }
 
+func (bs *BlogService) GetRecentBlogs(ctx context.Context, authorID uuid.UUID, limit int) ([]domain.Blog, error) {
+	blogs, err := bs.repo.GetRecentBlogs(ctx, authorID, limit)
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, domain.ErrInternal
+	}
+
+	return blogs, nil
+}
+
+func (bs *BlogService) GetRecentBlogsByTag(ctx context.Context, tag string, limit int) ([]domain.Blog, error) {
+	if !isTag(tag) {
+		return nil, domain.ErrInvalidTag
+	}
+
+	blogs, err := bs.repo.GetRecentBlogsByTag(ctx, tag, limit)
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, domain.ErrInternal
+	}
+
+	return blogs, nil
+}
+
+func (bs *BlogService) GetSitemapBlogs(ctx context.Context) ([]domain.Blog, error) {
+	blogs, err := bs.repo.GetSitemapBlogs(ctx)
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, domain.ErrInternal
+	}
+
+	return blogs, nil
+}
+
+func (bs *BlogService) GetBlogsByAuthors(ctx context.Context, authorIDs []uuid.UUID, cursor *domain.TimelineCursor, limit int) ([]domain.Blog, error) {
+	blogs, err := bs.repo.GetBlogsByAuthors(ctx, authorIDs, cursor, limit)
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, domain.ErrInternal
+	}
+
+	return blogs, nil
+}
+
 func (bs *BlogService) SearchBlogsByTitle(ctx context.Context, title string, skip, limit int) ([]domain.Blog, error) {
 	var blogs []domain.Blog
 	var err error
@@ -111,7 +165,55 @@ func (bs *BlogService) SearchBlogsByTitle(ctx context.Context, title string, ski
 	return blogs, nil
 }
 
+func (bs *BlogService) GetBlogBySlug(ctx context.Context, slug string) (*domain.Blog, error) {
+	// slugs are stored in their canonical form, a non canonical slug is looked up as it
+	slug = util.Slugify(slug)
+	if slug == "" {
+		return nil, domain.ErrDataNotFound
+	}
+
+	id, err := bs.cache.GetBlogIDBySlug(ctx, slug)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			logger.Info(err.Error())
+		} else {
+			logger.Error(err.Error())
+		}
+	} else {
+		blog, err := bs.GetBlogByID(ctx, id)
+		// the cached blog may be gone and its slug used again after a purge
+		if err != domain.ErrDataNotFound {
+			return blog, err
+		}
+	}
+
+	blog, err := bs.repo.GetBlogBySlug(ctx, slug)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	err = bs.cache.SetBlogSlug(ctx, slug, blog.ID)
+	logOnError(err)
+	err = bs.cache.SetBlog(ctx, blog)
+	logOnError(err)
+
+	return blog, nil
+}
+
 func (bs *BlogService) CreateBlog(ctx context.Context, blog *domain.Blog) (*domain.Blog, error) {
+	if blog.Slug != "" && !util.IsSlug(blog.Slug) {
+		return nil, domain.ErrInvalidSlug
+	}
+	tags, err := normalizeTags(blog.Tags)
+	if err != nil {
+		return nil, err
+	}
+	blog.Tags = tags
+	setTextMetadata(blog)
+
 	newBlog, err := bs.repo.CreateBlog(ctx, blog)
 	if err != nil {
 		if err == domain.ErrDataConflict || err == domain.ErrConflictingData {
@@ -122,6 +224,10 @@ func (bs *BlogService) CreateBlog(ctx context.Context, blog *domain.Blog) (*doma
 
 	err = bs.cache.SetBlog(ctx, newBlog)
 	logOnError(err)
+	err = bs.sitemaps.DeleteAllSitemaps(ctx)
+	logOnError(err)
+	err = bs.feeds.DeleteAllFeeds(ctx)
+	logOnError(err)
 
 	return newBlog, nil
 }
@@ -142,9 +248,23 @@ func (bs *BlogService) Authorized(ctx context.Context, userId, blogId uuid.UUID)
 }
 
 func (bs *BlogService) UpdateBlog(ctx context.Context, updates *domain.Blog) (*domain.Blog, error) {
+	if updates.Slug != "" && !util.IsSlug(updates.Slug) {
+		return nil, domain.ErrInvalidSlug
+	}
+	if updates.Tags != nil {
+		tags, err := normalizeTags(updates.Tags)
+		if err != nil {
+			return nil, err
+		}
+		updates.Tags = tags
+	}
+	if updates.Text != "" {
+		setTextMetadata(updates)
+	}
+
 	updatedBlog, err := bs.repo.UpdateBlog(ctx, updates)
 	if err != nil {
-		if err == domain.ErrNoUpdatedData {
+		if err == domain.ErrNoUpdatedData || err == domain.ErrPreconditionFailed || err == domain.ErrConflictingData {
 			return nil, err
 		}
 		return nil, domain.ErrInternal
@@ -152,13 +272,66 @@ func (bs *BlogService) UpdateBlog(ctx context.Context, updates *domain.Blog) (*d
 
 	err = bs.cache.SetBlog(ctx, updatedBlog)
 	logOnError(err)
+	err = bs.sitemaps.DeleteAllSitemaps(ctx)
+	logOnError(err)
+	err = bs.feeds.DeleteAllFeeds(ctx)
+	logOnError(err)
 	return updatedBlog, nil
 }
 
-func (bs *BlogService) DeleteBlog(ctx context.Context, id uuid.UUID) error {
-	err := bs.repo.DeleteBlog(ctx, id)
+func (bs *BlogService) PatchBlog(ctx context.Context, id uuid.UUID, version int, patch *domain.BlogPatch) (*domain.Blog, error) {
+	updates := map[string]interface{}{}
+	if patch.Title != nil {
+		updates["title"] = *patch.Title
+	}
+	if patch.Text != nil {
+		metadata := content.Metadata(*patch.Text)
+		updates["text"] = *patch.Text
+		updates["excerpt"] = metadata.Excerpt
+		updates["word_count"] = metadata.WordCount
+		updates["reading_time"] = metadata.ReadingTime
+	}
+	if patch.Summary != nil {
+		updates["summary"] = *patch.Summary
+	}
+	if patch.Slug != nil {
+		if *patch.Slug != "" && !util.IsSlug(*patch.Slug) {
+			return nil, domain.ErrInvalidSlug
+		}
+		updates["slug"] = *patch.Slug
+	}
+	if patch.Tags != nil {
+		tags, err := normalizeTags(*patch.Tags)
+		if err != nil {
+			return nil, err
+		}
+		updates["tags"] = tags
+	}
+	if len(updates) == 0 {
+		return nil, domain.ErrNoUpdatedData
+	}
+
+	updatedBlog, err := bs.repo.UpdateBlogByMap(ctx, id, version, &updates)
 	if err != nil {
-		if err == domain.ErrNoUpdatedData {
+		if err == domain.ErrNoUpdatedData || err == domain.ErrPreconditionFailed || err == domain.ErrConflictingData {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	err = bs.cache.SetBlog(ctx, updatedBlog)
+	logOnError(err)
+	err = bs.sitemaps.DeleteAllSitemaps(ctx)
+	logOnError(err)
+	err = bs.feeds.DeleteAllFeeds(ctx)
+	logOnError(err)
+	return updatedBlog, nil
+}
+
+func (bs *BlogService) DeleteBlog(ctx context.Context, id uuid.UUID, version int) error {
+	err := bs.repo.DeleteBlog(ctx, id, version)
+	if err != nil {
+		if err == domain.ErrNoUpdatedData || err == domain.ErrPreconditionFailed {
 			return err
 		}
 		return domain.ErrInternal
@@ -166,6 +339,183 @@ func (bs *BlogService) DeleteBlog(ctx context.Context, id uuid.UUID) error {
 
 	err = bs.cache.DeleteBlog(ctx, id)
 	logOnError(err)
+	err = bs.sitemaps.DeleteAllSitemaps(ctx)
+	logOnError(err)
+	err = bs.feeds.DeleteAllFeeds(ctx)
+	logOnError(err)
 
 	return nil
 }
+
+func (bs *BlogService) GetTrash(ctx context.Context, authorID uuid.UUID, skip, limit int) ([]domain.Blog, int, error) {
+	blogs, total, err := bs.repo.GetDeletedBlogsByAuthorID(ctx, authorID, skip, limit)
+	if err != nil {
+		return nil, 0, domain.ErrInternal
+	}
+
+	return blogs, total, nil
+}
+
+func (bs *BlogService) RestoreBlog(ctx context.Context, authorID, id uuid.UUID) (*domain.Blog, error) {
+	blog, err := bs.repo.RestoreBlog(ctx, authorID, id)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	err = bs.cache.SetBlog(ctx, blog)
+	logOnError(err)
+	err = bs.sitemaps.DeleteAllSitemaps(ctx)
+	logOnError(err)
+	err = bs.feeds.DeleteAllFeeds(ctx)
+	logOnError(err)
+
+	return blog, nil
+}
+
+func (bs *BlogService) GetRevisions(ctx context.Context, blogID uuid.UUID, skip, limit int) ([]domain.BlogRevision, int, error) {
+	revisions, total, err := bs.revisions.GetRevisions(ctx, blogID, skip, limit)
+	if err != nil {
+		return nil, 0, domain.ErrInternal
+	}
+
+	return revisions, total, nil
+}
+
+func (bs *BlogService) GetRevision(ctx context.Context, blogID uuid.UUID, number int) (*domain.BlogRevision, error) {
+	revision, err := bs.revisions.GetRevision(ctx, blogID, number)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return revision, nil
+}
+
+func (bs *BlogService) DiffRevisions(ctx context.Context, blogID uuid.UUID, from, to int) (*domain.RevisionDiff, error) {
+	fromRevision, err := bs.GetRevision(ctx, blogID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := bs.GetRevision(ctx, blogID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.RevisionDiff{
+		From:      fromRevision.Number,
+		To:        toRevision.Number,
+		FromTitle: fromRevision.Title,
+		ToTitle:   toRevision.Title,
+		Lines:     diffLines(fromRevision.Text, toRevision.Text),
+	}, nil
+}
+
+func (bs *BlogService) RestoreRevision(ctx context.Context, blogID uuid.UUID, number int) (*domain.Blog, error) {
+	revision, err := bs.GetRevision(ctx, blogID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	// the restored content is saved as a new revision, the history is never rewritten
+	return bs.UpdateBlog(ctx, &domain.Blog{
+		ID:    blogID,
+		Title: revision.Title,
+		Text:  revision.Text,
+	})
+}
+
+func (bs *BlogService) AssignSlugs(ctx context.Context) error {
+	count, err := bs.repo.AssignSlugs(ctx)
+	if err != nil {
+		logger.Error(err.Error())
+		return domain.ErrInternal
+	}
+
+	if count > 0 {
+		// the sitemaps and feeds link to the blogs by slug
+		err = bs.sitemaps.DeleteAllSitemaps(ctx)
+		logOnError(err)
+		err = bs.feeds.DeleteAllFeeds(ctx)
+		logOnError(err)
+		logger.Info(fmt.Sprintf("slugs assigned to %v blogs", count))
+	}
+	return nil
+}
+
+// metadataBatchSize is the number of blogs read at once by BackfillMetadata
+const metadataBatchSize = 100
+
+func (bs *BlogService) BackfillMetadata(ctx context.Context, all bool) (int, error) {
+	count := 0
+	after := uuid.Nil
+	for {
+		blogs, err := bs.repo.GetBlogsForMetadata(ctx, after, all, metadataBatchSize)
+		if err != nil {
+			logger.Error(err.Error())
+			return count, domain.ErrInternal
+		}
+		if len(blogs) == 0 {
+			break
+		}
+
+		for _, blog := range blogs {
+			err := bs.repo.SetBlogMetadata(ctx, blog.ID, content.Metadata(blog.Text))
+			if err != nil {
+				logger.Error(err.Error())
+				return count, domain.ErrInternal
+			}
+			count++
+		}
+		after = blogs[len(blogs)-1].ID
+	}
+
+	// the cached blogs and lists miss the metadata
+	if count > 0 {
+		err := bs.cache.DeleteAllBlogs(ctx)
+		logOnError(err)
+		err = bs.cache.DeleteAllList(ctx)
+		logOnError(err)
+		err = bs.cache.DeleteAllSearchList(ctx)
+		logOnError(err)
+		logger.Info(fmt.Sprintf("metadata computed for %v blogs", count))
+	}
+	return count, nil
+}
+
+// setTextMetadata set the metadata computed from the text of a blog
+func setTextMetadata(blog *domain.Blog) {
+	metadata := content.Metadata(blog.Text)
+	blog.Excerpt = metadata.Excerpt
+	blog.WordCount = metadata.WordCount
+	blog.ReadingTime = metadata.ReadingTime
+}
+
+// maxTagLength is the length limit of a tag
+const maxTagLength = 32
+
+// isTag check the tag is a slug short enough to be a tag
+func isTag(tag string) bool {
+	return len(tag) <= maxTagLength && util.IsSlug(tag)
+}
+
+// normalizeTags check the tags and return them sorted without duplicates, nil stays nil
+func normalizeTags(tags []string) ([]string, error) {
+	if tags == nil {
+		return nil, nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if !isTag(tag) {
+			return nil, domain.ErrInvalidTag
+		}
+		normalized = append(normalized, tag)
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized), nil
+}
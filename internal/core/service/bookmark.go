@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/logger"
+)
+
+type BookmarkService struct {
+	repo  ports.IBookmarkRepository
+	blogs ports.IBlogService
+}
+
+func NewBookmarkService(repo ports.IBookmarkRepository, blogService ports.IBlogService) ports.IBookmarkService {
+	return &BookmarkService{
+		repo:  repo,
+		blogs: blogService,
+	}
+}
+
+func (bs *BookmarkService) AddBookmark(ctx context.Context, userID, blogID uuid.UUID, note string) (*domain.Bookmark, error) {
+	// blogs in the trash can not be saved
+	blog, err := bs.blogs.GetBlogByID(ctx, blogID)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmark, err := bs.repo.SaveBookmark(ctx, &domain.Bookmark{
+		UserID:    userID,
+		BlogID:    blogID,
+		Note:      note,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, domain.ErrInternal
+	}
+	bookmark.Blog = blog
+
+	return bookmark, nil
+}
+
+func (bs *BookmarkService) RemoveBookmark(ctx context.Context, userID, blogID uuid.UUID) error {
+	err := bs.repo.DeleteBookmark(ctx, userID, blogID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		logger.Error(err.Error())
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (bs *BookmarkService) GetBookmarks(ctx context.Context, userID uuid.UUID, skip, limit int) ([]domain.Bookmark, int, error) {
+	bookmarks, total, err := bs.repo.GetBookmarks(ctx, userID, skip, limit)
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, 0, domain.ErrInternal
+	}
+
+	return bookmarks, total, nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tommjj/go-blog-api/internal/core/content"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/logger"
+)
+
+type ContentService struct {
+	cache ports.IContentCache
+}
+
+func NewContentService(cache ports.IContentCache) ports.IContentService {
+	return &ContentService{
+		cache: cache,
+	}
+}
+
+func (cs *ContentService) Render(ctx context.Context, text string) (*domain.RenderedContent, error) {
+	// the same text always renders the same, the rendering is shared by every blog and version holding it
+	hash := content.Hash(text)
+
+	rendered, err := cs.cache.GetRendered(ctx, hash)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			logger.Info(err.Error())
+		} else {
+			logger.Error(err.Error())
+		}
+	} else {
+		return rendered, nil
+	}
+
+	rendered, err = content.Render(text)
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, domain.ErrInternal
+	}
+
+	err = cs.cache.SetRendered(ctx, hash, rendered)
+	logOnError(err)
+
+	return rendered, nil
+}
+
+func (cs *ContentService) HighlightCSS(ctx context.Context, style string) (string, error) {
+	if style == "" {
+		style = content.DefaultStyle
+	}
+
+	var css strings.Builder
+	err := content.WriteStyleCSS(&css, style)
+	if err != nil {
+		if err == content.ErrUnknownStyle {
+			return "", domain.ErrDataNotFound
+		}
+		logger.Error(err.Error())
+		return "", domain.ErrInternal
+	}
+
+	return css.String(), nil
+}
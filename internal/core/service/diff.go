@@ -0,0 +1,137 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+)
+
+// maxDiffEdits bound the work of a diff, past it the changed lines are shown
+// as a whole deletion followed by a whole insertion
+const maxDiffEdits = 2000
+
+// diffLines return the line-level diff turning oldText into newText
+func diffLines(oldText, newText string) []domain.DiffLine {
+	a, b := splitLines(oldText), splitLines(newText)
+
+	// only the middle part that changed goes through the diff
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]domain.DiffLine, 0, len(a)+len(b)-prefix-suffix)
+	lines = appendLines(lines, domain.DiffEqual, a[:prefix])
+
+	middleA, middleB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	middle, ok := myersDiff(middleA, middleB)
+	if !ok {
+		middle = appendLines(appendLines(nil, domain.DiffDelete, middleA), domain.DiffInsert, middleB)
+	}
+	lines = append(lines, middle...)
+
+	return appendLines(lines, domain.DiffEqual, a[len(a)-suffix:])
+}
+
+// myersDiff return the shortest diff of a and b with the Myers algorithm,
+// false if it needs more than maxDiffEdits edits
+func myersDiff(a, b []string) ([]domain.DiffLine, bool) {
+	n, m := len(a), len(b)
+	maxD := n + m
+	if maxD > maxDiffEdits {
+		maxD = maxDiffEdits
+	}
+
+	// v[offset+k] is the furthest x reached on diagonal k, trace[d] the part of v
+	// on diagonals -d-1..d+1 before step d, enough to walk the path back
+	offset := maxD + 1
+	v := make([]int, 2*maxD+3)
+	trace := [][]int{}
+
+	found := false
+	for d := 0; d <= maxD && !found; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		return nil, false
+	}
+
+	// walk back from the end, the lines come out in reverse order
+	reversed := make([]domain.DiffLine, 0, n+m)
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		prev := trace[d]
+		k := x - y
+		at := func(k int) int { return prev[k+d+1] }
+
+		prevK := k - 1
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, domain.DiffLine{Op: domain.DiffEqual, Text: a[x-1]})
+			x--
+			y--
+		}
+		if x == prevX {
+			reversed = append(reversed, domain.DiffLine{Op: domain.DiffInsert, Text: b[y-1]})
+			y--
+		} else {
+			reversed = append(reversed, domain.DiffLine{Op: domain.DiffDelete, Text: a[x-1]})
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		reversed = append(reversed, domain.DiffLine{Op: domain.DiffEqual, Text: a[x-1]})
+		x--
+		y--
+	}
+
+	lines := make([]domain.DiffLine, len(reversed))
+	for i := range reversed {
+		lines[i] = reversed[len(reversed)-1-i]
+	}
+	return lines, true
+}
+
+// splitLines split a text in lines, an empty text has no line
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
+
+func appendLines(lines []domain.DiffLine, op domain.DiffOp, texts []string) []domain.DiffLine {
+	for _, text := range texts {
+		lines = append(lines, domain.DiffLine{Op: op, Text: text})
+	}
+	return lines
+}
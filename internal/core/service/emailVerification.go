@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/mail"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/core/util"
+	"github.com/tommjj/go-blog-api/internal/logger"
+)
+
+// verificationTokenSize is the number of random bytes of an email verification token
+const verificationTokenSize = 32
+
+type EmailVerificationService struct {
+	userRepo  ports.IUserRepository
+	userCache ports.IUserCache
+	tokenRepo ports.IActionTokenRepository
+	mailer    ports.IMailer
+	secret    string
+	verifyURL string
+	duration  time.Duration
+}
+
+func NewEmailVerificationService(
+	conf config.Auth,
+	userRepo ports.IUserRepository,
+	userCache ports.IUserCache,
+	tokenRepo ports.IActionTokenRepository,
+	mailer ports.IMailer,
+) (ports.IEmailVerificationService, error) {
+	duration, err := time.ParseDuration(conf.VerificationDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EmailVerificationService{
+		userRepo:  userRepo,
+		userCache: userCache,
+		tokenRepo: tokenRepo,
+		mailer:    mailer,
+		secret:    conf.SecretKey,
+		verifyURL: conf.VerificationURL,
+		duration:  duration,
+	}, nil
+}
+
+func (es *EmailVerificationService) SendVerification(ctx context.Context, user *domain.User) error {
+	if user.Email == "" {
+		return domain.ErrNoEmail
+	}
+	if user.EmailVerified {
+		return domain.ErrEmailAlreadyVerified
+	}
+
+	// links sent for a previous address must not verify the current one
+	err := es.tokenRepo.DeleteTokensByUserID(ctx, user.ID, domain.EmailVerificationPurpose)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	token, err := util.GenerateToken(verificationTokenSize)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	_, err = es.tokenRepo.CreateToken(ctx, &domain.ActionToken{
+		UserID:    user.ID,
+		Purpose:   domain.EmailVerificationPurpose,
+		TokenHash: util.HashToken(es.secret, token),
+		ExpiresAt: time.Now().Add(es.duration),
+	})
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	verifyURL, err := addQueryParam(es.verifyURL, "token", token)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	msg, err := mail.Render(mail.EmailVerificationTemplate, user.Email, mail.EmailVerificationData{
+		Username:  user.Name,
+		VerifyURL: verifyURL,
+		ExpiresIn: es.duration.String(),
+	})
+	if err != nil {
+		logger.Error(err.Error())
+		return domain.ErrInternal
+	}
+
+	err = es.mailer.Send(ctx, msg)
+	if err != nil {
+		logger.Error(err.Error())
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (es *EmailVerificationService) ResendVerification(ctx context.Context, id uuid.UUID) error {
+	user, err := es.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	return es.SendVerification(ctx, user)
+}
+
+func (es *EmailVerificationService) VerifyEmail(ctx context.Context, token string) error {
+	verifyToken, err := es.tokenRepo.GetTokenByHash(ctx, domain.EmailVerificationPurpose, util.HashToken(es.secret, token))
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return domain.ErrInvalidActionToken
+		}
+		return domain.ErrInternal
+	}
+
+	if !verifyToken.IsUsable(time.Now()) {
+		return domain.ErrInvalidActionToken
+	}
+
+	// a link left over from a removed address must not verify a user without email
+	user, err := es.userRepo.GetUserByID(ctx, verifyToken.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return domain.ErrInvalidActionToken
+		}
+		return domain.ErrInternal
+	}
+	if user.Email == "" {
+		return domain.ErrInvalidActionToken
+	}
+
+	err = es.tokenRepo.ConsumeToken(ctx, verifyToken.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidActionToken) {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	updatedUser, err := es.userRepo.UpdateUserByMap(ctx, verifyToken.UserID, &map[string]interface{}{
+		"email_verified": true,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrNoUpdatedData) {
+			return domain.ErrInvalidActionToken
+		}
+		return domain.ErrInternal
+	}
+	updatedUser.Password = ""
+
+	err = es.userCache.SetUser(ctx, updatedUser)
+	logOnError(err)
+
+	return nil
+}
+
+func (es *EmailVerificationService) CancelVerification(ctx context.Context, id uuid.UUID) error {
+	err := es.tokenRepo.DeleteTokensByUserID(ctx, id, domain.EmailVerificationPurpose)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/tommjj/go-blog-api/internal/adapter/mailer"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite/repository"
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/auth"
+	"github.com/tommjj/go-blog-api/internal/core/cache"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/core/service"
+)
+
+var verifyURLPattern = regexp.MustCompile(`https://blog\.example\.com/verify\?token=[A-Za-z0-9_-]+`)
+
+type verificationTest struct {
+	svc      ports.IEmailVerificationService
+	users    ports.IUserService
+	userRepo ports.IUserRepository
+	smtp     *smtpServer
+	user     *domain.User
+}
+
+func newVerificationTest(t *testing.T) *verificationTest {
+	t.Helper()
+
+	db := newTestDB(t)
+	smtp := newSMTPServer(t)
+	mem := newMemoryCache()
+
+	smtpMailer, err := mailer.NewSMTPMailer(config.Mail{
+		From:     "Go Blog <no-reply@blog.example.com>",
+		SMTPHost: "127.0.0.1",
+		SMTPPort: smtp.port(),
+	})
+	if err != nil {
+		t.Fatalf("create mailer: %v", err)
+	}
+
+	userRepo := repository.NewUserRepository(db)
+	userCache := cache.NewUserCache(mem, time.Hour)
+	svc, err := service.NewEmailVerificationService(
+		config.Auth{
+			SecretKey:            "test-secret",
+			VerificationDuration: "24h",
+			VerificationURL:      "https://blog.example.com/verify",
+		},
+		userRepo,
+		userCache,
+		repository.NewActionTokenRepository(db),
+		smtpMailer,
+	)
+	if err != nil {
+		t.Fatalf("create service: %v", err)
+	}
+
+	users := service.NewUserService(
+		userRepo,
+		userCache,
+		auth.NewPasswordPolicy(config.Password{MinLength: 8}, nil),
+		svc,
+		cache.NewBlogCache(mem, time.Hour, time.Hour, time.Hour),
+		cache.NewSitemapCache(mem, time.Hour),
+		cache.NewFeedCache(mem, time.Hour),
+	)
+
+	return &verificationTest{
+		svc:      svc,
+		users:    users,
+		userRepo: userRepo,
+		smtp:     smtp,
+		user:     createTestUser(t, userRepo, "alice", "alice@example.com", "Old Password 1"),
+	}
+}
+
+// changeEmail patch the email of the user of the test
+func (vt *verificationTest) changeEmail(t *testing.T, email string) {
+	t.Helper()
+
+	_, err := vt.users.PatchUser(context.Background(), vt.user.ID, &domain.UserPatch{Email: &email})
+	if err != nil {
+		t.Fatalf("change email to %q: %v", email, err)
+	}
+}
+
+func (vt *verificationTest) getUser(t *testing.T) *domain.User {
+	t.Helper()
+
+	user, err := vt.userRepo.GetUserByID(context.Background(), vt.user.ID)
+	if err != nil {
+		t.Fatalf("get user: %v", err)
+	}
+	return user
+}
+
+func TestVerificationLinkOfChangedEmail(t *testing.T) {
+	vt := newVerificationTest(t)
+	ctx := context.Background()
+
+	vt.changeEmail(t, "alice@old.example.com")
+	oldToken := linkToken(t, vt.smtp.receive(t), verifyURLPattern)
+
+	vt.changeEmail(t, "alice@new.example.com")
+	msg := vt.smtp.receive(t)
+	if len(msg.To) != 1 || msg.To[0] != "alice@new.example.com" {
+		t.Fatalf("mail to %v, want [alice@new.example.com]", msg.To)
+	}
+	newToken := linkToken(t, msg, verifyURLPattern)
+
+	err := vt.svc.VerifyEmail(ctx, oldToken)
+	if !errors.Is(err, domain.ErrInvalidActionToken) {
+		t.Fatalf("verify with the link of the previous email: %v, want %v", err, domain.ErrInvalidActionToken)
+	}
+	if vt.getUser(t).EmailVerified {
+		t.Fatal("new email verified by the link of the previous one")
+	}
+
+	if err := vt.svc.VerifyEmail(ctx, newToken); err != nil {
+		t.Fatalf("verify email: %v", err)
+	}
+	if !vt.getUser(t).EmailVerified {
+		t.Error("email not verified")
+	}
+}
+
+func TestVerificationLinkOfRemovedEmail(t *testing.T) {
+	vt := newVerificationTest(t)
+
+	vt.changeEmail(t, "alice@new.example.com")
+	token := linkToken(t, vt.smtp.receive(t), verifyURLPattern)
+
+	vt.changeEmail(t, "")
+
+	err := vt.svc.VerifyEmail(context.Background(), token)
+	if !errors.Is(err, domain.ErrInvalidActionToken) {
+		t.Fatalf("verify after the email was removed: %v, want %v", err, domain.ErrInvalidActionToken)
+	}
+
+	user := vt.getUser(t)
+	if user.Email != "" || user.EmailVerified {
+		t.Errorf("user email %q verified %v, want no email and not verified", user.Email, user.EmailVerified)
+	}
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/feeds"
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/logger"
+)
+
+type FeedService struct {
+	conf    config.Feed
+	site    config.Site
+	blogs   ports.IBlogService
+	users   ports.IUserService
+	content ports.IContentService
+	cache   ports.IFeedCache
+}
+
+func NewFeedService(conf config.Feed, site config.Site, blogService ports.IBlogService, userService ports.IUserService, contentService ports.IContentService, cache ports.IFeedCache) (ports.IFeedService, error) {
+	if conf.Size <= 0 {
+		return nil, fmt.Errorf("feed size must be positive: %v", conf.Size)
+	}
+
+	return &FeedService{
+		conf:    conf,
+		site:    site,
+		blogs:   blogService,
+		users:   userService,
+		content: contentService,
+		cache:   cache,
+	}, nil
+}
+
+func (fs *FeedService) GetSiteFeed(ctx context.Context, format domain.FeedFormat) (*domain.Feed, error) {
+	return fs.getFeed(ctx, format, uuid.Nil, "")
+}
+
+func (fs *FeedService) GetAuthorFeed(ctx context.Context, format domain.FeedFormat, authorID uuid.UUID) (*domain.Feed, error) {
+	if authorID == uuid.Nil {
+		return nil, domain.ErrDataNotFound
+	}
+	return fs.getFeed(ctx, format, authorID, "")
+}
+
+func (fs *FeedService) GetTagFeed(ctx context.Context, format domain.FeedFormat, tag string) (*domain.Feed, error) {
+	return fs.getFeed(ctx, format, uuid.Nil, tag)
+}
+
+// getFeed get the feed of the latest blogs of an author or with a tag,
+// of the site if the author id is uuid.Nil and the tag is empty
+func (fs *FeedService) getFeed(ctx context.Context, format domain.FeedFormat, authorID uuid.UUID, tag string) (*domain.Feed, error) {
+	cached, err := fs.cache.GetFeed(ctx, format, authorID, tag)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			logger.Info(err.Error())
+		} else {
+			logger.Error(err.Error())
+		}
+	} else {
+		return cached, nil
+	}
+
+	feed := &feeds.Feed{
+		Title:       fs.conf.Title,
+		Description: fs.conf.Description,
+		Link:        &feeds.Link{Href: fs.site.URL},
+	}
+	if authorID != uuid.Nil {
+		author, err := fs.users.GetUserByID(ctx, authorID)
+		if err != nil {
+			return nil, err
+		}
+		feed.Title = fmt.Sprintf("%v - %v", author.Name, fs.conf.Title)
+		feed.Link = &feeds.Link{Href: fs.site.AuthorURL + authorID.String()}
+		feed.Author = &feeds.Author{Name: author.Name}
+	}
+
+	var blogs []domain.Blog
+	if tag != "" {
+		feed.Title = fmt.Sprintf("%v - %v", tag, fs.conf.Title)
+		blogs, err = fs.blogs.GetRecentBlogsByTag(ctx, tag, fs.conf.Size)
+	} else {
+		blogs, err = fs.blogs.GetRecentBlogs(ctx, authorID, fs.conf.Size)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	authors := map[uuid.UUID]string{}
+	for _, blog := range blogs {
+		rendered, err := fs.content.Render(ctx, blog.Text)
+		if err != nil {
+			return nil, err
+		}
+
+		name, ok := authors[blog.AuthorID]
+		if !ok {
+			author, err := fs.users.GetUserByID(ctx, blog.AuthorID)
+			if err != nil {
+				return nil, err
+			}
+			name = author.Name
+			authors[blog.AuthorID] = name
+		}
+
+		// the summary of RSS and Atom is HTML, the one of JSON Feed plain text
+		summary := blog.Summary
+		if summary == "" {
+			summary = blog.Excerpt
+		}
+		if format != domain.FeedJSON {
+			summary = html.EscapeString(summary)
+		}
+
+		feed.Add(&feeds.Item{
+			Id:          "urn:uuid:" + blog.ID.String(),
+			IsPermaLink: "false",
+			Title:       blog.Title,
+			Link:        &feeds.Link{Href: fs.site.BlogURL + url.PathEscape(blog.Slug)},
+			Author:      &feeds.Author{Name: name},
+			Description: summary,
+			Content:     rendered.HTML,
+			Created:     blog.CreatedAt,
+			Updated:     blog.UpdatedAt,
+		})
+		if blog.UpdatedAt.After(feed.Updated) {
+			feed.Updated = blog.UpdatedAt
+		}
+	}
+	// a feed without blogs is as new as its document
+	if feed.Updated.IsZero() {
+		feed.Updated = time.Now()
+	}
+
+	var content string
+	switch format {
+	case domain.FeedRSS:
+		content, err = feed.ToRss()
+	case domain.FeedAtom:
+		content, err = feed.ToAtom()
+	case domain.FeedJSON:
+		content, err = feed.ToJSON()
+	default:
+		return nil, domain.ErrDataNotFound
+	}
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, domain.ErrInternal
+	}
+
+	hash := sha256.Sum256([]byte(content))
+	document := &domain.Feed{
+		Format:    format,
+		Content:   content,
+		ETag:      hex.EncodeToString(hash[:16]),
+		UpdatedAt: feed.Updated.UTC().Truncate(time.Second),
+	}
+
+	err = fs.cache.SetFeed(ctx, authorID, tag, document)
+	logOnError(err)
+
+	return document, nil
+}
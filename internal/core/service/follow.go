@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/logger"
+)
+
+type FollowService struct {
+	repo  ports.IFollowRepository
+	users ports.IUserService
+	blogs ports.IBlogService
+	cache ports.ITimelineCache
+}
+
+func NewFollowService(repo ports.IFollowRepository, userService ports.IUserService, blogService ports.IBlogService, cache ports.ITimelineCache) ports.IFollowService {
+	return &FollowService{
+		repo:  repo,
+		users: userService,
+		blogs: blogService,
+		cache: cache,
+	}
+}
+
+func (fs *FollowService) Follow(ctx context.Context, followerID, followeeID uuid.UUID) (*domain.FollowCounts, error) {
+	if followerID == followeeID {
+		return nil, domain.ErrSelfFollow
+	}
+
+	// deleted users can not be followed
+	_, err := fs.users.GetUserByID(ctx, followeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = fs.repo.SaveFollow(ctx, &domain.Follow{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, domain.ErrInternal
+	}
+
+	err = fs.cache.DeleteTimelines(ctx, followerID)
+	logOnError(err)
+
+	return fs.countFollows(ctx, followeeID)
+}
+
+func (fs *FollowService) Unfollow(ctx context.Context, followerID, followeeID uuid.UUID) (*domain.FollowCounts, error) {
+	err := fs.repo.DeleteFollow(ctx, followerID, followeeID)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		logger.Error(err.Error())
+		return nil, domain.ErrInternal
+	}
+
+	err = fs.cache.DeleteTimelines(ctx, followerID)
+	logOnError(err)
+
+	return fs.countFollows(ctx, followeeID)
+}
+
+func (fs *FollowService) countFollows(ctx context.Context, userID uuid.UUID) (*domain.FollowCounts, error) {
+	counts, err := fs.repo.CountFollows(ctx, userID)
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, domain.ErrInternal
+	}
+
+	return counts, nil
+}
+
+func (fs *FollowService) GetFollowers(ctx context.Context, userID uuid.UUID, skip, limit int) ([]domain.Follow, int, error) {
+	return fs.getFollows(ctx, fs.repo.GetFollowers, userID, skip, limit)
+}
+
+func (fs *FollowService) GetFollowing(ctx context.Context, userID uuid.UUID, skip, limit int) ([]domain.Follow, int, error) {
+	return fs.getFollows(ctx, fs.repo.GetFollowing, userID, skip, limit)
+}
+
+// getFollows get a page of follows of a user who is not deleted
+func (fs *FollowService) getFollows(ctx context.Context,
+	get func(ctx context.Context, userID uuid.UUID, skip, limit int) ([]domain.Follow, int, error),
+	userID uuid.UUID, skip, limit int,
+) ([]domain.Follow, int, error) {
+	_, err := fs.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	follows, total, err := get(ctx, userID, skip, limit)
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, 0, domain.ErrInternal
+	}
+
+	return follows, total, nil
+}
+
+// GetTimeline read the timeline on request from the blogs of the followed authors,
+// the pages are cached until the user follows or unfollows an author
+func (fs *FollowService) GetTimeline(ctx context.Context, userID uuid.UUID, cursor *domain.TimelineCursor, limit int) (*domain.Timeline, error) {
+	cached, err := fs.cache.GetTimeline(ctx, userID, cursor, limit)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			logger.Info(err.Error())
+		} else {
+			logger.Error(err.Error())
+		}
+	} else {
+		return cached, nil
+	}
+
+	authorIDs, err := fs.repo.GetFolloweeIDs(ctx, userID)
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, domain.ErrInternal
+	}
+
+	timeline := &domain.Timeline{Blogs: []domain.Blog{}}
+	if len(authorIDs) > 0 {
+		// one more blog than the page tells if there is a next page
+		blogs, err := fs.blogs.GetBlogsByAuthors(ctx, authorIDs, cursor, limit+1)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(blogs) > limit {
+			blogs = blogs[:limit]
+			last := blogs[limit-1]
+			timeline.Next = &domain.TimelineCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+		}
+		timeline.Blogs = blogs
+	}
+
+	err = fs.cache.SetTimeline(ctx, userID, cursor, limit, timeline)
+	logOnError(err)
+
+	return timeline, nil
+}
@@ -1,6 +1,13 @@
 package service
 
-import "github.com/tommjj/go-blog-api/internal/logger"
+import (
+	"net/url"
+	"strings"
+
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/logger"
+)
 
 // logOnError log if error not nil, use logger.Error
 func logOnError(err error) {
@@ -8,3 +15,51 @@ func logOnError(err error) {
 		logger.Error(err.Error())
 	}
 }
+
+// addQueryParam add a query param to rawURL
+func addQueryParam(rawURL, key, value string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	query.Set(key, value)
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// normalizeEmail trim and lowercase email so the unique index is case insensitive
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// newLoginResult create the result of a first factor login, a two-factor challenge token
+// is returned instead of the access token if the user enabled it
+func newLoginResult(tk ports.ITokenService, user *domain.User) (*domain.LoginResult, error) {
+	if user.IsSuspended() {
+		return nil, domain.ErrAccountSuspended
+	}
+
+	if user.TOTPEnabled {
+		mfaToken, err := tk.CreateMFAToken(user)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+
+		return &domain.LoginResult{
+			MFARequired: true,
+			MFAToken:    mfaToken,
+		}, nil
+	}
+
+	token, err := tk.CreateToken(user)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return &domain.LoginResult{
+		Token: token,
+	}, nil
+}
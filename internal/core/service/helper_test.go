@@ -0,0 +1,214 @@
+package service_test
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite"
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/auth"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/core/util"
+)
+
+// newTestDB open a migrated database in a file removed with the test
+func newTestDB(t *testing.T) *sqlite.DB {
+	t.Helper()
+
+	db, err := sqlite.New(config.DB{FileName: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() {
+		if conn, err := db.DB.DB(); err == nil {
+			conn.Close()
+		}
+	})
+	return db
+}
+
+// newTestTokenService create a token service signing with a test secret
+func newTestTokenService(t *testing.T) ports.ITokenService {
+	t.Helper()
+
+	tk, err := auth.NewJWTTokenService(config.Auth{
+		SecretKey:             "test-secret",
+		Duration:              "1h",
+		MFADuration:           "5m",
+		ImpersonationDuration: "15m",
+	})
+	if err != nil {
+		t.Fatalf("create token service: %v", err)
+	}
+	return tk
+}
+
+// createTestUser insert a user with a hashed password
+func createTestUser(t *testing.T, repo ports.IUserRepository, name, email, password string) *domain.User {
+	t.Helper()
+
+	hash, err := util.HashPassword(password)
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	user, err := repo.CreateUser(context.Background(), &domain.User{
+		Name:          name,
+		Email:         email,
+		EmailVerified: email != "",
+		Password:      hash,
+	})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+// memoryCache implement ports.ICacheRepository in memory, the ttl are kept to expire the values
+type memoryCache struct {
+	mu      sync.Mutex
+	values  map[string][]byte
+	hashes  map[string]map[string]int64
+	expires map[string]time.Time
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{
+		values:  map[string][]byte{},
+		hashes:  map[string]map[string]int64{},
+		expires: map[string]time.Time{},
+	}
+}
+
+// get return a value that has not expired, the caller holds the lock
+func (mc *memoryCache) get(key string) ([]byte, bool) {
+	if expires, ok := mc.expires[key]; ok && !time.Now().Before(expires) {
+		delete(mc.values, key)
+		delete(mc.expires, key)
+	}
+	value, ok := mc.values[key]
+	return value, ok
+}
+
+// set store a value, the caller holds the lock
+func (mc *memoryCache) set(key string, value []byte, ttl time.Duration) {
+	mc.values[key] = value
+	if ttl > 0 {
+		mc.expires[key] = time.Now().Add(ttl)
+	} else {
+		delete(mc.expires, key)
+	}
+}
+
+func (mc *memoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	mc.set(key, value, ttl)
+	return nil
+}
+
+func (mc *memoryCache) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if _, ok := mc.get(key); ok {
+		return false, nil
+	}
+	mc.set(key, value, ttl)
+	return true, nil
+}
+
+func (mc *memoryCache) Get(ctx context.Context, key string) ([]byte, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	value, ok := mc.get(key)
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	return value, nil
+}
+
+func (mc *memoryCache) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	value, ok := mc.get(key)
+	if !ok {
+		mc.set(key, []byte("0"), ttl)
+	}
+	count, _ := strconv.ParseInt(string(value), 10, 64)
+	count++
+	mc.values[key] = []byte(strconv.FormatInt(count, 10))
+	return count, nil
+}
+
+func (mc *memoryCache) IncrBy(ctx context.Context, key string, value int64, ttl time.Duration) (int64, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	current, ok := mc.get(key)
+	if !ok {
+		mc.set(key, []byte("0"), ttl)
+	}
+	count, _ := strconv.ParseInt(string(current), 10, 64)
+	count += value
+	mc.values[key] = []byte(strconv.FormatInt(count, 10))
+	return count, nil
+}
+
+func (mc *memoryCache) HIncrBy(ctx context.Context, key, field string, value int64) (int64, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if mc.hashes[key] == nil {
+		mc.hashes[key] = map[string]int64{}
+	}
+	mc.hashes[key][field] += value
+	return mc.hashes[key][field], nil
+}
+
+func (mc *memoryCache) HGetDelAll(ctx context.Context, key string) (map[string]string, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	fields := map[string]string{}
+	for field, value := range mc.hashes[key] {
+		fields[field] = strconv.FormatInt(value, 10)
+	}
+	delete(mc.hashes, key)
+	return fields, nil
+}
+
+func (mc *memoryCache) Delete(ctx context.Context, key string) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	delete(mc.values, key)
+	delete(mc.expires, key)
+	return nil
+}
+
+func (mc *memoryCache) DeleteByPrefix(ctx context.Context, prefix string) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	prefix = strings.TrimSuffix(prefix, "*")
+	for key := range mc.values {
+		if strings.HasPrefix(key, prefix) {
+			delete(mc.values, key)
+			delete(mc.expires, key)
+		}
+	}
+	return nil
+}
+
+func (mc *memoryCache) Close() error {
+	return nil
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/mail"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/core/util"
+	"github.com/tommjj/go-blog-api/internal/logger"
+)
+
+// magicLinkTokenSize is the number of random bytes of a login link token
+const magicLinkTokenSize = 32
+
+type MagicLinkService struct {
+	tk        ports.ITokenService
+	userRepo  ports.IUserRepository
+	userCache ports.IUserCache
+	tokenRepo ports.IActionTokenRepository
+	cache     ports.ICacheRepository
+	mailer    ports.IMailer
+	secret    string
+	loginURL  string
+	duration  time.Duration
+	limit     int
+	window    time.Duration
+}
+
+func NewMagicLinkService(
+	conf config.Auth,
+	token ports.ITokenService,
+	userRepo ports.IUserRepository,
+	userCache ports.IUserCache,
+	tokenRepo ports.IActionTokenRepository,
+	cache ports.ICacheRepository,
+	mailer ports.IMailer,
+) (ports.IMagicLinkService, error) {
+	duration, err := time.ParseDuration(conf.MagicLinkDuration)
+	if err != nil {
+		return nil, err
+	}
+	window, err := time.ParseDuration(conf.MagicLinkWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MagicLinkService{
+		tk:        token,
+		userRepo:  userRepo,
+		userCache: userCache,
+		tokenRepo: tokenRepo,
+		cache:     cache,
+		mailer:    mailer,
+		secret:    conf.SecretKey,
+		loginURL:  conf.MagicLinkURL,
+		duration:  duration,
+		limit:     conf.MagicLinkLimit,
+		window:    window,
+	}, nil
+}
+
+func (ms *MagicLinkService) RequestLink(ctx context.Context, email string) error {
+	email = normalizeEmail(email)
+
+	// the limit is checked before the lookup so registered and unknown
+	// addresses answer the same way
+	count, err := ms.cache.Incr(ctx, magicLinkLimitKey(ms.secret, email), ms.window)
+	if err != nil {
+		logger.Error(err.Error())
+		return domain.ErrInternal
+	}
+	if count > int64(ms.limit) {
+		return domain.ErrTooManyRequests
+	}
+
+	user, err := ms.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		// do not tell the caller whether the email is registered
+		if errors.Is(err, domain.ErrDataNotFound) {
+			logger.Info("magic link requested for an unknown email")
+			return nil
+		}
+		return domain.ErrInternal
+	}
+
+	// only the latest link stays valid
+	err = ms.tokenRepo.DeleteTokensByUserID(ctx, user.ID, domain.MagicLinkPurpose)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	token, err := util.GenerateToken(magicLinkTokenSize)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	_, err = ms.tokenRepo.CreateToken(ctx, &domain.ActionToken{
+		UserID:    user.ID,
+		Purpose:   domain.MagicLinkPurpose,
+		TokenHash: util.HashToken(ms.secret, token),
+		ExpiresAt: time.Now().Add(ms.duration),
+	})
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	loginURL, err := addQueryParam(ms.loginURL, "token", token)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	msg, err := mail.Render(mail.MagicLinkTemplate, user.Email, mail.MagicLinkData{
+		Username:  user.Name,
+		LoginURL:  loginURL,
+		ExpiresIn: ms.duration.String(),
+	})
+	if err != nil {
+		logger.Error(err.Error())
+		return domain.ErrInternal
+	}
+
+	// a failed delivery is not reported to the caller for the same reason as unknown emails
+	err = ms.mailer.Send(ctx, msg)
+	logOnError(err)
+
+	return nil
+}
+
+func (ms *MagicLinkService) Login(ctx context.Context, token string) (*domain.LoginResult, error) {
+	loginToken, err := ms.tokenRepo.GetTokenByHash(ctx, domain.MagicLinkPurpose, util.HashToken(ms.secret, token))
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, domain.ErrInvalidActionToken
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if !loginToken.IsUsable(time.Now()) {
+		return nil, domain.ErrInvalidActionToken
+	}
+
+	err = ms.tokenRepo.ConsumeToken(ctx, loginToken.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidActionToken) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	user, err := ms.userRepo.GetUserByID(ctx, loginToken.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, domain.ErrInvalidActionToken
+		}
+		return nil, domain.ErrInternal
+	}
+
+	// opening the link proves the user owns the address
+	if !user.EmailVerified {
+		updatedUser, err := ms.userRepo.UpdateUserByMap(ctx, user.ID, &map[string]interface{}{
+			"email_verified": true,
+		})
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+		updatedUser.Password = ""
+
+		err = ms.userCache.SetUser(ctx, updatedUser)
+		logOnError(err)
+
+		user.EmailVerified = true
+	}
+
+	// the link replaces the password, not the second factor
+	return newLoginResult(ms.tk, user)
+}
+
+// magicLinkLimitKey return the rate limit key of an address, the address itself is not stored in the cache
+func magicLinkLimitKey(secret, email string) string {
+	return fmt.Sprintf("magicLinkLimit-%v", util.HashToken(secret, email))
+}
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/auth"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/core/util"
+	"github.com/tommjj/go-blog-api/internal/logger"
+)
+
+const (
+	recoveryCodeCount    = 10
+	recoveryCodeLength   = 10                                 // characters, split in two groups
+	recoveryCodeAlphabet = "0123456789abcdefghjkmnpqrstvwxyz" // 32 characters without look-alikes
+
+	// totpStepTTL is how long a used TOTP step is remembered to reject replays,
+	// longer than the accepted skew window
+	totpStepTTL = 2 * time.Minute
+)
+
+type MFAService struct {
+	userRepo  ports.IUserRepository
+	userCache ports.IUserCache
+	codeRepo  ports.IRecoveryCodeRepository
+	cache     ports.ICacheRepository
+	secret    string
+	issuer    string
+}
+
+func NewMFAService(
+	conf config.Auth,
+	userRepo ports.IUserRepository,
+	userCache ports.IUserCache,
+	codeRepo ports.IRecoveryCodeRepository,
+	cache ports.ICacheRepository,
+) ports.IMFAService {
+	return &MFAService{
+		userRepo:  userRepo,
+		userCache: userCache,
+		codeRepo:  codeRepo,
+		cache:     cache,
+		secret:    conf.SecretKey,
+		issuer:    conf.TOTPIssuer,
+	}
+}
+
+func (ms *MFAService) EnrollTOTP(ctx context.Context, id uuid.UUID) (*domain.TOTPEnrollment, error) {
+	user, err := ms.getUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	// the secret is stored disabled until the user proves the app has it
+	err = ms.updateUser(ctx, id, map[string]interface{}{
+		"totp_secret":  secret,
+		"totp_enabled": false,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TOTPEnrollment{
+		Secret: secret,
+		URI:    auth.TOTPURI(ms.issuer, user.Name, secret),
+	}, nil
+}
+
+func (ms *MFAService) ConfirmTOTP(ctx context.Context, id uuid.UUID, code string) ([]string, error) {
+	user, err := ms.getUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, domain.ErrMFANotEnrolled
+	}
+
+	err = ms.verifyTOTP(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := ms.replaceRecoveryCodes(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ms.updateUser(ctx, id, map[string]interface{}{
+		"totp_enabled": true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (ms *MFAService) DisableTOTP(ctx context.Context, id uuid.UUID, password string) error {
+	user, err := ms.getUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !user.TOTPEnabled {
+		return domain.ErrMFANotEnabled
+	}
+
+	err = util.ComparePassword(password, user.Password)
+	if err != nil {
+		return domain.ErrIncorrectPassword
+	}
+
+	err = ms.updateUser(ctx, id, map[string]interface{}{
+		"totp_secret":  "",
+		"totp_enabled": false,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = ms.codeRepo.DeleteCodes(ctx, id)
+	logOnError(err)
+
+	return nil
+}
+
+func (ms *MFAService) RegenerateRecoveryCodes(ctx context.Context, id uuid.UUID, code string) ([]string, error) {
+	user, err := ms.getUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.TOTPEnabled {
+		return nil, domain.ErrMFANotEnabled
+	}
+
+	err = ms.verifyTOTP(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return ms.replaceRecoveryCodes(ctx, id)
+}
+
+func (ms *MFAService) VerifyCode(ctx context.Context, user *domain.User, code string) error {
+	if !user.TOTPEnabled {
+		return domain.ErrMFANotEnabled
+	}
+
+	err := ms.verifyTOTP(ctx, user, code)
+	if err == nil || !errors.Is(err, domain.ErrInvalidMFACode) {
+		return err
+	}
+
+	err = ms.codeRepo.ConsumeCode(ctx, user.ID, ms.hashRecoveryCode(code))
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return domain.ErrInvalidMFACode
+		}
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// verifyTOTP check the code against the user secret, a code can only be used once
+func (ms *MFAService) verifyTOTP(ctx context.Context, user *domain.User, code string) error {
+	step, ok := auth.ValidateTOTP(user.TOTPSecret, code, time.Now())
+	if !ok {
+		return domain.ErrInvalidMFACode
+	}
+
+	// the codes of steps older than the last used one are rejected too
+	key := fmt.Sprintf("totpStep-%v", user.ID)
+	lastStep := int64(-1)
+	last, err := ms.cache.Get(ctx, key)
+	if err == nil {
+		lastStep, err = strconv.ParseInt(string(last), 10, 64)
+		if err != nil {
+			lastStep = -1
+		}
+	} else if !errors.Is(err, domain.ErrDataNotFound) {
+		logger.Error(err.Error())
+		return domain.ErrInvalidMFACode
+	}
+	if step < lastStep {
+		return domain.ErrInvalidMFACode
+	}
+
+	// the step is claimed atomically so concurrent requests with the same code can not both pass,
+	// a step that can not be claimed is not accepted
+	claimed, err := ms.cache.SetNX(ctx, fmt.Sprintf("%v-%v", key, step), []byte("1"), totpStepTTL)
+	if err != nil {
+		logger.Error(err.Error())
+		return domain.ErrInvalidMFACode
+	}
+	if !claimed {
+		return domain.ErrInvalidMFACode
+	}
+
+	if step > lastStep {
+		err = ms.cache.Set(ctx, key, []byte(strconv.FormatInt(step, 10)), totpStepTTL)
+		logOnError(err)
+	}
+
+	return nil
+}
+
+// replaceRecoveryCodes generate new recovery codes, only their hashes are stored
+func (ms *MFAService) replaceRecoveryCodes(ctx context.Context, id uuid.UUID) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+
+		codes = append(codes, code)
+		hashes = append(hashes, ms.hashRecoveryCode(code))
+	}
+
+	err := ms.codeRepo.ReplaceCodes(ctx, id, hashes)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return codes, nil
+}
+
+// hashRecoveryCode hash a recovery code ignoring case, spaces and the group separator
+func (ms *MFAService) hashRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+	return util.HashToken(ms.secret, code)
+}
+
+// getUser get a user with its password and TOTP secret from the repository
+func (ms *MFAService) getUser(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	user, err := ms.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+	return user, nil
+}
+
+func (ms *MFAService) updateUser(ctx context.Context, id uuid.UUID, data map[string]interface{}) error {
+	updatedUser, err := ms.userRepo.UpdateUserByMap(ctx, id, &data)
+	if err != nil {
+		if errors.Is(err, domain.ErrNoUpdatedData) {
+			return domain.ErrDataNotFound
+		}
+		return domain.ErrInternal
+	}
+	updatedUser.Password = ""
+
+	err = ms.userCache.SetUser(ctx, updatedUser)
+	logOnError(err)
+
+	return nil
+}
+
+// generateRecoveryCode return a random code formatted as "xxxxx-xxxxx"
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for i, v := range b {
+		if i == recoveryCodeLength/2 {
+			sb.WriteByte('-')
+		}
+		sb.WriteByte(recoveryCodeAlphabet[int(v)%len(recoveryCodeAlphabet)])
+	}
+
+	return sb.String(), nil
+}
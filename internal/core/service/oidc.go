@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/core/util"
+	"github.com/tommjj/go-blog-api/internal/logger"
+)
+
+const (
+	// oidcTokenSize is the number of random bytes of the state, nonce and PKCE verifier
+	oidcTokenSize = 32
+
+	// provisioned usernames fit the 24 characters column with a "-NNNN" suffix
+	usernameBaseLength   = 19
+	usernameMinLength    = 3
+	usernameSuffixTries  = 10
+	usernameFallbackBase = "user"
+)
+
+var usernameDisallowedChars = regexp.MustCompile(`[^a-z0-9_.-]+`)
+
+// oidcState is what is kept in the cache between the redirect to the provider and the callback
+type oidcState struct {
+	Provider string `json:"provider"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+type OIDCService struct {
+	providers    map[string]ports.IOIDCProvider
+	tk           ports.ITokenService
+	userRepo     ports.IUserRepository
+	identityRepo ports.IUserIdentityRepository
+	cache        ports.ICacheRepository
+	duration     time.Duration
+}
+
+func NewOIDCService(
+	conf config.OIDC,
+	providers []ports.IOIDCProvider,
+	token ports.ITokenService,
+	userRepo ports.IUserRepository,
+	identityRepo ports.IUserIdentityRepository,
+	cache ports.ICacheRepository,
+) (ports.IOIDCService, error) {
+	duration, err := time.ParseDuration(conf.StateDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	providerMap := make(map[string]ports.IOIDCProvider, len(providers))
+	for _, provider := range providers {
+		providerMap[provider.Name()] = provider
+	}
+
+	return &OIDCService{
+		providers:    providerMap,
+		tk:           token,
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		cache:        cache,
+		duration:     duration,
+	}, nil
+}
+
+func (oc *OIDCService) Providers() []string {
+	names := make([]string, 0, len(oc.providers))
+	for name := range oc.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func (oc *OIDCService) Authorize(ctx context.Context, providerName string) (string, error) {
+	provider, ok := oc.providers[providerName]
+	if !ok {
+		return "", domain.ErrDataNotFound
+	}
+
+	var tokens [3]string
+	for i := range tokens {
+		token, err := util.GenerateToken(oidcTokenSize)
+		if err != nil {
+			return "", domain.ErrInternal
+		}
+		tokens[i] = token
+	}
+	state, nonce, verifier := tokens[0], tokens[1], tokens[2]
+
+	data, err := json.Marshal(oidcState{
+		Provider: providerName,
+		Nonce:    nonce,
+		Verifier: verifier,
+	})
+	if err != nil {
+		return "", domain.ErrInternal
+	}
+
+	err = oc.cache.Set(ctx, oidcStateKey(state), data, oc.duration)
+	if err != nil {
+		return "", domain.ErrInternal
+	}
+
+	return provider.AuthCodeURL(state, nonce, verifier), nil
+}
+
+func (oc *OIDCService) Callback(ctx context.Context, providerName, state, code string) (*domain.LoginResult, error) {
+	provider, ok := oc.providers[providerName]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+
+	saved, err := oc.takeState(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	if saved.Provider != providerName {
+		return nil, domain.ErrInvalidOIDCState
+	}
+
+	identity, err := provider.Exchange(ctx, code, saved.Verifier, saved.Nonce)
+	if err != nil {
+		logger.Info(fmt.Sprintf("%v login rejected: %v", providerName, err))
+		return nil, domain.ErrOIDCLoginFailed
+	}
+
+	user, err := oc.resolveUser(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	// the provider replaces the password, not the second factor
+	return newLoginResult(oc.tk, user)
+}
+
+// resolveUser find the user linked to the identity, link the account with the same verified email
+// or create a new user
+func (oc *OIDCService) resolveUser(ctx context.Context, identity *domain.ExternalIdentity) (*domain.User, error) {
+	link, err := oc.identityRepo.GetIdentity(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		user, err := oc.userRepo.GetUserByID(ctx, link.UserID)
+		if err != nil {
+			// the linked user is in the trash
+			if errors.Is(err, domain.ErrDataNotFound) {
+				return nil, domain.ErrOIDCLoginFailed
+			}
+			return nil, domain.ErrInternal
+		}
+		return user, nil
+	}
+	if !errors.Is(err, domain.ErrDataNotFound) {
+		return nil, domain.ErrInternal
+	}
+
+	email := ""
+	if identity.EmailVerified {
+		email = normalizeEmail(identity.Email)
+	}
+
+	if email != "" {
+		user, err := oc.userRepo.GetUserByEmail(ctx, email)
+		if err == nil {
+			// an unverified address may have been registered by someone else to take over
+			// the account of the real owner once they sign in with the provider
+			if !user.EmailVerified {
+				return nil, domain.ErrIdentityLinkConflict
+			}
+
+			err = oc.link(ctx, user, identity)
+			if err != nil {
+				return nil, err
+			}
+			return user, nil
+		}
+		if !errors.Is(err, domain.ErrDataNotFound) {
+			return nil, domain.ErrInternal
+		}
+	}
+
+	user, err := oc.provisionUser(ctx, identity, email)
+	if err != nil {
+		return nil, err
+	}
+
+	err = oc.link(ctx, user, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (oc *OIDCService) link(ctx context.Context, user *domain.User, identity *domain.ExternalIdentity) error {
+	_, err := oc.identityRepo.CreateIdentity(ctx, &domain.UserIdentity{
+		UserID:   user.ID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	})
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// provisionUser create a user without password, a password can be set later with a password reset
+func (oc *OIDCService) provisionUser(ctx context.Context, identity *domain.ExternalIdentity, email string) (*domain.User, error) {
+	base := usernameBase(identity)
+
+	name := base
+	for i := 0; i <= usernameSuffixTries; i++ {
+		if i > 0 || len(name) < usernameMinLength {
+			suffix, err := rand.Int(rand.Reader, big.NewInt(10000))
+			if err != nil {
+				return nil, domain.ErrInternal
+			}
+			name = fmt.Sprintf("%v-%04d", base, suffix.Int64())
+		}
+
+		user, err := oc.userRepo.CreateUser(ctx, &domain.User{
+			Name:          name,
+			Email:         email,
+			EmailVerified: email != "",
+		})
+		if err == nil {
+			return user, nil
+		}
+		if !errors.Is(err, domain.ErrConflictingData) {
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return nil, domain.ErrInternal
+}
+
+// takeState get and delete the login state, a state can only be used once
+func (oc *OIDCService) takeState(ctx context.Context, state string) (*oidcState, error) {
+	key := oidcStateKey(state)
+
+	data, err := oc.cache.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, domain.ErrInvalidOIDCState
+		}
+		return nil, domain.ErrInternal
+	}
+
+	err = oc.cache.Delete(ctx, key)
+	logOnError(err)
+
+	saved := &oidcState{}
+	err = json.Unmarshal(data, saved)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return saved, nil
+}
+
+func oidcStateKey(state string) string {
+	return fmt.Sprintf("oidcState-%v", state)
+}
+
+// usernameBase return a username made from the first usable claim of the identity
+func usernameBase(identity *domain.ExternalIdentity) string {
+	localPart, _, _ := strings.Cut(identity.Email, "@")
+
+	for _, claim := range []string{identity.PreferredUsername, localPart, identity.Name} {
+		name := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(claim), " ", "."))
+		name = usernameDisallowedChars.ReplaceAllString(name, "")
+		if len(name) > usernameBaseLength {
+			name = name[:usernameBaseLength]
+		}
+		name = strings.Trim(name, "._-")
+
+		if len(name) >= usernameMinLength {
+			return name
+		}
+	}
+
+	return usernameFallbackBase
+}
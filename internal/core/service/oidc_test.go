@@ -0,0 +1,483 @@
+package service_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/adapter/oidc"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite/repository"
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/core/service"
+)
+
+const (
+	testOIDCClientID     = "blog-client"
+	testOIDCClientSecret = "blog-secret"
+	testOIDCRedirectURL  = "https://blog.example.com/v1/api/auth/oidc/mock/callback"
+	testOIDCKeyID        = "test-key"
+)
+
+// oidcGrant is an authorization code issued by the mock provider
+type oidcGrant struct {
+	challenge string
+	nonce     string
+	claims    jwt.MapClaims
+}
+
+// oidcMockProvider is an OpenID Connect provider listening on a local port,
+// it serves the discovery document, the keys and the token endpoint with PKCE
+type oidcMockProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu     sync.Mutex
+	grants map[string]*oidcGrant
+}
+
+func newOIDCMockProvider(t *testing.T) *oidcMockProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	op := &oidcMockProvider{
+		key:    key,
+		grants: map[string]*oidcGrant{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", op.discovery)
+	mux.HandleFunc("/keys", op.keys)
+	mux.HandleFunc("/token", op.token)
+	op.server = httptest.NewServer(mux)
+	t.Cleanup(op.server.Close)
+
+	return op
+}
+
+func (op *oidcMockProvider) issuer() string {
+	return op.server.URL
+}
+
+func (op *oidcMockProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                op.issuer(),
+		"authorization_endpoint":                op.issuer() + "/authorize",
+		"token_endpoint":                        op.issuer() + "/token",
+		"jwks_uri":                              op.issuer() + "/keys",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (op *oidcMockProvider) keys(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": testOIDCKeyID,
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(op.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(op.key.E)).Bytes()),
+		}},
+	})
+}
+
+// token exchange a code once, the verifier must match the S256 challenge of the authorization
+func (op *oidcMockProvider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != testOIDCClientID || clientSecret != testOIDCClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	op.mu.Lock()
+	grant, ok := op.grants[r.PostForm.Get("code")]
+	delete(op.grants, r.PostForm.Get("code"))
+	op.mu.Unlock()
+
+	if !ok || r.PostForm.Get("grant_type") != "authorization_code" ||
+		s256Challenge(r.PostForm.Get("code_verifier")) != grant.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   op.issuer(),
+		"aud":   testOIDCClientID,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"nonce": grant.nonce,
+	}
+	for name, value := range grant.claims {
+		claims[name] = value
+	}
+
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	idToken.Header["kid"] = testOIDCKeyID
+	signed, err := idToken.SignedString(op.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     signed,
+	})
+}
+
+// authorize play the user approving the login at the authorization URL,
+// return the state and the code the provider redirects back with
+func (op *oidcMockProvider) authorize(t *testing.T, authURL string, claims jwt.MapClaims) (string, string) {
+	t.Helper()
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parse authorization URL: %v", err)
+	}
+	if u.Scheme+"://"+u.Host+u.Path != op.issuer()+"/authorize" {
+		t.Fatalf("authorization URL %v is not the provider endpoint", authURL)
+	}
+
+	query := u.Query()
+	for name, want := range map[string]string{
+		"response_type":         "code",
+		"client_id":             testOIDCClientID,
+		"redirect_uri":          testOIDCRedirectURL,
+		"code_challenge_method": "S256",
+	} {
+		if got := query.Get(name); got != want {
+			t.Fatalf("authorization URL %v is %q, want %q", name, got, want)
+		}
+	}
+	for _, name := range []string{"state", "nonce", "code_challenge"} {
+		if query.Get(name) == "" {
+			t.Fatalf("authorization URL without %v", name)
+		}
+	}
+
+	code := uuid.NewString()
+	op.mu.Lock()
+	op.grants[code] = &oidcGrant{
+		challenge: query.Get("code_challenge"),
+		nonce:     query.Get("nonce"),
+		claims:    claims,
+	}
+	op.mu.Unlock()
+
+	return query.Get("state"), code
+}
+
+func s256Challenge(verifier string) string {
+	hash := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+type oidcTest struct {
+	svc          ports.IOIDCService
+	tk           ports.ITokenService
+	userRepo     ports.IUserRepository
+	identityRepo ports.IUserIdentityRepository
+	provider     *oidcMockProvider
+}
+
+func newOIDCTest(t *testing.T) *oidcTest {
+	t.Helper()
+
+	db := newTestDB(t)
+	provider := newOIDCMockProvider(t)
+
+	var providers []ports.IOIDCProvider
+	for _, name := range []string{"mock", "other"} {
+		p, err := oidc.New(context.Background(), config.OIDCProvider{
+			Name:         name,
+			Issuer:       provider.issuer(),
+			ClientID:     testOIDCClientID,
+			ClientSecret: testOIDCClientSecret,
+			RedirectURL:  testOIDCRedirectURL,
+			Scopes:       []string{"openid", "email", "profile"},
+		})
+		if err != nil {
+			t.Fatalf("create provider: %v", err)
+		}
+		providers = append(providers, p)
+	}
+
+	tk := newTestTokenService(t)
+	userRepo := repository.NewUserRepository(db)
+	identityRepo := repository.NewUserIdentityRepository(db)
+
+	svc, err := service.NewOIDCService(config.OIDC{StateDuration: "10m"}, providers, tk, userRepo, identityRepo, newMemoryCache())
+	if err != nil {
+		t.Fatalf("create service: %v", err)
+	}
+
+	return &oidcTest{
+		svc:          svc,
+		tk:           tk,
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		provider:     provider,
+	}
+}
+
+// login run a full login on the mock provider with the claims of the ID token
+func (ot *oidcTest) login(t *testing.T, claims jwt.MapClaims) (*domain.LoginResult, error) {
+	t.Helper()
+	ctx := context.Background()
+
+	authURL, err := ot.svc.Authorize(ctx, "mock")
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	state, code := ot.provider.authorize(t, authURL, claims)
+
+	return ot.svc.Callback(ctx, "mock", state, code)
+}
+
+// loggedInUser return the user of the access token of a login
+func (ot *oidcTest) loggedInUser(t *testing.T, result *domain.LoginResult) *domain.User {
+	t.Helper()
+
+	payload, err := ot.tk.VerifyToken(result.Token)
+	if err != nil {
+		t.Fatalf("verify token: %v", err)
+	}
+	user, err := ot.userRepo.GetUserByID(context.Background(), payload.ID)
+	if err != nil {
+		t.Fatalf("get user: %v", err)
+	}
+	return user
+}
+
+func TestOIDCLoginCreatesUser(t *testing.T) {
+	ot := newOIDCTest(t)
+	ctx := context.Background()
+
+	result, err := ot.login(t, jwt.MapClaims{
+		"sub":            "subject-1",
+		"email":          "Bob@Example.com",
+		"email_verified": true,
+		"name":           "Bob",
+	})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	user := ot.loggedInUser(t, result)
+	if user.Email != "bob@example.com" || !user.EmailVerified {
+		t.Errorf("user email %q verified %v, want a verified bob@example.com", user.Email, user.EmailVerified)
+	}
+	if user.Name != "bob" {
+		t.Errorf("user name %q, want bob", user.Name)
+	}
+
+	identity, err := ot.identityRepo.GetIdentity(ctx, "mock", "subject-1")
+	if err != nil {
+		t.Fatalf("get identity: %v", err)
+	}
+	if identity.UserID != user.ID {
+		t.Errorf("identity linked to %v, want %v", identity.UserID, user.ID)
+	}
+
+	// the linked subject signs in to the same user, whatever its email is now
+	result, err = ot.login(t, jwt.MapClaims{
+		"sub":            "subject-1",
+		"email":          "robert@example.com",
+		"email_verified": true,
+	})
+	if err != nil {
+		t.Fatalf("second login: %v", err)
+	}
+	if again := ot.loggedInUser(t, result); again.ID != user.ID {
+		t.Errorf("second login as %v, want %v", again.ID, user.ID)
+	}
+}
+
+func TestOIDCLoginLinksVerifiedEmail(t *testing.T) {
+	ot := newOIDCTest(t)
+	alice := createTestUser(t, ot.userRepo, "alice", "alice@example.com", "Old Password 1")
+
+	result, err := ot.login(t, jwt.MapClaims{
+		"sub":            "subject-1",
+		"email":          "Alice@Example.com",
+		"email_verified": true,
+	})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if user := ot.loggedInUser(t, result); user.ID != alice.ID {
+		t.Errorf("logged in as %v, want %v", user.ID, alice.ID)
+	}
+
+	identity, err := ot.identityRepo.GetIdentity(context.Background(), "mock", "subject-1")
+	if err != nil {
+		t.Fatalf("get identity: %v", err)
+	}
+	if identity.UserID != alice.ID {
+		t.Errorf("identity linked to %v, want %v", identity.UserID, alice.ID)
+	}
+}
+
+func TestOIDCLoginDoesNotLinkUnverifiedProviderEmail(t *testing.T) {
+	ot := newOIDCTest(t)
+	alice := createTestUser(t, ot.userRepo, "alice", "alice@example.com", "Old Password 1")
+
+	result, err := ot.login(t, jwt.MapClaims{
+		"sub":            "subject-1",
+		"email":          "alice@example.com",
+		"email_verified": false,
+	})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	user := ot.loggedInUser(t, result)
+	if user.ID == alice.ID {
+		t.Fatal("unverified provider email linked to the account")
+	}
+	if user.Email != "" {
+		t.Errorf("new user with email %q, want none", user.Email)
+	}
+}
+
+func TestOIDCLoginIdentityLinkConflict(t *testing.T) {
+	ot := newOIDCTest(t)
+	ctx := context.Background()
+
+	alice := createTestUser(t, ot.userRepo, "alice", "alice@example.com", "Old Password 1")
+	_, err := ot.userRepo.UpdateUserByMap(ctx, alice.ID, &map[string]interface{}{"email_verified": false})
+	if err != nil {
+		t.Fatalf("unverify email: %v", err)
+	}
+
+	_, err = ot.login(t, jwt.MapClaims{
+		"sub":            "subject-1",
+		"email":          "alice@example.com",
+		"email_verified": true,
+	})
+	if !errors.Is(err, domain.ErrIdentityLinkConflict) {
+		t.Fatalf("login: %v, want %v", err, domain.ErrIdentityLinkConflict)
+	}
+
+	_, err = ot.identityRepo.GetIdentity(ctx, "mock", "subject-1")
+	if !errors.Is(err, domain.ErrDataNotFound) {
+		t.Errorf("get identity: %v, want %v", err, domain.ErrDataNotFound)
+	}
+}
+
+func TestOIDCCallbackRejectsCodeOfAnotherVerifier(t *testing.T) {
+	ot := newOIDCTest(t)
+	ctx := context.Background()
+
+	authURL, err := ot.svc.Authorize(ctx, "mock")
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	state, _ := ot.provider.authorize(t, authURL, jwt.MapClaims{"sub": "subject-1"})
+
+	// a code injected from the login of someone else was issued for their challenge
+	otherURL, err := ot.svc.Authorize(ctx, "mock")
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	_, code := ot.provider.authorize(t, otherURL, jwt.MapClaims{"sub": "subject-2"})
+
+	_, err = ot.svc.Callback(ctx, "mock", state, code)
+	if !errors.Is(err, domain.ErrOIDCLoginFailed) {
+		t.Fatalf("callback: %v, want %v", err, domain.ErrOIDCLoginFailed)
+	}
+}
+
+func TestOIDCCallbackRejectsNonceMismatch(t *testing.T) {
+	ot := newOIDCTest(t)
+
+	_, err := ot.login(t, jwt.MapClaims{
+		"sub":   "subject-1",
+		"nonce": "nonce-of-another-login",
+	})
+	if !errors.Is(err, domain.ErrOIDCLoginFailed) {
+		t.Fatalf("login: %v, want %v", err, domain.ErrOIDCLoginFailed)
+	}
+}
+
+func TestOIDCCallbackRejectsStateMismatch(t *testing.T) {
+	ot := newOIDCTest(t)
+	ctx := context.Background()
+
+	authURL, err := ot.svc.Authorize(ctx, "mock")
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	state, code := ot.provider.authorize(t, authURL, jwt.MapClaims{"sub": "subject-1"})
+
+	_, err = ot.svc.Callback(ctx, "mock", "unknown-state", code)
+	if !errors.Is(err, domain.ErrInvalidOIDCState) {
+		t.Errorf("callback with an unknown state: %v, want %v", err, domain.ErrInvalidOIDCState)
+	}
+
+	_, err = ot.svc.Callback(ctx, "other", state, code)
+	if !errors.Is(err, domain.ErrInvalidOIDCState) {
+		t.Errorf("callback of another provider: %v, want %v", err, domain.ErrInvalidOIDCState)
+	}
+
+	// the state was used by the callback above
+	_, err = ot.svc.Callback(ctx, "mock", state, code)
+	if !errors.Is(err, domain.ErrInvalidOIDCState) {
+		t.Errorf("callback with a used state: %v, want %v", err, domain.ErrInvalidOIDCState)
+	}
+}
+
+func TestOIDCCallbackStateWorksOnce(t *testing.T) {
+	ot := newOIDCTest(t)
+	ctx := context.Background()
+
+	authURL, err := ot.svc.Authorize(ctx, "mock")
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	state, code := ot.provider.authorize(t, authURL, jwt.MapClaims{"sub": "subject-1"})
+
+	if _, err := ot.svc.Callback(ctx, "mock", state, code); err != nil {
+		t.Fatalf("callback: %v", err)
+	}
+
+	_, err = ot.svc.Callback(ctx, "mock", state, code)
+	if !errors.Is(err, domain.ErrInvalidOIDCState) {
+		t.Fatalf("second callback: %v, want %v", err, domain.ErrInvalidOIDCState)
+	}
+}
@@ -0,0 +1,399 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/logger"
+)
+
+// defaultPasskeyName is the name of a passkey registered without one
+const defaultPasskeyName = "Passkey"
+
+type PasskeyService struct {
+	wa       *webauthn.WebAuthn
+	tk       ports.ITokenService
+	userRepo ports.IUserRepository
+	repo     ports.IPasskeyRepository
+	cache    ports.ICacheRepository
+	timeout  time.Duration
+}
+
+func NewPasskeyService(
+	conf config.WebAuthn,
+	token ports.ITokenService,
+	userRepo ports.IUserRepository,
+	passkeyRepo ports.IPasskeyRepository,
+	cache ports.ICacheRepository,
+) (ports.IPasskeyService, error) {
+	timeout, err := time.ParseDuration(conf.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	// passkeys replace both the password and the second factor,
+	// so they must be discoverable and verify the user
+	wa, err := webauthn.New(&webauthn.Config{
+		RPID:          conf.RPID,
+		RPDisplayName: conf.RPDisplayName,
+		RPOrigins:     conf.RPOrigins,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			UserVerification:   protocol.VerificationRequired,
+		},
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        webauthn.TimeoutConfig{Enforce: true, Timeout: timeout, TimeoutUVD: timeout},
+			Registration: webauthn.TimeoutConfig{Enforce: true, Timeout: timeout, TimeoutUVD: timeout},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &PasskeyService{
+		wa:       wa,
+		tk:       token,
+		userRepo: userRepo,
+		repo:     passkeyRepo,
+		cache:    cache,
+		timeout:  timeout,
+	}, nil
+}
+
+func (ps *PasskeyService) BeginRegistration(ctx context.Context, userID uuid.UUID) (json.RawMessage, error) {
+	user, err := ps.getWebAuthnUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	creation, session, err := ps.wa.BeginRegistration(user, webauthn.WithExclusions(user.descriptors()))
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	err = ps.setSession(ctx, registrationSessionKey(userID), session)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := json.Marshal(creation)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return options, nil
+}
+
+func (ps *PasskeyService) FinishRegistration(ctx context.Context, userID uuid.UUID, name string, response []byte) (*domain.Passkey, error) {
+	session, err := ps.takeSession(ctx, registrationSessionKey(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return nil, domain.ErrInvalidPasskey
+	}
+
+	user, err := ps.getWebAuthnUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := ps.wa.CreateCredential(user, *session, parsed)
+	if err != nil {
+		logger.Info(fmt.Sprintf("passkey registration rejected: %v", err))
+		return nil, domain.ErrInvalidPasskey
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = defaultPasskeyName
+	}
+
+	transports := make([]string, 0, len(credential.Transport))
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+
+	passkey, err := ps.repo.CreatePasskey(ctx, &domain.Passkey{
+		UserID:          userID,
+		Name:            name,
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      transports,
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       credential.Authenticator.SignCount,
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrConflictingData) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return passkey, nil
+}
+
+func (ps *PasskeyService) GetPasskeys(ctx context.Context, userID uuid.UUID) ([]domain.Passkey, error) {
+	passkeys, err := ps.repo.GetPasskeysByUserID(ctx, userID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return passkeys, nil
+}
+
+func (ps *PasskeyService) DeletePasskey(ctx context.Context, userID, id uuid.UUID) error {
+	err := ps.repo.DeletePasskey(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (ps *PasskeyService) BeginLogin(ctx context.Context) (json.RawMessage, error) {
+	assertion, session, err := ps.wa.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired))
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	// the browser does not know who is signing in yet, the challenge is the only thing
+	// that ties the response back to its session
+	err = ps.setSession(ctx, loginSessionKey(session.Challenge), session)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := json.Marshal(assertion)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return options, nil
+}
+
+// FinishLogin verify the assertion and issue the same access token as a password login,
+// the user verification of the authenticator stands in for the second factor
+func (ps *PasskeyService) FinishLogin(ctx context.Context, response []byte) (string, error) {
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return "", domain.ErrPasskeyLoginFailed
+	}
+
+	session, err := ps.takeSession(ctx, loginSessionKey(parsed.Response.CollectedClientData.Challenge))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPasskey) {
+			return "", domain.ErrPasskeyLoginFailed
+		}
+		return "", err
+	}
+
+	var user *webAuthnUser
+	findUser := func(rawID, userHandle []byte) (webauthn.User, error) {
+		passkey, err := ps.repo.GetPasskeyByCredentialID(ctx, rawID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !bytes.Equal(passkey.UserID[:], userHandle) {
+			return nil, domain.ErrDataNotFound
+		}
+
+		found, err := ps.userRepo.GetUserByID(ctx, passkey.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		user = &webAuthnUser{user: found, passkeys: []domain.Passkey{*passkey}}
+		return user, nil
+	}
+
+	credential, err := ps.wa.ValidateDiscoverableLogin(findUser, *session, parsed)
+	if err != nil {
+		logger.Info(fmt.Sprintf("passkey login rejected: %v", err))
+		return "", domain.ErrPasskeyLoginFailed
+	}
+
+	passkey := user.passkeys[0]
+
+	if user.user.IsSuspended() {
+		return "", domain.ErrAccountSuspended
+	}
+
+	if credential.Authenticator.CloneWarning {
+		logger.Warn(fmt.Sprintf("passkey %v of user %v did not increase its sign count, the authenticator may be cloned", passkey.ID, passkey.UserID))
+		return "", domain.ErrPasskeyLoginFailed
+	}
+
+	err = ps.repo.UpdateSignCount(ctx, passkey.ID, credential.Authenticator.SignCount, credential.Flags.BackupState, time.Now())
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			// another login with the same counter got there first
+			return "", domain.ErrPasskeyLoginFailed
+		}
+		return "", domain.ErrInternal
+	}
+
+	token, err := ps.tk.CreateToken(user.user)
+	if err != nil {
+		return "", domain.ErrInternal
+	}
+
+	return token, nil
+}
+
+// getWebAuthnUser get a user with its passkeys from the repositories
+func (ps *PasskeyService) getWebAuthnUser(ctx context.Context, id uuid.UUID) (*webAuthnUser, error) {
+	user, err := ps.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	passkeys, err := ps.repo.GetPasskeysByUserID(ctx, id)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return &webAuthnUser{user: user, passkeys: passkeys}, nil
+}
+
+// setSession store the ceremony session until the browser answers
+func (ps *PasskeyService) setSession(ctx context.Context, key string, session *webauthn.SessionData) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	err = ps.cache.Set(ctx, key, data, ps.timeout)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// takeSession get and delete the ceremony session, a challenge can only be answered once
+func (ps *PasskeyService) takeSession(ctx context.Context, key string) (*webauthn.SessionData, error) {
+	data, err := ps.cache.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, domain.ErrInvalidPasskey
+		}
+		return nil, domain.ErrInternal
+	}
+
+	session := &webauthn.SessionData{}
+	err = json.Unmarshal(data, session)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	// the challenge is claimed atomically so concurrent answers to the same challenge can not
+	// both read the session before it is deleted, a challenge that can not be claimed is not accepted
+	claimed, err := ps.cache.SetNX(ctx, fmt.Sprintf("%v-%v-used", key, session.Challenge), []byte("1"), ps.timeout)
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, domain.ErrInvalidPasskey
+	}
+	if !claimed {
+		return nil, domain.ErrInvalidPasskey
+	}
+
+	err = ps.cache.Delete(ctx, key)
+	logOnError(err)
+
+	return session, nil
+}
+
+func registrationSessionKey(userID uuid.UUID) string {
+	return fmt.Sprintf("webauthnRegistration-%v", userID)
+}
+
+func loginSessionKey(challenge string) string {
+	return fmt.Sprintf("webauthnLogin-%v", challenge)
+}
+
+// webAuthnUser implement webauthn.User, the user handle is the user id
+type webAuthnUser struct {
+	user     *domain.User
+	passkeys []domain.Passkey
+}
+
+func (wu *webAuthnUser) WebAuthnID() []byte {
+	return wu.user.ID[:]
+}
+
+func (wu *webAuthnUser) WebAuthnName() string {
+	return wu.user.Name
+}
+
+func (wu *webAuthnUser) WebAuthnDisplayName() string {
+	return wu.user.Name
+}
+
+func (wu *webAuthnUser) WebAuthnIcon() string {
+	return ""
+}
+
+func (wu *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(wu.passkeys))
+
+	for _, passkey := range wu.passkeys {
+		transports := make([]protocol.AuthenticatorTransport, 0, len(passkey.Transports))
+		for _, transport := range passkey.Transports {
+			transports = append(transports, protocol.AuthenticatorTransport(transport))
+		}
+
+		credentials = append(credentials, webauthn.Credential{
+			ID:              passkey.CredentialID,
+			PublicKey:       passkey.PublicKey,
+			AttestationType: passkey.AttestationType,
+			Transport:       transports,
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: passkey.BackupEligible,
+				BackupState:    passkey.BackupState,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    passkey.AAGUID,
+				SignCount: passkey.SignCount,
+			},
+		})
+	}
+
+	return credentials
+}
+
+// descriptors return the passkeys the browser should not register again
+func (wu *webAuthnUser) descriptors() []protocol.CredentialDescriptor {
+	credentials := wu.WebAuthnCredentials()
+
+	descriptors := make([]protocol.CredentialDescriptor, 0, len(credentials))
+	for _, credential := range credentials {
+		descriptors = append(descriptors, credential.Descriptor())
+	}
+
+	return descriptors
+}
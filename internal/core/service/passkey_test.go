@@ -0,0 +1,476 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite/repository"
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/core/service"
+)
+
+const (
+	testRPID     = "blog.example.com"
+	testRPOrigin = "https://blog.example.com"
+)
+
+// authenticator flags of the authenticator data
+const (
+	flagUserPresent        = 0x01
+	flagUserVerified       = 0x04
+	flagAttestedCredential = 0x40
+)
+
+// softwareAuthenticator is a virtual authenticator holding one P-256 credential,
+// it answers the ceremonies like a platform authenticator with "none" attestation
+type softwareAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+	signCount    uint32
+}
+
+func newSoftwareAuthenticator(t *testing.T) *softwareAuthenticator {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	credentialID := make([]byte, 32)
+	if _, err := rand.Read(credentialID); err != nil {
+		t.Fatalf("generate credential id: %v", err)
+	}
+
+	return &softwareAuthenticator{key: key, credentialID: credentialID}
+}
+
+// optionsChallenge read the challenge and the user handle of the options of a ceremony
+func optionsChallenge(t *testing.T, options json.RawMessage) (string, []byte) {
+	t.Helper()
+
+	var parsed struct {
+		PublicKey struct {
+			Challenge string `json:"challenge"`
+			User      struct {
+				ID string `json:"id"`
+			} `json:"user"`
+		} `json:"publicKey"`
+	}
+	if err := json.Unmarshal(options, &parsed); err != nil {
+		t.Fatalf("parse options: %v", err)
+	}
+
+	var userHandle []byte
+	if parsed.PublicKey.User.ID != "" {
+		var err error
+		userHandle, err = base64.RawURLEncoding.DecodeString(parsed.PublicKey.User.ID)
+		if err != nil {
+			t.Fatalf("decode user handle: %v", err)
+		}
+	}
+	return parsed.PublicKey.Challenge, userHandle
+}
+
+// clientData build the client data JSON the browser would send for the challenge
+func clientData(ceremony, challenge string) []byte {
+	data, _ := json.Marshal(map[string]interface{}{
+		"type":        ceremony,
+		"challenge":   challenge,
+		"origin":      testRPOrigin,
+		"crossOrigin": false,
+	})
+	return data
+}
+
+// authenticatorData build the authenticator data, the attested credential is only added with the flag
+func (sa *softwareAuthenticator) authenticatorData(t *testing.T, flags byte) []byte {
+	t.Helper()
+
+	rpIDHash := sha256.Sum256([]byte(testRPID))
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, sa.signCount)
+
+	if flags&flagAttestedCredential != 0 {
+		publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+			PublicKeyData: webauthncose.PublicKeyData{
+				KeyType:   int64(webauthncose.EllipticKey),
+				Algorithm: int64(webauthncose.AlgES256),
+			},
+			Curve:  int64(webauthncose.P256),
+			XCoord: sa.key.PublicKey.X.FillBytes(make([]byte, 32)),
+			YCoord: sa.key.PublicKey.Y.FillBytes(make([]byte, 32)),
+		})
+		if err != nil {
+			t.Fatalf("encode public key: %v", err)
+		}
+
+		data = append(data, make([]byte, 16)...) // AAGUID
+		data = binary.BigEndian.AppendUint16(data, uint16(len(sa.credentialID)))
+		data = append(data, sa.credentialID...)
+		data = append(data, publicKey...)
+	}
+	return data
+}
+
+// register answer registration options with a new credential
+func (sa *softwareAuthenticator) register(t *testing.T, options json.RawMessage) []byte {
+	t.Helper()
+
+	challenge, userHandle := optionsChallenge(t, options)
+	sa.userHandle = userHandle
+
+	attestation, err := webauthncbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": sa.authenticatorData(t, flagUserPresent|flagUserVerified|flagAttestedCredential),
+	})
+	if err != nil {
+		t.Fatalf("encode attestation: %v", err)
+	}
+
+	return credentialResponse(sa.credentialID, map[string]interface{}{
+		"clientDataJSON":    encode(clientData("webauthn.create", challenge)),
+		"attestationObject": encode(attestation),
+		"transports":        []string{"internal"},
+	})
+}
+
+// login answer login options with the credential and the sign count
+func (sa *softwareAuthenticator) login(t *testing.T, options json.RawMessage, signCount uint32) []byte {
+	t.Helper()
+
+	challenge, _ := optionsChallenge(t, options)
+	sa.signCount = signCount
+
+	data := clientData("webauthn.get", challenge)
+	authData := sa.authenticatorData(t, flagUserPresent|flagUserVerified)
+	clientDataHash := sha256.Sum256(data)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+
+	signature, err := ecdsa.SignASN1(rand.Reader, sa.key, digest[:])
+	if err != nil {
+		t.Fatalf("sign assertion: %v", err)
+	}
+
+	return credentialResponse(sa.credentialID, map[string]interface{}{
+		"clientDataJSON":    encode(data),
+		"authenticatorData": encode(authData),
+		"signature":         encode(signature),
+		"userHandle":        encode(sa.userHandle),
+	})
+}
+
+func credentialResponse(credentialID []byte, response map[string]interface{}) []byte {
+	body, _ := json.Marshal(map[string]interface{}{
+		"id":       encode(credentialID),
+		"rawId":    encode(credentialID),
+		"type":     "public-key",
+		"response": response,
+	})
+	return body
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+type passkeyTest struct {
+	svc  ports.IPasskeyService
+	repo ports.IPasskeyRepository
+	tk   ports.ITokenService
+	user *domain.User
+}
+
+func newPasskeyTest(t *testing.T) *passkeyTest {
+	t.Helper()
+	return newPasskeyTestWithCache(t, newMemoryCache())
+}
+
+func newPasskeyTestWithCache(t *testing.T, cache ports.ICacheRepository) *passkeyTest {
+	t.Helper()
+
+	db := newTestDB(t)
+	userRepo := repository.NewUserRepository(db)
+	passkeyRepo := repository.NewPasskeyRepository(db)
+
+	tk := newTestTokenService(t)
+	svc, err := service.NewPasskeyService(config.WebAuthn{
+		RPID:          testRPID,
+		RPDisplayName: "Go Blog",
+		RPOrigins:     []string{testRPOrigin},
+		Timeout:       "5m",
+	}, tk, userRepo, passkeyRepo, cache)
+	if err != nil {
+		t.Fatalf("create service: %v", err)
+	}
+
+	return &passkeyTest{
+		svc:  svc,
+		repo: passkeyRepo,
+		tk:   tk,
+		user: createTestUser(t, userRepo, "alice", "alice@example.com", "Old Password 1"),
+	}
+}
+
+// registerPasskey register the credential of the authenticator to the user of the test
+func (pt *passkeyTest) registerPasskey(t *testing.T, sa *softwareAuthenticator) *domain.Passkey {
+	t.Helper()
+	ctx := context.Background()
+
+	options, err := pt.svc.BeginRegistration(ctx, pt.user.ID)
+	if err != nil {
+		t.Fatalf("begin registration: %v", err)
+	}
+	passkey, err := pt.svc.FinishRegistration(ctx, pt.user.ID, " Laptop ", sa.register(t, options))
+	if err != nil {
+		t.Fatalf("finish registration: %v", err)
+	}
+	return passkey
+}
+
+// signCount return the sign count saved for the credential of the authenticator
+func (pt *passkeyTest) signCount(t *testing.T, sa *softwareAuthenticator) uint32 {
+	t.Helper()
+
+	passkey, err := pt.repo.GetPasskeyByCredentialID(context.Background(), sa.credentialID)
+	if err != nil {
+		t.Fatalf("get passkey: %v", err)
+	}
+	return passkey.SignCount
+}
+
+func TestPasskeyRegisterAndLogin(t *testing.T) {
+	pt := newPasskeyTest(t)
+	sa := newSoftwareAuthenticator(t)
+	ctx := context.Background()
+
+	passkey := pt.registerPasskey(t, sa)
+	if passkey.Name != "Laptop" {
+		t.Errorf("passkey name %q, want Laptop", passkey.Name)
+	}
+	if !bytes.Equal(passkey.CredentialID, sa.credentialID) {
+		t.Error("passkey saved with another credential id")
+	}
+
+	options, err := pt.svc.BeginLogin(ctx)
+	if err != nil {
+		t.Fatalf("begin login: %v", err)
+	}
+	token, err := pt.svc.FinishLogin(ctx, sa.login(t, options, 1))
+	if err != nil {
+		t.Fatalf("finish login: %v", err)
+	}
+
+	payload, err := pt.tk.VerifyToken(token)
+	if err != nil {
+		t.Fatalf("verify token: %v", err)
+	}
+	if payload.ID != pt.user.ID {
+		t.Errorf("token of user %v, want %v", payload.ID, pt.user.ID)
+	}
+	if count := pt.signCount(t, sa); count != 1 {
+		t.Errorf("sign count %v, want 1", count)
+	}
+}
+
+func TestPasskeyRegistrationExcludesRegisteredCredentials(t *testing.T) {
+	pt := newPasskeyTest(t)
+	sa := newSoftwareAuthenticator(t)
+
+	pt.registerPasskey(t, sa)
+
+	options, err := pt.svc.BeginRegistration(context.Background(), pt.user.ID)
+	if err != nil {
+		t.Fatalf("begin registration: %v", err)
+	}
+	var parsed struct {
+		PublicKey struct {
+			ExcludeCredentials []struct {
+				ID string `json:"id"`
+			} `json:"excludeCredentials"`
+		} `json:"publicKey"`
+	}
+	if err := json.Unmarshal(options, &parsed); err != nil {
+		t.Fatalf("parse options: %v", err)
+	}
+	excluded := parsed.PublicKey.ExcludeCredentials
+	if len(excluded) != 1 || excluded[0].ID != encode(sa.credentialID) {
+		t.Errorf("excluded credentials %v, want the registered one", excluded)
+	}
+}
+
+func TestPasskeyRegisterSecondPasskey(t *testing.T) {
+	pt := newPasskeyTest(t)
+
+	first := pt.registerPasskey(t, newSoftwareAuthenticator(t))
+	second := pt.registerPasskey(t, newSoftwareAuthenticator(t))
+	if first.ID == second.ID {
+		t.Fatal("second registration returned the first passkey")
+	}
+}
+
+func TestPasskeyLoginSignCountRegression(t *testing.T) {
+	pt := newPasskeyTest(t)
+	sa := newSoftwareAuthenticator(t)
+	ctx := context.Background()
+
+	pt.registerPasskey(t, sa)
+
+	options, err := pt.svc.BeginLogin(ctx)
+	if err != nil {
+		t.Fatalf("begin login: %v", err)
+	}
+	if _, err := pt.svc.FinishLogin(ctx, sa.login(t, options, 5)); err != nil {
+		t.Fatalf("finish login: %v", err)
+	}
+
+	// a cloned authenticator answers with a counter behind the one already seen
+	for _, count := range []uint32{5, 3} {
+		options, err := pt.svc.BeginLogin(ctx)
+		if err != nil {
+			t.Fatalf("begin login: %v", err)
+		}
+		_, err = pt.svc.FinishLogin(ctx, sa.login(t, options, count))
+		if !errors.Is(err, domain.ErrPasskeyLoginFailed) {
+			t.Errorf("login with sign count %v after 5: %v, want %v", count, err, domain.ErrPasskeyLoginFailed)
+		}
+	}
+
+	if count := pt.signCount(t, sa); count != 5 {
+		t.Errorf("sign count %v, want 5", count)
+	}
+}
+
+func TestPasskeyLoginChallengeReuse(t *testing.T) {
+	pt := newPasskeyTest(t)
+	sa := newSoftwareAuthenticator(t)
+	ctx := context.Background()
+
+	pt.registerPasskey(t, sa)
+
+	options, err := pt.svc.BeginLogin(ctx)
+	if err != nil {
+		t.Fatalf("begin login: %v", err)
+	}
+	if _, err := pt.svc.FinishLogin(ctx, sa.login(t, options, 1)); err != nil {
+		t.Fatalf("finish login: %v", err)
+	}
+
+	// a fresh signature with a higher counter does not make an answered challenge usable again
+	_, err = pt.svc.FinishLogin(ctx, sa.login(t, options, 2))
+	if !errors.Is(err, domain.ErrPasskeyLoginFailed) {
+		t.Fatalf("login with a used challenge: %v, want %v", err, domain.ErrPasskeyLoginFailed)
+	}
+}
+
+func TestPasskeyRegistrationChallengeReuse(t *testing.T) {
+	pt := newPasskeyTest(t)
+	sa := newSoftwareAuthenticator(t)
+	ctx := context.Background()
+
+	options, err := pt.svc.BeginRegistration(ctx, pt.user.ID)
+	if err != nil {
+		t.Fatalf("begin registration: %v", err)
+	}
+	response := sa.register(t, options)
+	if _, err := pt.svc.FinishRegistration(ctx, pt.user.ID, "", response); err != nil {
+		t.Fatalf("finish registration: %v", err)
+	}
+
+	_, err = pt.svc.FinishRegistration(ctx, pt.user.ID, "", response)
+	if !errors.Is(err, domain.ErrInvalidPasskey) {
+		t.Fatalf("registration with a used challenge: %v, want %v", err, domain.ErrInvalidPasskey)
+	}
+}
+
+func TestPasskeyLoginUnknownChallenge(t *testing.T) {
+	pt := newPasskeyTest(t)
+	sa := newSoftwareAuthenticator(t)
+	ctx := context.Background()
+
+	pt.registerPasskey(t, sa)
+
+	// the challenge of another server is unknown here, like one whose session expired
+	other := newPasskeyTest(t)
+	options, err := other.svc.BeginLogin(ctx)
+	if err != nil {
+		t.Fatalf("begin login: %v", err)
+	}
+
+	_, err = pt.svc.FinishLogin(ctx, sa.login(t, options, 1))
+	if !errors.Is(err, domain.ErrPasskeyLoginFailed) {
+		t.Fatalf("login with an unknown challenge: %v, want %v", err, domain.ErrPasskeyLoginFailed)
+	}
+}
+
+// sessionBarrierCache hold the reads of login sessions until two of them are waiting,
+// the concurrent logins then both read the session before either can delete it
+type sessionBarrierCache struct {
+	ports.ICacheRepository
+	reads sync.WaitGroup
+}
+
+func (sc *sessionBarrierCache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := sc.ICacheRepository.Get(ctx, key)
+	if strings.HasPrefix(key, "webauthnLogin-") {
+		sc.reads.Done()
+		sc.reads.Wait()
+	}
+	return value, err
+}
+
+func TestPasskeyLoginConcurrentReplay(t *testing.T) {
+	cache := &sessionBarrierCache{ICacheRepository: newMemoryCache()}
+	cache.reads.Add(2)
+	pt := newPasskeyTestWithCache(t, cache)
+	sa := newSoftwareAuthenticator(t)
+	ctx := context.Background()
+
+	pt.registerPasskey(t, sa)
+
+	options, err := pt.svc.BeginLogin(ctx)
+	if err != nil {
+		t.Fatalf("begin login: %v", err)
+	}
+	// an authenticator without counter always sends 0, the sign count does not tell the replay apart
+	response := sa.login(t, options, 0)
+
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := pt.svc.FinishLogin(ctx, response)
+			errs <- err
+		}()
+	}
+
+	succeeded := 0
+	for i := 0; i < 2; i++ {
+		err := <-errs
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, domain.ErrPasskeyLoginFailed):
+			t.Errorf("concurrent login: %v, want %v", err, domain.ErrPasskeyLoginFailed)
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("%v concurrent logins with the same assertion succeeded, want 1", succeeded)
+	}
+}
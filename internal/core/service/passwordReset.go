@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/mail"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/core/util"
+	"github.com/tommjj/go-blog-api/internal/logger"
+)
+
+// resetTokenSize is the number of random bytes of a password reset token
+const resetTokenSize = 32
+
+type PasswordResetService struct {
+	userRepo  ports.IUserRepository
+	userCache ports.IUserCache
+	tokenRepo ports.IActionTokenRepository
+	policy    ports.IPasswordPolicy
+	mailer    ports.IMailer
+	secret    string
+	resetURL  string
+	duration  time.Duration
+}
+
+func NewPasswordResetService(
+	conf config.Auth,
+	userRepo ports.IUserRepository,
+	userCache ports.IUserCache,
+	tokenRepo ports.IActionTokenRepository,
+	policy ports.IPasswordPolicy,
+	mailer ports.IMailer,
+) (ports.IPasswordResetService, error) {
+	duration, err := time.ParseDuration(conf.PasswordResetDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PasswordResetService{
+		userRepo:  userRepo,
+		userCache: userCache,
+		tokenRepo: tokenRepo,
+		policy:    policy,
+		mailer:    mailer,
+		secret:    conf.SecretKey,
+		resetURL:  conf.PasswordResetURL,
+		duration:  duration,
+	}, nil
+}
+
+func (ps *PasswordResetService) RequestReset(ctx context.Context, email string) error {
+	user, err := ps.userRepo.GetUserByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		// do not tell the caller whether the email is registered
+		if errors.Is(err, domain.ErrDataNotFound) {
+			logger.Info("password reset requested for an unknown email")
+			return nil
+		}
+		return domain.ErrInternal
+	}
+
+	// only the latest reset link stays valid
+	err = ps.tokenRepo.DeleteTokensByUserID(ctx, user.ID, domain.PasswordResetPurpose)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	token, err := util.GenerateToken(resetTokenSize)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	_, err = ps.tokenRepo.CreateToken(ctx, &domain.ActionToken{
+		UserID:    user.ID,
+		Purpose:   domain.PasswordResetPurpose,
+		TokenHash: util.HashToken(ps.secret, token),
+		ExpiresAt: time.Now().Add(ps.duration),
+	})
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	resetURL, err := addQueryParam(ps.resetURL, "token", token)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	msg, err := mail.Render(mail.PasswordResetTemplate, user.Email, mail.PasswordResetData{
+		Username:  user.Name,
+		ResetURL:  resetURL,
+		ExpiresIn: ps.duration.String(),
+	})
+	if err != nil {
+		logger.Error(err.Error())
+		return domain.ErrInternal
+	}
+
+	// a failed delivery is not reported to the caller for the same reason as unknown emails
+	err = ps.mailer.Send(ctx, msg)
+	logOnError(err)
+
+	return nil
+}
+
+func (ps *PasswordResetService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	resetToken, err := ps.tokenRepo.GetTokenByHash(ctx, domain.PasswordResetPurpose, util.HashToken(ps.secret, token))
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return domain.ErrInvalidActionToken
+		}
+		return domain.ErrInternal
+	}
+
+	if !resetToken.IsUsable(time.Now()) {
+		return domain.ErrInvalidActionToken
+	}
+
+	user, err := ps.userRepo.GetUserByID(ctx, resetToken.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return domain.ErrInvalidActionToken
+		}
+		return domain.ErrInternal
+	}
+
+	err = ps.policy.Validate(ctx, user.Name, newPassword)
+	if err != nil {
+		if errors.Is(err, domain.ErrWeakPassword) {
+			return err
+		}
+		logger.Error(err.Error())
+		return domain.ErrInternal
+	}
+
+	hashPass, err := util.HashPassword(newPassword)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	err = ps.tokenRepo.ConsumeToken(ctx, resetToken.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidActionToken) {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	updatedUser, err := ps.userRepo.UpdateCredentialsByMap(ctx, user.ID, &map[string]interface{}{
+		"password": hashPass,
+	})
+	if err != nil {
+		return domain.ErrInternal
+	}
+	updatedUser.Password = ""
+
+	err = ps.userCache.SetUser(ctx, updatedUser)
+	logOnError(err)
+
+	err = ps.tokenRepo.DeleteTokensByUserID(ctx, user.ID, domain.PasswordResetPurpose)
+	logOnError(err)
+
+	return nil
+}
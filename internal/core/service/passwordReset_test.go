@@ -0,0 +1,327 @@
+package service_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tommjj/go-blog-api/internal/adapter/mailer"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite/repository"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite/schema"
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/auth"
+	"github.com/tommjj/go-blog-api/internal/core/cache"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/core/service"
+	"github.com/tommjj/go-blog-api/internal/core/util"
+)
+
+// smtpMessage is a message received by the test SMTP server
+type smtpMessage struct {
+	From string
+	To   []string
+	Data string
+}
+
+// smtpServer is an SMTP server listening on a local port, it accepts every message and hands it to the test
+type smtpServer struct {
+	listener net.Listener
+	messages chan smtpMessage
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := &smtpServer{
+		listener: listener,
+		messages: make(chan smtpMessage, 10),
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (ss *smtpServer) port() int {
+	return ss.listener.Addr().(*net.TCPAddr).Port
+}
+
+// serve speak the part of SMTP the mailer uses, without STARTTLS and AUTH
+func (ss *smtpServer) serve(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+
+	msg := smtpMessage{}
+	text.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		command, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(command) {
+		case "EHLO", "HELO":
+			text.PrintfLine("250 localhost")
+		case "MAIL":
+			msg = smtpMessage{From: strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")}
+			text.PrintfLine("250 OK")
+		case "RCPT":
+			msg.To = append(msg.To, strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>"))
+			text.PrintfLine("250 OK")
+		case "DATA":
+			text.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			msg.Data = string(data)
+			ss.messages <- msg
+			text.PrintfLine("250 OK")
+		case "QUIT":
+			text.PrintfLine("221 Bye")
+			return
+		default:
+			text.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+// receive wait for the next message delivered to the server
+func (ss *smtpServer) receive(t *testing.T) smtpMessage {
+	t.Helper()
+
+	select {
+	case msg := <-ss.messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message delivered")
+		return smtpMessage{}
+	}
+}
+
+// textPart return the decoded text/plain part of a multipart message
+func textPart(t *testing.T, data string) string {
+	t.Helper()
+
+	msg, err := mail.ReadMessage(bufio.NewReader(strings.NewReader(data)))
+	if err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("parse content type: %v", err)
+	}
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			t.Fatalf("no text part: %v", err)
+		}
+		if strings.HasPrefix(part.Header.Get("Content-Type"), "text/plain") {
+			body, err := io.ReadAll(part)
+			if err != nil {
+				t.Fatalf("read text part: %v", err)
+			}
+			return string(body)
+		}
+	}
+}
+
+var resetURLPattern = regexp.MustCompile(`https://blog\.example\.com/reset\?token=[A-Za-z0-9_-]+`)
+
+// resetToken return the token of the reset link of a mail
+func resetToken(t *testing.T, msg smtpMessage) string {
+	t.Helper()
+	return linkToken(t, msg, resetURLPattern)
+}
+
+// linkToken return the token of the first link of a mail matching the pattern
+func linkToken(t *testing.T, msg smtpMessage, pattern *regexp.Regexp) string {
+	t.Helper()
+
+	link := pattern.FindString(textPart(t, msg.Data))
+	if link == "" {
+		t.Fatalf("no link matching %v in the mail:\n%v", pattern, msg.Data)
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("parse link: %v", err)
+	}
+	return u.Query().Get("token")
+}
+
+type resetTest struct {
+	db       *sqlite.DB
+	svc      ports.IPasswordResetService
+	userRepo ports.IUserRepository
+	smtp     *smtpServer
+	user     *domain.User
+}
+
+func newResetTest(t *testing.T) *resetTest {
+	t.Helper()
+
+	db := newTestDB(t)
+	smtp := newSMTPServer(t)
+
+	smtpMailer, err := mailer.NewSMTPMailer(config.Mail{
+		From:     "Go Blog <no-reply@blog.example.com>",
+		SMTPHost: "127.0.0.1",
+		SMTPPort: smtp.port(),
+	})
+	if err != nil {
+		t.Fatalf("create mailer: %v", err)
+	}
+
+	userRepo := repository.NewUserRepository(db)
+	svc, err := service.NewPasswordResetService(
+		config.Auth{
+			SecretKey:             "test-secret",
+			PasswordResetDuration: "15m",
+			PasswordResetURL:      "https://blog.example.com/reset",
+		},
+		userRepo,
+		cache.NewUserCache(newMemoryCache(), time.Hour),
+		repository.NewActionTokenRepository(db),
+		auth.NewPasswordPolicy(config.Password{MinLength: 8}, nil),
+		smtpMailer,
+	)
+	if err != nil {
+		t.Fatalf("create service: %v", err)
+	}
+
+	return &resetTest{
+		db:       db,
+		svc:      svc,
+		userRepo: userRepo,
+		smtp:     smtp,
+		user:     createTestUser(t, userRepo, "alice", "alice@example.com", "Old Password 1"),
+	}
+}
+
+func TestPasswordResetDeliversMail(t *testing.T) {
+	rt := newResetTest(t)
+
+	if err := rt.svc.RequestReset(context.Background(), "Alice@Example.com"); err != nil {
+		t.Fatalf("request reset: %v", err)
+	}
+
+	msg := rt.smtp.receive(t)
+	if msg.From != "no-reply@blog.example.com" {
+		t.Errorf("mail from %q, want no-reply@blog.example.com", msg.From)
+	}
+	if len(msg.To) != 1 || msg.To[0] != "alice@example.com" {
+		t.Errorf("mail to %v, want [alice@example.com]", msg.To)
+	}
+	if token := resetToken(t, msg); token == "" {
+		t.Error("reset link without token")
+	}
+}
+
+func TestPasswordResetUnknownEmailSendsNothing(t *testing.T) {
+	rt := newResetTest(t)
+
+	if err := rt.svc.RequestReset(context.Background(), "bob@example.com"); err != nil {
+		t.Fatalf("request reset: %v", err)
+	}
+
+	select {
+	case msg := <-rt.smtp.messages:
+		t.Fatalf("mail sent to an unknown email: %v", msg.To)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestPasswordResetTokenWorksOnce(t *testing.T) {
+	rt := newResetTest(t)
+	ctx := context.Background()
+
+	if err := rt.svc.RequestReset(ctx, "alice@example.com"); err != nil {
+		t.Fatalf("request reset: %v", err)
+	}
+	token := resetToken(t, rt.smtp.receive(t))
+
+	if err := rt.svc.ResetPassword(ctx, token, "New Password 2"); err != nil {
+		t.Fatalf("reset password: %v", err)
+	}
+
+	user, err := rt.userRepo.GetUserByID(ctx, rt.user.ID)
+	if err != nil {
+		t.Fatalf("get user: %v", err)
+	}
+	if util.ComparePassword("New Password 2", user.Password) != nil {
+		t.Error("password not changed")
+	}
+	if user.TokenVersion != rt.user.TokenVersion+1 {
+		t.Errorf("token version %v, want %v", user.TokenVersion, rt.user.TokenVersion+1)
+	}
+
+	err = rt.svc.ResetPassword(ctx, token, "New Password 3")
+	if !errors.Is(err, domain.ErrInvalidActionToken) {
+		t.Fatalf("second reset with the same token: %v, want %v", err, domain.ErrInvalidActionToken)
+	}
+
+	user, err = rt.userRepo.GetUserByID(ctx, rt.user.ID)
+	if err != nil {
+		t.Fatalf("get user: %v", err)
+	}
+	if util.ComparePassword("New Password 2", user.Password) != nil {
+		t.Error("password changed by a used token")
+	}
+}
+
+func TestPasswordResetExpiredToken(t *testing.T) {
+	rt := newResetTest(t)
+	ctx := context.Background()
+
+	if err := rt.svc.RequestReset(ctx, "alice@example.com"); err != nil {
+		t.Fatalf("request reset: %v", err)
+	}
+	token := resetToken(t, rt.smtp.receive(t))
+
+	err := rt.db.Model(&schema.ActionToken{}).Where("user_id = ?", rt.user.ID).
+		Update("expires_at", time.Now().Add(-time.Minute)).Error
+	if err != nil {
+		t.Fatalf("expire token: %v", err)
+	}
+
+	err = rt.svc.ResetPassword(ctx, token, "New Password 2")
+	if !errors.Is(err, domain.ErrInvalidActionToken) {
+		t.Fatalf("reset with an expired token: %v, want %v", err, domain.ErrInvalidActionToken)
+	}
+
+	user, err := rt.userRepo.GetUserByID(ctx, rt.user.ID)
+	if err != nil {
+		t.Fatalf("get user: %v", err)
+	}
+	if util.ComparePassword("Old Password 1", user.Password) != nil {
+		t.Error("password changed by an expired token")
+	}
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/logger"
+)
+
+// ReactionService count the reactions in redis counters, written on every reaction,
+// and counts them again in the database at every reconcile interval.
+// A missing counter is set from the reactions in the database, the reconcile fixes the counters that drifted
+type ReactionService struct {
+	kinds    []string
+	known    map[string]bool
+	interval time.Duration
+	repo     ports.IReactionRepository
+	cache    ports.IReactionCache
+	blogs    ports.IBlogService
+}
+
+func NewReactionService(conf config.Reaction, repo ports.IReactionRepository, cache ports.IReactionCache, blogService ports.IBlogService) (ports.IReactionService, error) {
+	if len(conf.Kinds) == 0 {
+		return nil, fmt.Errorf("reaction kinds are required")
+	}
+	interval, err := time.ParseDuration(conf.ReconcileInterval)
+	if err != nil {
+		return nil, err
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("reaction reconcile interval must be positive: %v", interval)
+	}
+
+	known := map[string]bool{}
+	for _, kind := range conf.Kinds {
+		known[kind] = true
+	}
+
+	return &ReactionService{
+		kinds:    conf.Kinds,
+		known:    known,
+		interval: interval,
+		repo:     repo,
+		cache:    cache,
+		blogs:    blogService,
+	}, nil
+}
+
+func (rs *ReactionService) AddReaction(ctx context.Context, blogID, userID uuid.UUID, kind string) (domain.ReactionCounts, error) {
+	return rs.react(ctx, blogID, kind, 1, func() (bool, error) {
+		return rs.repo.AddReaction(ctx, &domain.BlogReaction{
+			BlogID:    blogID,
+			UserID:    userID,
+			Kind:      kind,
+			CreatedAt: time.Now(),
+		})
+	})
+}
+
+func (rs *ReactionService) RemoveReaction(ctx context.Context, blogID, userID uuid.UUID, kind string) (domain.ReactionCounts, error) {
+	return rs.react(ctx, blogID, kind, -1, func() (bool, error) {
+		return rs.repo.RemoveReaction(ctx, blogID, userID, kind)
+	})
+}
+
+// react write a reaction to a blog, the counter of its kind follows when the write changed a row
+func (rs *ReactionService) react(ctx context.Context, blogID uuid.UUID, kind string, value int, write func() (bool, error)) (domain.ReactionCounts, error) {
+	if !rs.known[kind] {
+		return nil, domain.ErrUnknownReaction
+	}
+
+	// blogs in the trash can not get reactions
+	_, err := rs.blogs.GetBlogByID(ctx, blogID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the counters are set before the write, a counter created by the increment would start from 0
+	allCounts, err := rs.GetCounts(ctx, blogID)
+	if err != nil {
+		return nil, err
+	}
+	counts := allCounts[blogID]
+
+	changed, err := write()
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, domain.ErrInternal
+	}
+	if !changed {
+		return counts, nil
+	}
+
+	count, err := rs.cache.AddCount(ctx, blogID, kind, value)
+	if err != nil {
+		logger.Error(err.Error())
+		// the counter may have missed the write, it is set again from the database
+		return rs.resetCounts(ctx, blogID, counts, kind, value), nil
+	}
+	counts[kind] = max(count, 0)
+
+	return counts, nil
+}
+
+// resetCounts set the counters of a blog from the reactions in the database, the counts read before
+// the write are returned with the value added if the database can not be read
+func (rs *ReactionService) resetCounts(ctx context.Context, blogID uuid.UUID, counts domain.ReactionCounts, kind string, value int) domain.ReactionCounts {
+	saved, err := rs.repo.GetReactionCounts(ctx, []uuid.UUID{blogID})
+	if err != nil {
+		logger.Error(err.Error())
+		counts[kind] = max(counts[kind]+value, 0)
+		return counts
+	}
+
+	counts = domain.ReactionCounts{}
+	for _, k := range rs.kinds {
+		counts[k] = saved[blogID][k]
+	}
+
+	err = rs.cache.SetCounts(ctx, blogID, counts)
+	logOnError(err)
+
+	return counts
+}
+
+func (rs *ReactionService) GetCounts(ctx context.Context, blogIDs ...uuid.UUID) (map[uuid.UUID]domain.ReactionCounts, error) {
+	counts := make(map[uuid.UUID]domain.ReactionCounts, len(blogIDs))
+	missing := []uuid.UUID{}
+	for _, id := range blogIDs {
+		blogCounts, err := rs.cache.GetCounts(ctx, id, rs.kinds)
+		if err != nil {
+			if err == domain.ErrDataNotFound {
+				logger.Info(err.Error())
+			} else {
+				logger.Error(err.Error())
+			}
+			missing = append(missing, id)
+			continue
+		}
+		counts[id] = blogCounts
+	}
+	if len(missing) == 0 {
+		return counts, nil
+	}
+
+	// the counters missing are set from the reactions in the database
+	saved, err := rs.repo.GetReactionCounts(ctx, missing)
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, domain.ErrInternal
+	}
+
+	for _, id := range missing {
+		blogCounts := domain.ReactionCounts{}
+		for _, kind := range rs.kinds {
+			blogCounts[kind] = saved[id][kind]
+		}
+		counts[id] = blogCounts
+
+		err = rs.cache.InitCounts(ctx, id, blogCounts)
+		logOnError(err)
+	}
+
+	return counts, nil
+}
+
+func (rs *ReactionService) Reconcile(ctx context.Context) error {
+	reconciled, err := rs.repo.ReconcileReactionCounts(ctx, rs.kinds)
+	if err != nil {
+		logger.Error(err.Error())
+		return domain.ErrInternal
+	}
+
+	// only the counters that drifted from the new counts or are missing are replaced, the others keep counting
+	for id, saved := range reconciled {
+		counts := domain.ReactionCounts{}
+		for _, kind := range rs.kinds {
+			counts[kind] = saved[kind]
+		}
+
+		current, err := rs.cache.GetCounts(ctx, id, rs.kinds)
+		if err == nil && maps.Equal(current, counts) {
+			continue
+		}
+
+		err = rs.cache.SetCounts(ctx, id, counts)
+		logOnError(err)
+	}
+
+	return nil
+}
+
+func (rs *ReactionService) RunReconcileJob(ctx context.Context) {
+	ticker := time.NewTicker(rs.interval)
+	defer ticker.Stop()
+
+	for {
+		// a failed reconcile is retried at the next tick
+		_ = rs.Reconcile(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/adapter/storage/sqlite/repository"
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/cache"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/core/service"
+)
+
+var testReactionKinds = []string{"like", "love"}
+
+// failingIncrCache fail every increment, like a redis that went away after the counters were read
+type failingIncrCache struct {
+	*memoryCache
+}
+
+func (fc *failingIncrCache) IncrBy(ctx context.Context, key string, value int64, ttl time.Duration) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+type reactionTest struct {
+	svc    ports.IReactionService
+	mem    *memoryCache
+	blogID uuid.UUID
+	users  []*domain.User
+}
+
+func newReactionTest(t *testing.T, counters ports.ICacheRepository, mem *memoryCache) *reactionTest {
+	t.Helper()
+
+	db := newTestDB(t)
+	userRepo := repository.NewUserRepository(db)
+	blogRepo := repository.NewBlogRepository(db)
+
+	users := []*domain.User{
+		createTestUser(t, userRepo, "alice", "alice@example.com", "Old Password 1"),
+		createTestUser(t, userRepo, "bob", "bob@example.com", "Old Password 1"),
+	}
+	blog, err := blogRepo.CreateBlog(context.Background(), &domain.Blog{
+		Title:    "Hello",
+		Text:     "Hello world",
+		AuthorID: users[0].ID,
+	})
+	if err != nil {
+		t.Fatalf("create blog: %v", err)
+	}
+
+	blogSvc := service.NewBlogService(
+		blogRepo,
+		repository.NewBlogRevisionRepository(db),
+		cache.NewBlogCache(mem, time.Hour, time.Hour, time.Hour),
+		cache.NewSitemapCache(mem, time.Hour),
+		cache.NewFeedCache(mem, time.Hour),
+	)
+	svc, err := service.NewReactionService(
+		config.Reaction{Kinds: testReactionKinds, ReconcileInterval: "1h"},
+		repository.NewReactionRepository(db),
+		cache.NewReactionCache(counters, 24*time.Hour),
+		blogSvc,
+	)
+	if err != nil {
+		t.Fatalf("create service: %v", err)
+	}
+
+	return &reactionTest{svc: svc, mem: mem, blogID: blog.ID, users: users}
+}
+
+// counterKey return the cache key of the counter of a kind of reaction to the blog of the test
+func (rt *reactionTest) counterKey(kind string) string {
+	return fmt.Sprintf("reaction-%v-%v", rt.blogID, kind)
+}
+
+func (rt *reactionTest) counts(t *testing.T) domain.ReactionCounts {
+	t.Helper()
+
+	counts, err := rt.svc.GetCounts(context.Background(), rt.blogID)
+	if err != nil {
+		t.Fatalf("get counts: %v", err)
+	}
+	return counts[rt.blogID]
+}
+
+func TestReactionReconcileFixesDriftedCounter(t *testing.T) {
+	mem := newMemoryCache()
+	rt := newReactionTest(t, mem, mem)
+	ctx := context.Background()
+
+	if _, err := rt.svc.AddReaction(ctx, rt.blogID, rt.users[0].ID, "like"); err != nil {
+		t.Fatalf("add reaction: %v", err)
+	}
+	if err := rt.svc.Reconcile(ctx); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+
+	// the counts in the database do not move, only the counter drifted
+	if err := mem.Set(ctx, rt.counterKey("like"), []byte("7"), time.Hour); err != nil {
+		t.Fatalf("set counter: %v", err)
+	}
+	if err := rt.svc.Reconcile(ctx); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+
+	if counts := rt.counts(t); counts["like"] != 1 || counts["love"] != 0 {
+		t.Errorf("counts %v, want like 1 and love 0", counts)
+	}
+}
+
+func TestReactionCounterExpiredBeforeIncrement(t *testing.T) {
+	mem := newMemoryCache()
+	rt := newReactionTest(t, mem, mem)
+	ctx := context.Background()
+
+	if _, err := rt.svc.AddReaction(ctx, rt.blogID, rt.users[0].ID, "like"); err != nil {
+		t.Fatalf("add reaction: %v", err)
+	}
+	if err := rt.svc.Reconcile(ctx); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+
+	// the counter expires between the read of the counts and the increment of the next reaction
+	counter := cache.NewReactionCache(mem, 24*time.Hour)
+	if err := mem.Delete(ctx, rt.counterKey("like")); err != nil {
+		t.Fatalf("delete counter: %v", err)
+	}
+	if _, err := counter.AddCount(ctx, rt.blogID, "like", 1); err != nil {
+		t.Fatalf("add count: %v", err)
+	}
+
+	mem.mu.Lock()
+	_, hasTTL := mem.expires[rt.counterKey("like")]
+	mem.mu.Unlock()
+	if !hasTTL {
+		t.Error("counter created by the increment has no ttl")
+	}
+
+	if err := rt.svc.Reconcile(ctx); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if counts := rt.counts(t); counts["like"] != 1 {
+		t.Errorf("like count %v after reconcile, want 1", counts["like"])
+	}
+}
+
+func TestReactionFailedIncrementResetsCounter(t *testing.T) {
+	mem := newMemoryCache()
+	rt := newReactionTest(t, &failingIncrCache{mem}, mem)
+	ctx := context.Background()
+
+	if _, err := rt.svc.AddReaction(ctx, rt.blogID, rt.users[0].ID, "like"); err != nil {
+		t.Fatalf("add reaction: %v", err)
+	}
+	counts, err := rt.svc.AddReaction(ctx, rt.blogID, rt.users[1].ID, "like")
+	if err != nil {
+		t.Fatalf("add reaction: %v", err)
+	}
+	if counts["like"] != 2 {
+		t.Errorf("returned like count %v, want 2", counts["like"])
+	}
+
+	if counts := rt.counts(t); counts["like"] != 2 {
+		t.Errorf("like count %v, want 2", counts["like"])
+	}
+}
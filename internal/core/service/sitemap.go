@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/logger"
+)
+
+// MaxSitemapURLs is the maximum number of pages of a sitemap, more are split in sitemaps listed by an index
+const MaxSitemapURLs = 50000
+
+const sitemapXMLNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	XMLNS    string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapURL `xml:"sitemap"`
+}
+
+// sitemapPage is a page of the site listed in a sitemap
+type sitemapPage struct {
+	loc     string
+	lastMod time.Time
+}
+
+type SitemapService struct {
+	conf   config.Sitemap
+	site   config.Site
+	blogs  ports.IBlogService
+	cache  ports.ISitemapCache
+	robots string
+}
+
+func NewSitemapService(conf config.Sitemap, site config.Site, blogService ports.IBlogService, cache ports.ISitemapCache) (ports.ISitemapService, error) {
+	if conf.URL == "" {
+		return nil, fmt.Errorf("sitemap url is required")
+	}
+
+	var robots strings.Builder
+	robots.WriteString("User-agent: *\n")
+	for _, path := range conf.RobotsDisallow {
+		fmt.Fprintf(&robots, "Disallow: %v\n", path)
+	}
+	if len(conf.RobotsDisallow) == 0 {
+		robots.WriteString("Disallow:\n")
+	}
+	fmt.Fprintf(&robots, "\nSitemap: %v/sitemap.xml\n", conf.URL)
+
+	return &SitemapService{
+		conf:   conf,
+		site:   site,
+		blogs:  blogService,
+		cache:  cache,
+		robots: robots.String(),
+	}, nil
+}
+
+func (ss *SitemapService) GetRobots(ctx context.Context) string {
+	return ss.robots
+}
+
+func (ss *SitemapService) GetSitemap(ctx context.Context, page int) (*domain.Sitemap, error) {
+	if page < 0 {
+		return nil, domain.ErrDataNotFound
+	}
+
+	// the sitemaps of an index are only looked for when the index has them
+	if page > 0 {
+		root, err := ss.getSitemap(ctx, 0)
+		if err != nil {
+			return nil, err
+		}
+		if page > root.Pages {
+			return nil, domain.ErrDataNotFound
+		}
+	}
+
+	return ss.getSitemap(ctx, page)
+}
+
+// getSitemap get a sitemap from the cache, all the sitemaps are generated again when it is missing
+func (ss *SitemapService) getSitemap(ctx context.Context, page int) (*domain.Sitemap, error) {
+	cached, err := ss.cache.GetSitemap(ctx, page)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			logger.Info(err.Error())
+		} else {
+			logger.Error(err.Error())
+		}
+	} else {
+		return cached, nil
+	}
+
+	sitemaps, err := ss.generate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sitemap := range sitemaps {
+		err = ss.cache.SetSitemap(ctx, sitemap)
+		logOnError(err)
+	}
+
+	if page >= len(sitemaps) {
+		return nil, domain.ErrDataNotFound
+	}
+	return sitemaps[page], nil
+}
+
+// generate write the sitemaps of the home page, the author pages and the blogs, the first one is /sitemap.xml.
+// An author page is as new as the latest blog of its author, the home page as the latest blog
+func (ss *SitemapService) generate(ctx context.Context) ([]*domain.Sitemap, error) {
+	blogs, err := ss.blogs.GetSitemapBlogs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	home := sitemapPage{loc: ss.site.URL}
+	authors := map[uuid.UUID]*sitemapPage{}
+	blogPages := make([]sitemapPage, 0, len(blogs))
+	for _, blog := range blogs {
+		if blog.Slug == "" {
+			continue
+		}
+		blogPages = append(blogPages, sitemapPage{
+			loc:     ss.site.BlogURL + url.PathEscape(blog.Slug),
+			lastMod: blog.UpdatedAt,
+		})
+
+		author, ok := authors[blog.AuthorID]
+		if !ok {
+			author = &sitemapPage{loc: ss.site.AuthorURL + blog.AuthorID.String()}
+			authors[blog.AuthorID] = author
+		}
+		if blog.UpdatedAt.After(author.lastMod) {
+			author.lastMod = blog.UpdatedAt
+		}
+		if blog.UpdatedAt.After(home.lastMod) {
+			home.lastMod = blog.UpdatedAt
+		}
+	}
+
+	authorPages := make([]sitemapPage, 0, len(authors))
+	for _, author := range authors {
+		authorPages = append(authorPages, *author)
+	}
+	sort.Slice(authorPages, func(i, j int) bool {
+		return authorPages[i].loc < authorPages[j].loc
+	})
+
+	pages := append([]sitemapPage{home}, authorPages...)
+	pages = append(pages, blogPages...)
+
+	if len(pages) <= MaxSitemapURLs {
+		sitemap, err := ss.writeURLSet(0, pages)
+		if err != nil {
+			return nil, err
+		}
+		return []*domain.Sitemap{sitemap}, nil
+	}
+
+	count := (len(pages) + MaxSitemapURLs - 1) / MaxSitemapURLs
+	sitemaps := make([]*domain.Sitemap, 1, count+1)
+	index := sitemapIndex{XMLNS: sitemapXMLNS}
+	updatedAt := time.Time{}
+	for i := 0; i < count; i++ {
+		end := min((i+1)*MaxSitemapURLs, len(pages))
+		sitemap, err := ss.writeURLSet(i+1, pages[i*MaxSitemapURLs:end])
+		if err != nil {
+			return nil, err
+		}
+		sitemaps = append(sitemaps, sitemap)
+
+		index.Sitemaps = append(index.Sitemaps, sitemapURL{
+			Loc:     fmt.Sprintf("%v/sitemaps/%v.xml", ss.conf.URL, i+1),
+			LastMod: formatLastMod(sitemap.UpdatedAt),
+		})
+		if sitemap.UpdatedAt.After(updatedAt) {
+			updatedAt = sitemap.UpdatedAt
+		}
+	}
+
+	root, err := writeSitemap(0, index, updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	root.Pages = count
+	sitemaps[0] = root
+	return sitemaps, nil
+}
+
+// writeURLSet write a sitemap of pages
+func (ss *SitemapService) writeURLSet(page int, pages []sitemapPage) (*domain.Sitemap, error) {
+	urlSet := sitemapURLSet{XMLNS: sitemapXMLNS, URLs: make([]sitemapURL, 0, len(pages))}
+	updatedAt := time.Time{}
+	for _, p := range pages {
+		urlSet.URLs = append(urlSet.URLs, sitemapURL{
+			Loc:     p.loc,
+			LastMod: formatLastMod(p.lastMod),
+		})
+		if p.lastMod.After(updatedAt) {
+			updatedAt = p.lastMod
+		}
+	}
+
+	return writeSitemap(page, urlSet, updatedAt)
+}
+
+// writeSitemap write a sitemap document, a site without blogs is as new as its sitemap
+func writeSitemap(page int, document any, updatedAt time.Time) (*domain.Sitemap, error) {
+	content, err := xml.Marshal(document)
+	if err != nil {
+		logger.Error(err.Error())
+		return nil, domain.ErrInternal
+	}
+	content = append([]byte(xml.Header), content...)
+
+	if updatedAt.IsZero() {
+		updatedAt = time.Now()
+	}
+
+	hash := sha256.Sum256(content)
+	return &domain.Sitemap{
+		Page:      page,
+		Content:   string(content),
+		ETag:      hex.EncodeToString(hash[:16]),
+		UpdatedAt: updatedAt.UTC().Truncate(time.Second),
+	}, nil
+}
+
+// formatLastMod format the last modification of a page in the W3C datetime format, nothing for the zero time
+func formatLastMod(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tommjj/go-blog-api/internal/config"
+	"github.com/tommjj/go-blog-api/internal/core/domain"
+	"github.com/tommjj/go-blog-api/internal/core/ports"
+	"github.com/tommjj/go-blog-api/internal/logger"
+)
+
+type TrashService struct {
+	userRepo  ports.IUserRepository
+	blogRepo  ports.IBlogRepository
+	retention time.Duration
+	interval  time.Duration
+}
+
+func NewTrashService(conf config.Trash, userRepo ports.IUserRepository, blogRepo ports.IBlogRepository) (ports.ITrashService, error) {
+	retention, err := time.ParseDuration(conf.Retention)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := time.ParseDuration(conf.PurgeInterval)
+	if err != nil {
+		return nil, err
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("trash purge interval must be positive: %v", interval)
+	}
+
+	return &TrashService{
+		userRepo:  userRepo,
+		blogRepo:  blogRepo,
+		retention: retention,
+		interval:  interval,
+	}, nil
+}
+
+func (ts *TrashService) Purge(ctx context.Context) error {
+	before := time.Now().Add(-ts.retention)
+
+	// users first, their blogs go with them
+	users, err := ts.userRepo.PurgeUsers(ctx, before)
+	if err != nil {
+		logger.Error(err.Error())
+		return domain.ErrInternal
+	}
+
+	blogs, err := ts.blogRepo.PurgeBlogs(ctx, before)
+	if err != nil {
+		logger.Error(err.Error())
+		return domain.ErrInternal
+	}
+
+	if users > 0 || blogs > 0 {
+		logger.Info(fmt.Sprintf("trash purged: %v users, %v blogs", users, blogs))
+	}
+
+	return nil
+}
+
+func (ts *TrashService) RunPurgeJob(ctx context.Context) {
+	ticker := time.NewTicker(ts.interval)
+	defer ticker.Stop()
+
+	for {
+		// a failed purge is retried at the next tick
+		_ = ts.Purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
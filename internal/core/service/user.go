@@ -12,14 +12,32 @@ import (
 )
 
 type UserService struct {
-	repo  ports.IUserRepository // user repo
-	cache ports.IUserCache      // user cache
+	repo     ports.IUserRepository           // user repo
+	cache    ports.IUserCache                // user cache
+	policy   ports.IPasswordPolicy           // password policy
+	verifier ports.IEmailVerificationService // email verification
+	blogs    ports.IBlogCache                // blog cache
+	sitemaps ports.ISitemapCache             // sitemap cache
+	feeds    ports.IFeedCache                // feed cache
 }
 
-func NewUserService(userRepo ports.IUserRepository, cache ports.IUserCache) ports.IUserService {
+func NewUserService(
+	userRepo ports.IUserRepository,
+	cache ports.IUserCache,
+	policy ports.IPasswordPolicy,
+	verifier ports.IEmailVerificationService,
+	blogCache ports.IBlogCache,
+	sitemapCache ports.ISitemapCache,
+	feedCache ports.IFeedCache,
+) ports.IUserService {
 	return &UserService{
-		repo:  userRepo,
-		cache: cache,
+		repo:     userRepo,
+		cache:    cache,
+		policy:   policy,
+		verifier: verifier,
+		blogs:    blogCache,
+		sitemaps: sitemapCache,
+		feeds:    feedCache,
 	}
 }
 
@@ -50,14 +68,20 @@ func (us *UserService) GetUserByID(ctx context.Context, id uuid.UUID) (*domain.U
 	return user, nil
 }
 
-func (us *UserService) CreateUser(ctx context.Context, username, password string) (*domain.User, error) {
-	hashPass, err := util.HashPassword(password)
+func (us *UserService) CreateUser(ctx context.Context, newUser *domain.User) (*domain.User, error) {
+	err := us.validatePassword(ctx, newUser.Name, newUser.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	hashPass, err := util.HashPassword(newUser.Password)
 	if err != nil {
 		return nil, domain.ErrInternal
 	}
 
 	user, err := us.repo.CreateUser(ctx, &domain.User{
-		Name:     username,
+		Name:     newUser.Name,
+		Email:    normalizeEmail(newUser.Email),
 		Password: hashPass,
 	})
 	if err != nil {
@@ -71,36 +95,69 @@ func (us *UserService) CreateUser(ctx context.Context, username, password string
 	err = us.cache.SetUser(ctx, user)
 	logOnError(err)
 
+	if user.Email != "" {
+		err = us.verifier.SendVerification(ctx, user)
+		logOnError(err)
+	}
+
 	return user, nil
 }
 
 func (us *UserService) UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
-	if user.Name == "" && user.Password == "" {
+	patch := &domain.UserPatch{}
+	if user.Name != "" {
+		patch.Name = &user.Name
+	}
+	if email := normalizeEmail(user.Email); email != "" {
+		patch.Email = &email
+	}
+
+	return us.PatchUser(ctx, user.ID, patch)
+}
+
+func (us *UserService) PatchUser(ctx context.Context, id uuid.UUID, patch *domain.UserPatch) (*domain.User, error) {
+	if patch.Name == nil && patch.Email == nil {
 		return nil, domain.ErrNoUpdatedData
 	}
 
-	existingUser, err := us.GetUserByID(ctx, user.ID)
+	existingUser, err := us.GetUserByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	if user.Password == "" && user.Name == existingUser.Name {
+	updates := map[string]interface{}{}
+	if patch.Name != nil && *patch.Name != "" && *patch.Name != existingUser.Name {
+		updates["name"] = *patch.Name
+	}
+	email := ""
+	emailChanged := false
+	if patch.Email != nil {
+		email = normalizeEmail(*patch.Email)
+		emailChanged = email != existingUser.Email
+	}
+	if emailChanged {
+		// a new address has to be verified again, a removed one is stored as NULL to keep the column unique
+		if email == "" {
+			updates["email"] = nil
+		} else {
+			updates["email"] = email
+		}
+		updates["email_verified"] = false
+	}
+
+	if len(updates) == 0 {
 		return nil, domain.ErrNoUpdatedData
 	}
 
-	hashPass := ""
-	if user.Password != "" {
-		hashPass, err = util.HashPassword(user.Password)
+	// the links sent to the previous address must not verify the new one, or a removed one
+	if emailChanged {
+		err = us.verifier.CancelVerification(ctx, id)
 		if err != nil {
-			return nil, domain.ErrInternal
+			return nil, err
 		}
 	}
 
-	updatedUser, err := us.repo.UpdateUser(ctx, &domain.User{
-		ID:       user.ID,
-		Name:     user.Name,
-		Password: hashPass,
-	})
+	updatedUser, err := us.repo.UpdateUserByMap(ctx, id, &updates)
 	if err != nil {
 		if err == domain.ErrConflictingData || err == domain.ErrNoUpdatedData {
 			return nil, err
@@ -109,15 +166,59 @@ func (us *UserService) UpdateUser(ctx context.Context, user *domain.User) (*doma
 	}
 	updatedUser.Password = ""
 
-	err = us.cache.DeleteUser(ctx, user.ID)
+	err = us.cache.SetUser(ctx, updatedUser)
 	logOnError(err)
 
-	err = us.cache.SetUser(ctx, user)
-	logOnError(err)
+	if emailChanged && email != "" {
+		err = us.verifier.SendVerification(ctx, updatedUser)
+		logOnError(err)
+	}
 
 	return updatedUser, nil
 }
 
+func (us *UserService) ChangePassword(ctx context.Context, id uuid.UUID, currentPassword, newPassword string) error {
+	user, err := us.repo.GetUserByID(ctx, id)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	err = util.ComparePassword(currentPassword, user.Password)
+	if err != nil {
+		return domain.ErrIncorrectPassword
+	}
+
+	err = us.validatePassword(ctx, user.Name, newPassword)
+	if err != nil {
+		return err
+	}
+
+	hashPass, err := util.HashPassword(newPassword)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	// bumping the token version revokes every token issued before the change
+	updatedUser, err := us.repo.UpdateCredentialsByMap(ctx, id, &map[string]interface{}{
+		"password": hashPass,
+	})
+	if err != nil {
+		if err == domain.ErrNoUpdatedData {
+			return domain.ErrDataNotFound
+		}
+		return domain.ErrInternal
+	}
+	updatedUser.Password = ""
+
+	err = us.cache.SetUser(ctx, updatedUser)
+	logOnError(err)
+
+	return nil
+}
+
 func (us *UserService) DeleteUser(ctx context.Context, id uuid.UUID) error {
 	err := us.repo.DeleteUser(ctx, id)
 
@@ -131,5 +232,31 @@ func (us *UserService) DeleteUser(ctx context.Context, id uuid.UUID) error {
 	err = us.cache.DeleteUser(ctx, id)
 	logOnError(err)
 
+	// the blogs of the user went to the trash with it, the cache does not know which ones they are
+	err = us.blogs.DeleteAllBlogs(ctx)
+	logOnError(err)
+	err = us.blogs.DeleteAllList(ctx)
+	logOnError(err)
+	err = us.blogs.DeleteAllSearchList(ctx)
+	logOnError(err)
+	err = us.sitemaps.DeleteAllSitemaps(ctx)
+	logOnError(err)
+	err = us.feeds.DeleteAllFeeds(ctx)
+	logOnError(err)
+
+	return nil
+}
+
+// validatePassword check password against the password policy,
+// policy violations are returned as is, other errors as domain.ErrInternal
+func (us *UserService) validatePassword(ctx context.Context, username, password string) error {
+	err := us.policy.Validate(ctx, username, password)
+	if err != nil {
+		if errors.Is(err, domain.ErrWeakPassword) {
+			return err
+		}
+		logger.Error(err.Error())
+		return domain.ErrInternal
+	}
 	return nil
 }
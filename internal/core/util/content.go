@@ -0,0 +1,17 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// HashContent return the hex SHA-256 of a title and a text,
+// the separator keeps a title and text pair from hashing like another split of the same bytes
+func HashContent(title, text string) string {
+	h := sha256.New()
+	h.Write([]byte(title))
+	h.Write([]byte{0})
+	h.Write([]byte(text))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
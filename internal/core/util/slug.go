@@ -0,0 +1,65 @@
+package util
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxSlugLength is the maximum length of a slug in runes
+const MaxSlugLength = 80
+
+// Slugify make the slug of a text: lower case letters and digits of any script joined by single hyphens,
+// accents are removed from latin letters, other scripts keep their marks
+func Slugify(text string) string {
+	var b strings.Builder
+	hyphen := false
+	latin := false
+	for _, r := range norm.NFKD.String(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			latin = r <= unicode.MaxASCII || unicode.Is(unicode.Latin, r)
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsMark(r):
+			// a mark belongs to the letter before it
+			if !latin && !hyphen && b.Len() > 0 {
+				b.WriteRune(r)
+			}
+		default:
+			hyphen = true
+		}
+	}
+
+	return truncateSlug(norm.NFC.String(b.String()), MaxSlugLength)
+}
+
+// IsSlug check if a text is its own slug
+func IsSlug(text string) bool {
+	return text != "" && Slugify(text) == text
+}
+
+// SlugWithSuffix return the slug with a number suffix, the slug is shortened to keep the result under MaxSlugLength
+func SlugWithSuffix(slug string, n int) string {
+	suffix := "-" + strconv.Itoa(n)
+	return truncateSlug(slug, MaxSlugLength-len(suffix)) + suffix
+}
+
+// truncateSlug cut a slug to at most max runes, at a hyphen if there is one
+func truncateSlug(slug string, max int) string {
+	runes := []rune(slug)
+	if len(runes) <= max {
+		return slug
+	}
+
+	cut := string(runes[:max])
+	if i := strings.LastIndexByte(cut, '-'); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimSuffix(cut, "-")
+}